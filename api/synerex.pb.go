@@ -83,11 +83,12 @@ func (GatewayType) EnumDescriptor() ([]byte, []int) {
 type MsgType int32
 
 const (
-	MsgType_DEMAND  MsgType = 0
-	MsgType_SUPPLY  MsgType = 1
-	MsgType_TARGET  MsgType = 2 // target for select/confirm
-	MsgType_MBUS    MsgType = 3 // mbus id for subscribe
-	MsgType_MBUSMSG MsgType = 4
+	MsgType_DEMAND    MsgType = 0
+	MsgType_SUPPLY    MsgType = 1
+	MsgType_TARGET    MsgType = 2 // target for select/confirm
+	MsgType_MBUS      MsgType = 3 // mbus id for subscribe
+	MsgType_MBUSMSG   MsgType = 4
+	MsgType_MBUSEVENT MsgType = 5 // mbus member left / closed
 )
 
 // Enum value maps for MsgType.
@@ -98,13 +99,15 @@ var (
 		2: "TARGET",
 		3: "MBUS",
 		4: "MBUSMSG",
+		5: "MBUSEVENT",
 	}
 	MsgType_value = map[string]int32{
-		"DEMAND":  0,
-		"SUPPLY":  1,
-		"TARGET":  2,
-		"MBUS":    3,
-		"MBUSMSG": 4,
+		"DEMAND":    0,
+		"SUPPLY":    1,
+		"TARGET":    2,
+		"MBUS":      3,
+		"MBUSMSG":   4,
+		"MBUSEVENT": 5,
 	}
 )
 
@@ -135,6 +138,56 @@ func (MsgType) EnumDescriptor() ([]byte, []int) {
 	return file_synerex_proto_rawDescGZIP(), []int{1}
 }
 
+// kind of Target message through gateways
+type TargetType int32
+
+const (
+	TargetType_SELECT_SUPPLY TargetType = 0 // select supply (forwarded to demand stream of the proposer)
+	TargetType_SELECT_DEMAND TargetType = 1 // select demand (forwarded to supply stream of the proposer)
+	TargetType_CONFIRM       TargetType = 2 // confirm for the forwarded select
+)
+
+// Enum value maps for TargetType.
+var (
+	TargetType_name = map[int32]string{
+		0: "SELECT_SUPPLY",
+		1: "SELECT_DEMAND",
+		2: "CONFIRM",
+	}
+	TargetType_value = map[string]int32{
+		"SELECT_SUPPLY": 0,
+		"SELECT_DEMAND": 1,
+		"CONFIRM":       2,
+	}
+)
+
+func (x TargetType) Enum() *TargetType {
+	p := new(TargetType)
+	*p = x
+	return p
+}
+
+func (x TargetType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TargetType) Descriptor() protoreflect.EnumDescriptor {
+	return file_synerex_proto_enumTypes[2].Descriptor()
+}
+
+func (TargetType) Type() protoreflect.EnumType {
+	return &file_synerex_proto_enumTypes[2]
+}
+
+func (x TargetType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TargetType.Descriptor instead.
+func (TargetType) EnumDescriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{2}
+}
+
 type MbusOpt_MbusType int32
 
 const (
@@ -165,11 +218,11 @@ func (x MbusOpt_MbusType) String() string {
 }
 
 func (MbusOpt_MbusType) Descriptor() protoreflect.EnumDescriptor {
-	return file_synerex_proto_enumTypes[2].Descriptor()
+	return file_synerex_proto_enumTypes[3].Descriptor()
 }
 
 func (MbusOpt_MbusType) Type() protoreflect.EnumType {
-	return &file_synerex_proto_enumTypes[2]
+	return &file_synerex_proto_enumTypes[3]
 }
 
 func (x MbusOpt_MbusType) Number() protoreflect.EnumNumber {
@@ -178,7 +231,7 @@ func (x MbusOpt_MbusType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MbusOpt_MbusType.Descriptor instead.
 func (MbusOpt_MbusType) EnumDescriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{9, 0}
+	return file_synerex_proto_rawDescGZIP(), []int{11, 0}
 }
 
 type MbusState_MbusStatus int32
@@ -217,11 +270,11 @@ func (x MbusState_MbusStatus) String() string {
 }
 
 func (MbusState_MbusStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_synerex_proto_enumTypes[3].Descriptor()
+	return file_synerex_proto_enumTypes[4].Descriptor()
 }
 
 func (MbusState_MbusStatus) Type() protoreflect.EnumType {
-	return &file_synerex_proto_enumTypes[3]
+	return &file_synerex_proto_enumTypes[4]
 }
 
 func (x MbusState_MbusStatus) Number() protoreflect.EnumNumber {
@@ -230,7 +283,59 @@ func (x MbusState_MbusStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MbusState_MbusStatus.Descriptor instead.
 func (MbusState_MbusStatus) EnumDescriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{10, 0}
+	return file_synerex_proto_rawDescGZIP(), []int{13, 0}
+}
+
+type MbusEvent_EventType int32
+
+const (
+	MbusEvent_STATE  MbusEvent_EventType = 0 // current state at the start of watch
+	MbusEvent_JOINED MbusEvent_EventType = 1 // client subscribed the mbus
+	MbusEvent_LEFT   MbusEvent_EventType = 2 // client unsubscribed the mbus
+	MbusEvent_CLOSED MbusEvent_EventType = 3 // mbus is closed (last event)
+)
+
+// Enum value maps for MbusEvent_EventType.
+var (
+	MbusEvent_EventType_name = map[int32]string{
+		0: "STATE",
+		1: "JOINED",
+		2: "LEFT",
+		3: "CLOSED",
+	}
+	MbusEvent_EventType_value = map[string]int32{
+		"STATE":  0,
+		"JOINED": 1,
+		"LEFT":   2,
+		"CLOSED": 3,
+	}
+)
+
+func (x MbusEvent_EventType) Enum() *MbusEvent_EventType {
+	p := new(MbusEvent_EventType)
+	*p = x
+	return p
+}
+
+func (x MbusEvent_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MbusEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_synerex_proto_enumTypes[5].Descriptor()
+}
+
+func (MbusEvent_EventType) Type() protoreflect.EnumType {
+	return &file_synerex_proto_enumTypes[5]
+}
+
+func (x MbusEvent_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MbusEvent_EventType.Descriptor instead.
+func (MbusEvent_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{14, 0}
 }
 
 type Response struct {
@@ -238,8 +343,9 @@ type Response struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ok  bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
-	Err string `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	Ok         bool               `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Err        string             `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	RetryAfter *duration.Duration `protobuf:"bytes,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"` // hint for retry when rate limited
 }
 
 func (x *Response) Reset() {
@@ -288,6 +394,101 @@ func (x *Response) GetErr() string {
 	return ""
 }
 
+func (x *Response) GetRetryAfter() *duration.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+// PublishAck is sent periodically on Publish streams (counts are totals of the stream)
+type PublishAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accepted   uint64             `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`                      // messages delivered to all subscribers
+	Dropped    uint64             `protobuf:"varint,2,opt,name=dropped,proto3" json:"dropped,omitempty"`                        // messages rejected (rate limit) or dropped for some subscribers
+	LastId     uint64             `protobuf:"fixed64,3,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"`           // id of the last processed message
+	Err        string             `protobuf:"bytes,4,opt,name=err,proto3" json:"err,omitempty"`                                 // reason of the last drop or denial in this ack window
+	RetryAfter *duration.Duration `protobuf:"bytes,5,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"` // hint for back off when rate limited
+	Denied     uint64             `protobuf:"varint,6,opt,name=denied,proto3" json:"denied,omitempty"`                          // messages denied by auth or acl (not counted in dropped)
+}
+
+func (x *PublishAck) Reset() {
+	*x = PublishAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synerex_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishAck) ProtoMessage() {}
+
+func (x *PublishAck) ProtoReflect() protoreflect.Message {
+	mi := &file_synerex_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishAck.ProtoReflect.Descriptor instead.
+func (*PublishAck) Descriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublishAck) GetAccepted() uint64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *PublishAck) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *PublishAck) GetLastId() uint64 {
+	if x != nil {
+		return x.LastId
+	}
+	return 0
+}
+
+func (x *PublishAck) GetErr() string {
+	if x != nil {
+		return x.Err
+	}
+	return ""
+}
+
+func (x *PublishAck) GetRetryAfter() *duration.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *PublishAck) GetDenied() uint64 {
+	if x != nil {
+		return x.Denied
+	}
+	return 0
+}
+
 type ConfirmResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -302,7 +503,7 @@ type ConfirmResponse struct {
 func (x *ConfirmResponse) Reset() {
 	*x = ConfirmResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[1]
+		mi := &file_synerex_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -315,7 +516,7 @@ func (x *ConfirmResponse) String() string {
 func (*ConfirmResponse) ProtoMessage() {}
 
 func (x *ConfirmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[1]
+	mi := &file_synerex_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,7 +529,7 @@ func (x *ConfirmResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfirmResponse.ProtoReflect.Descriptor instead.
 func (*ConfirmResponse) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{1}
+	return file_synerex_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ConfirmResponse) GetOk() bool {
@@ -365,12 +566,13 @@ type Content struct {
 	unknownFields protoimpl.UnknownFields
 
 	Entity []byte `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Chunk  *Chunk `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"` // set if entity is a part of large content (split by sxutil)
 }
 
 func (x *Content) Reset() {
 	*x = Content{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[2]
+		mi := &file_synerex_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -383,7 +585,7 @@ func (x *Content) String() string {
 func (*Content) ProtoMessage() {}
 
 func (x *Content) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[2]
+	mi := &file_synerex_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,7 +598,7 @@ func (x *Content) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Content.ProtoReflect.Descriptor instead.
 func (*Content) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{2}
+	return file_synerex_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Content) GetEntity() []byte {
@@ -406,6 +608,86 @@ func (x *Content) GetEntity() []byte {
 	return nil
 }
 
+func (x *Content) GetChunk() *Chunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// Chunk is a position of the part in the original content.
+// Each chunk is sent as a message with its own id, and relayed in order by the server.
+type Chunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MsgId uint64 `protobuf:"fixed64,1,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"` // id of the original message (reassembled message has this id)
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`               // 0 origin
+	Total uint32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`               // number of chunks
+	Size  uint64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                 // size of the original entity
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synerex_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_synerex_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Chunk) GetMsgId() uint64 {
+	if x != nil {
+		return x.MsgId
+	}
+	return 0
+}
+
+func (x *Chunk) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Chunk) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Chunk) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 type Supply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -418,14 +700,16 @@ type Supply struct {
 	SupplyName  string               `protobuf:"bytes,5,opt,name=supply_name,json=supplyName,proto3" json:"supply_name,omitempty"`
 	Ts          *timestamp.Timestamp `protobuf:"bytes,6,opt,name=ts,proto3" json:"ts,omitempty"`
 	ArgJson     string               `protobuf:"bytes,7,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"`
-	MbusId      uint64               `protobuf:"fixed64,8,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"` // new mbus id for select demand.
-	Cdata       *Content             `protobuf:"bytes,9,opt,name=cdata,proto3" json:"cdata,omitempty"`                   // content data
+	MbusId      uint64               `protobuf:"fixed64,8,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`               // new mbus id for select demand.
+	Cdata       *Content             `protobuf:"bytes,9,opt,name=cdata,proto3" json:"cdata,omitempty"`                                 // content data
+	Seq         uint64               `protobuf:"varint,10,opt,name=seq,proto3" json:"seq,omitempty"`                                   // sequence number in the channel (set by server, upper 32 bits are server epoch)
+	TraceParent string               `protobuf:"bytes,11,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"` // W3C traceparent of the server span (set by server)
 }
 
 func (x *Supply) Reset() {
 	*x = Supply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[3]
+		mi := &file_synerex_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -438,7 +722,7 @@ func (x *Supply) String() string {
 func (*Supply) ProtoMessage() {}
 
 func (x *Supply) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[3]
+	mi := &file_synerex_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -451,7 +735,7 @@ func (x *Supply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Supply.ProtoReflect.Descriptor instead.
 func (*Supply) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{3}
+	return file_synerex_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Supply) GetId() uint64 {
@@ -517,6 +801,20 @@ func (x *Supply) GetCdata() *Content {
 	return nil
 }
 
+func (x *Supply) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Supply) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
 type Demand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -529,14 +827,16 @@ type Demand struct {
 	DemandName  string               `protobuf:"bytes,5,opt,name=demand_name,json=demandName,proto3" json:"demand_name,omitempty"`
 	Ts          *timestamp.Timestamp `protobuf:"bytes,6,opt,name=ts,proto3" json:"ts,omitempty"`
 	ArgJson     string               `protobuf:"bytes,7,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"`
-	MbusId      uint64               `protobuf:"fixed64,8,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"` // new mbus id for select supply...
-	Cdata       *Content             `protobuf:"bytes,9,opt,name=cdata,proto3" json:"cdata,omitempty"`                   // content data
+	MbusId      uint64               `protobuf:"fixed64,8,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`               // new mbus id for select supply...
+	Cdata       *Content             `protobuf:"bytes,9,opt,name=cdata,proto3" json:"cdata,omitempty"`                                 // content data
+	Seq         uint64               `protobuf:"varint,10,opt,name=seq,proto3" json:"seq,omitempty"`                                   // sequence number in the channel (set by server, upper 32 bits are server epoch)
+	TraceParent string               `protobuf:"bytes,11,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"` // W3C traceparent of the server span (set by server)
 }
 
 func (x *Demand) Reset() {
 	*x = Demand{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[4]
+		mi := &file_synerex_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -549,7 +849,7 @@ func (x *Demand) String() string {
 func (*Demand) ProtoMessage() {}
 
 func (x *Demand) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[4]
+	mi := &file_synerex_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -562,7 +862,7 @@ func (x *Demand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Demand.ProtoReflect.Descriptor instead.
 func (*Demand) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{4}
+	return file_synerex_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Demand) GetId() uint64 {
@@ -628,6 +928,20 @@ func (x *Demand) GetCdata() *Content {
 	return nil
 }
 
+func (x *Demand) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Demand) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
 type Target struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -638,13 +952,14 @@ type Target struct {
 	TargetId    uint64             `protobuf:"fixed64,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`         // for target
 	ChannelType uint32             `protobuf:"varint,4,opt,name=channel_type,json=channelType,proto3" json:"channel_type,omitempty"` // channel type
 	Wait        *duration.Duration `protobuf:"bytes,5,opt,name=wait,proto3" json:"wait,omitempty"`
-	MbusId      uint64             `protobuf:"fixed64,6,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"` // if you need message bus, set Mbus with mbus_id = 1
+	MbusId      uint64             `protobuf:"fixed64,6,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`              // if you need message bus, set Mbus with mbus_id = 1
+	TraceParent string             `protobuf:"bytes,7,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"` // W3C traceparent of the server span (set by server)
 }
 
 func (x *Target) Reset() {
 	*x = Target{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[5]
+		mi := &file_synerex_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -657,7 +972,7 @@ func (x *Target) String() string {
 func (*Target) ProtoMessage() {}
 
 func (x *Target) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[5]
+	mi := &file_synerex_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +985,7 @@ func (x *Target) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Target.ProtoReflect.Descriptor instead.
 func (*Target) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{5}
+	return file_synerex_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Target) GetId() uint64 {
@@ -715,6 +1030,13 @@ func (x *Target) GetMbusId() uint64 {
 	return 0
 }
 
+func (x *Target) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
 type Channel struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -723,12 +1045,14 @@ type Channel struct {
 	ClientId    uint64 `protobuf:"fixed64,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	ChannelType uint32 `protobuf:"varint,2,opt,name=channel_type,json=channelType,proto3" json:"channel_type,omitempty"` // channel type
 	ArgJson     string `protobuf:"bytes,3,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"`              // for Channel Argument
+	BufferSize  uint32 `protobuf:"varint,4,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`    // requested subscriber buffer size (0 for server default)
+	ResumeAfter uint64 `protobuf:"varint,5,opt,name=resume_after,json=resumeAfter,proto3" json:"resume_after,omitempty"` // replay messages after this sequence number (0 for no replay)
 }
 
 func (x *Channel) Reset() {
 	*x = Channel{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[6]
+		mi := &file_synerex_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -741,7 +1065,7 @@ func (x *Channel) String() string {
 func (*Channel) ProtoMessage() {}
 
 func (x *Channel) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[6]
+	mi := &file_synerex_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -754,7 +1078,7 @@ func (x *Channel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Channel.ProtoReflect.Descriptor instead.
 func (*Channel) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{6}
+	return file_synerex_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Channel) GetClientId() uint64 {
@@ -778,20 +1102,35 @@ func (x *Channel) GetArgJson() string {
 	return ""
 }
 
+func (x *Channel) GetBufferSize() uint32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+func (x *Channel) GetResumeAfter() uint64 {
+	if x != nil {
+		return x.ResumeAfter
+	}
+	return 0
+}
+
 type Mbus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ClientId uint64 `protobuf:"fixed64,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	MbusId   uint64 `protobuf:"fixed64,2,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
-	ArgJson  string `protobuf:"bytes,3,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"` // for mbus description
+	ClientId uint64   `protobuf:"fixed64,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	MbusId   uint64   `protobuf:"fixed64,2,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
+	ArgJson  string   `protobuf:"bytes,3,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"` // for mbus description
+	Opt      *MbusOpt `protobuf:"bytes,4,opt,name=opt,proto3" json:"opt,omitempty"`                        // type and members of the mbus (set by server for gateways)
 }
 
 func (x *Mbus) Reset() {
 	*x = Mbus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[7]
+		mi := &file_synerex_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -804,7 +1143,7 @@ func (x *Mbus) String() string {
 func (*Mbus) ProtoMessage() {}
 
 func (x *Mbus) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[7]
+	mi := &file_synerex_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,7 +1156,7 @@ func (x *Mbus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Mbus.ProtoReflect.Descriptor instead.
 func (*Mbus) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{7}
+	return file_synerex_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Mbus) GetClientId() uint64 {
@@ -841,25 +1180,33 @@ func (x *Mbus) GetArgJson() string {
 	return ""
 }
 
+func (x *Mbus) GetOpt() *MbusOpt {
+	if x != nil {
+		return x.Opt
+	}
+	return nil
+}
+
 type MbusMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MsgId    uint64   `protobuf:"fixed64,1,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"` // if 0 for close message
-	SenderId uint64   `protobuf:"fixed64,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	TargetId uint64   `protobuf:"fixed64,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // for target // if 0 for broadcast in mbus
-	MbusId   uint64   `protobuf:"fixed64,4,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
-	MsgType  uint32   `protobuf:"varint,5,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"` // for message type
-	MsgInfo  string   `protobuf:"bytes,6,opt,name=msg_info,json=msgInfo,proto3" json:"msg_info,omitempty"`  // for abstract information
-	ArgJson  string   `protobuf:"bytes,7,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"`
-	Cdata    *Content `protobuf:"bytes,8,opt,name=cdata,proto3" json:"cdata,omitempty"` // content data (enbedded from v0.4.0)
+	MsgId       uint64   `protobuf:"fixed64,1,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"` // if 0 for close message
+	SenderId    uint64   `protobuf:"fixed64,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	TargetId    uint64   `protobuf:"fixed64,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // for target // if 0 for broadcast in mbus
+	MbusId      uint64   `protobuf:"fixed64,4,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
+	MsgType     uint32   `protobuf:"varint,5,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"` // for message type
+	MsgInfo     string   `protobuf:"bytes,6,opt,name=msg_info,json=msgInfo,proto3" json:"msg_info,omitempty"`  // for abstract information
+	ArgJson     string   `protobuf:"bytes,7,opt,name=arg_json,json=argJson,proto3" json:"arg_json,omitempty"`
+	Cdata       *Content `protobuf:"bytes,8,opt,name=cdata,proto3" json:"cdata,omitempty"`                                // content data (enbedded from v0.4.0)
+	TraceParent string   `protobuf:"bytes,9,opt,name=trace_parent,json=traceParent,proto3" json:"trace_parent,omitempty"` // W3C traceparent of the server span (set by server)
 }
 
 func (x *MbusMsg) Reset() {
 	*x = MbusMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[8]
+		mi := &file_synerex_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -872,7 +1219,7 @@ func (x *MbusMsg) String() string {
 func (*MbusMsg) ProtoMessage() {}
 
 func (x *MbusMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[8]
+	mi := &file_synerex_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,7 +1232,7 @@ func (x *MbusMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MbusMsg.ProtoReflect.Descriptor instead.
 func (*MbusMsg) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{8}
+	return file_synerex_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MbusMsg) GetMsgId() uint64 {
@@ -944,6 +1291,13 @@ func (x *MbusMsg) GetCdata() *Content {
 	return nil
 }
 
+func (x *MbusMsg) GetTraceParent() string {
+	if x != nil {
+		return x.TraceParent
+	}
+	return ""
+}
+
 // options for creating Mbus from v0.4.0
 type MbusOpt struct {
 	state         protoimpl.MessageState
@@ -951,13 +1305,14 @@ type MbusOpt struct {
 	unknownFields protoimpl.UnknownFields
 
 	MbusType    MbusOpt_MbusType `protobuf:"varint,1,opt,name=mbus_type,json=mbusType,proto3,enum=api.MbusOpt_MbusType" json:"mbus_type,omitempty"`
-	Subscribers []uint64         `protobuf:"fixed64,2,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"` // use this for limiting subscribers
+	Subscribers []uint64         `protobuf:"fixed64,2,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"`    // use this for limiting subscribers
+	ClientId    uint64           `protobuf:"fixed64,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // creator (owner) of the mbus (required for PRIVATE)
 }
 
 func (x *MbusOpt) Reset() {
 	*x = MbusOpt{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[9]
+		mi := &file_synerex_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -970,7 +1325,7 @@ func (x *MbusOpt) String() string {
 func (*MbusOpt) ProtoMessage() {}
 
 func (x *MbusOpt) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[9]
+	mi := &file_synerex_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -983,7 +1338,7 @@ func (x *MbusOpt) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MbusOpt.ProtoReflect.Descriptor instead.
 func (*MbusOpt) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{9}
+	return file_synerex_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *MbusOpt) GetMbusType() MbusOpt_MbusType {
@@ -1000,6 +1355,77 @@ func (x *MbusOpt) GetSubscribers() []uint64 {
 	return nil
 }
 
+func (x *MbusOpt) GetClientId() uint64 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+// members of private mbus
+type MbusMembers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClientId uint64   `protobuf:"fixed64,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // owner of the mbus
+	MbusId   uint64   `protobuf:"fixed64,2,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
+	Members  []uint64 `protobuf:"fixed64,3,rep,packed,name=members,proto3" json:"members,omitempty"`
+}
+
+func (x *MbusMembers) Reset() {
+	*x = MbusMembers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synerex_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MbusMembers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MbusMembers) ProtoMessage() {}
+
+func (x *MbusMembers) ProtoReflect() protoreflect.Message {
+	mi := &file_synerex_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MbusMembers.ProtoReflect.Descriptor instead.
+func (*MbusMembers) Descriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MbusMembers) GetClientId() uint64 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *MbusMembers) GetMbusId() uint64 {
+	if x != nil {
+		return x.MbusId
+	}
+	return 0
+}
+
+func (x *MbusMembers) GetMembers() []uint64 {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
 // message for obtaining mbus state from 0.4.0
 type MbusState struct {
 	state         protoimpl.MessageState
@@ -1014,7 +1440,7 @@ type MbusState struct {
 func (x *MbusState) Reset() {
 	*x = MbusState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[10]
+		mi := &file_synerex_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1027,7 +1453,7 @@ func (x *MbusState) String() string {
 func (*MbusState) ProtoMessage() {}
 
 func (x *MbusState) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[10]
+	mi := &file_synerex_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1040,7 +1466,7 @@ func (x *MbusState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MbusState.ProtoReflect.Descriptor instead.
 func (*MbusState) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{10}
+	return file_synerex_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *MbusState) GetMbusId() uint64 {
@@ -1064,6 +1490,78 @@ func (x *MbusState) GetSubscribers() []uint64 {
 	return nil
 }
 
+// member event of mbus
+type MbusEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MbusId      uint64              `protobuf:"fixed64,1,opt,name=mbus_id,json=mbusId,proto3" json:"mbus_id,omitempty"`
+	ClientId    uint64              `protobuf:"fixed64,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // joined/left client (0 for STATE, CLOSED)
+	EventType   MbusEvent_EventType `protobuf:"varint,3,opt,name=event_type,json=eventType,proto3,enum=api.MbusEvent_EventType" json:"event_type,omitempty"`
+	Subscribers []uint64            `protobuf:"fixed64,4,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"` // current subscribers after the event
+}
+
+func (x *MbusEvent) Reset() {
+	*x = MbusEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_synerex_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MbusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MbusEvent) ProtoMessage() {}
+
+func (x *MbusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_synerex_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MbusEvent.ProtoReflect.Descriptor instead.
+func (*MbusEvent) Descriptor() ([]byte, []int) {
+	return file_synerex_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MbusEvent) GetMbusId() uint64 {
+	if x != nil {
+		return x.MbusId
+	}
+	return 0
+}
+
+func (x *MbusEvent) GetClientId() uint64 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *MbusEvent) GetEventType() MbusEvent_EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return MbusEvent_STATE
+}
+
+func (x *MbusEvent) GetSubscribers() []uint64 {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
 type GatewayInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1071,13 +1569,13 @@ type GatewayInfo struct {
 
 	ClientId    uint64      `protobuf:"fixed64,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // client_id (snowflake) of gateway
 	GatewayType GatewayType `protobuf:"varint,2,opt,name=gateway_type,json=gatewayType,proto3,enum=api.GatewayType" json:"gateway_type,omitempty"`
-	Channels    []uint32    `protobuf:"varint,3,rep,packed,name=channels,proto3" json:"channels,omitempty"` // which channel for forward
+	Channels    []uint32    `protobuf:"varint,3,rep,packed,name=channels,proto3" json:"channels,omitempty"` // which channel for forward (empty for all channels)
 }
 
 func (x *GatewayInfo) Reset() {
 	*x = GatewayInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[11]
+		mi := &file_synerex_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1090,7 +1588,7 @@ func (x *GatewayInfo) String() string {
 func (*GatewayInfo) ProtoMessage() {}
 
 func (x *GatewayInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[11]
+	mi := &file_synerex_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1103,7 +1601,7 @@ func (x *GatewayInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GatewayInfo.ProtoReflect.Descriptor instead.
 func (*GatewayInfo) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{11}
+	return file_synerex_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GatewayInfo) GetClientId() uint64 {
@@ -1141,13 +1639,18 @@ type GatewayMsg struct {
 	//	*GatewayMsg_Target
 	//	*GatewayMsg_Mbus
 	//	*GatewayMsg_MbusMsg
-	MsgOneof isGatewayMsg_MsgOneof `protobuf_oneof:"msg_oneof"`
+	//	*GatewayMsg_MbusEvent
+	MsgOneof   isGatewayMsg_MsgOneof `protobuf_oneof:"msg_oneof"`
+	TargetType TargetType            `protobuf:"varint,8,opt,name=target_type,json=targetType,proto3,enum=api.TargetType" json:"target_type,omitempty"` // only for msg_type = TARGET
+	HopCount   uint32                `protobuf:"varint,10,opt,name=hop_count,json=hopCount,proto3" json:"hop_count,omitempty"`                          // number of gateways passed
+	Visited    []uint64              `protobuf:"fixed64,11,rep,packed,name=visited,proto3" json:"visited,omitempty"`                                    // synerex ids of servers passed (including source)
+	GatewayId  uint64                `protobuf:"fixed64,12,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`                      // client_id of the gateway calling ForwardToGateway
 }
 
 func (x *GatewayMsg) Reset() {
 	*x = GatewayMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[12]
+		mi := &file_synerex_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1160,7 +1663,7 @@ func (x *GatewayMsg) String() string {
 func (*GatewayMsg) ProtoMessage() {}
 
 func (x *GatewayMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[12]
+	mi := &file_synerex_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1173,7 +1676,7 @@ func (x *GatewayMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GatewayMsg.ProtoReflect.Descriptor instead.
 func (*GatewayMsg) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{12}
+	return file_synerex_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GatewayMsg) GetSrcSynerexId() uint64 {
@@ -1225,11 +1728,46 @@ func (x *GatewayMsg) GetMbus() *Mbus {
 	return nil
 }
 
-func (x *GatewayMsg) GetMbusMsg() *MbusMsg {
-	if x, ok := x.GetMsgOneof().(*GatewayMsg_MbusMsg); ok {
-		return x.MbusMsg
+func (x *GatewayMsg) GetMbusMsg() *MbusMsg {
+	if x, ok := x.GetMsgOneof().(*GatewayMsg_MbusMsg); ok {
+		return x.MbusMsg
+	}
+	return nil
+}
+
+func (x *GatewayMsg) GetMbusEvent() *MbusEvent {
+	if x, ok := x.GetMsgOneof().(*GatewayMsg_MbusEvent); ok {
+		return x.MbusEvent
+	}
+	return nil
+}
+
+func (x *GatewayMsg) GetTargetType() TargetType {
+	if x != nil {
+		return x.TargetType
+	}
+	return TargetType_SELECT_SUPPLY
+}
+
+func (x *GatewayMsg) GetHopCount() uint32 {
+	if x != nil {
+		return x.HopCount
+	}
+	return 0
+}
+
+func (x *GatewayMsg) GetVisited() []uint64 {
+	if x != nil {
+		return x.Visited
+	}
+	return nil
+}
+
+func (x *GatewayMsg) GetGatewayId() uint64 {
+	if x != nil {
+		return x.GatewayId
 	}
-	return nil
+	return 0
 }
 
 type isGatewayMsg_MsgOneof interface {
@@ -1256,6 +1794,10 @@ type GatewayMsg_MbusMsg struct {
 	MbusMsg *MbusMsg `protobuf:"bytes,7,opt,name=mbus_msg,json=mbusMsg,proto3,oneof"`
 }
 
+type GatewayMsg_MbusEvent struct {
+	MbusEvent *MbusEvent `protobuf:"bytes,9,opt,name=mbus_event,json=mbusEvent,proto3,oneof"`
+}
+
 func (*GatewayMsg_Demand) isGatewayMsg_MsgOneof() {}
 
 func (*GatewayMsg_Supply) isGatewayMsg_MsgOneof() {}
@@ -1266,6 +1808,8 @@ func (*GatewayMsg_Mbus) isGatewayMsg_MsgOneof() {}
 
 func (*GatewayMsg_MbusMsg) isGatewayMsg_MsgOneof() {}
 
+func (*GatewayMsg_MbusEvent) isGatewayMsg_MsgOneof() {}
+
 type ProviderID struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1278,7 +1822,7 @@ type ProviderID struct {
 func (x *ProviderID) Reset() {
 	*x = ProviderID{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_synerex_proto_msgTypes[13]
+		mi := &file_synerex_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1291,7 +1835,7 @@ func (x *ProviderID) String() string {
 func (*ProviderID) ProtoMessage() {}
 
 func (x *ProviderID) ProtoReflect() protoreflect.Message {
-	mi := &file_synerex_proto_msgTypes[13]
+	mi := &file_synerex_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1304,7 +1848,7 @@ func (x *ProviderID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProviderID.ProtoReflect.Descriptor instead.
 func (*ProviderID) Descriptor() ([]byte, []int) {
-	return file_synerex_proto_rawDescGZIP(), []int{13}
+	return file_synerex_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ProviderID) GetClientId() uint64 {
@@ -1329,10 +1873,26 @@ var file_synerex_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2c, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x68, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f,
 	0x6b, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
-	0x65, 0x72, 0x72, 0x22, 0x7b, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x52, 0x65,
+	0x65, 0x72, 0x72, 0x12, 0x3a, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22,
+	0xc1, 0x01, 0x0a, 0x0a, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x6b, 0x12, 0x1a,
+	0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x64, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x72, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x12,
+	0x3a, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x65, 0x6e, 0x69, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x64, 0x65, 0x6e,
+	0x69, 0x65, 0x64, 0x22, 0x7b, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x52, 0x65,
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69,
 	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12,
@@ -1340,204 +1900,284 @@ var file_synerex_proto_rawDesc = []byte{
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
 	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x12, 0x10,
 	0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72,
-	0x22, 0x21, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65,
+	0x22, 0x43, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65,
 	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x65, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x22, 0x9a, 0x02, 0x0a, 0x06, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b,
-	0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
-	0x75, 0x70, 0x70, 0x6c, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x02,
-	0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f,
-	0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a,
-	0x73, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x05,
-	0x63, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0x9a, 0x02, 0x0a, 0x06, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73,
-	0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x6d, 0x61,
-	0x6e, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64,
-	0x65, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x73, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x02, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f,
-	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e,
-	0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x22, 0xbd, 0x01,
-	0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x06, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64,
-	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e,
-	0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f,
-	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04,
-	0x77, 0x61, 0x69, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x22, 0x64, 0x0a,
+	0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x5e, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x15,
+	0x0a, 0x06, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x05,
+	0x6d, 0x73, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xcf, 0x02, 0x0a, 0x06, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a,
+	0x0a, 0x02, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72,
+	0x67, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72,
+	0x67, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x22,
+	0x0a, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x63, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x03, 0x73, 0x65, 0x71, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x63,
+	0x65, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x22, 0xcf, 0x02, 0x0a, 0x06, 0x44, 0x65, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x06, 0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x2a, 0x0a, 0x02, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f,
+	0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x63,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x72,
+	0x61, 0x63, 0x65, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x22, 0xe0, 0x01, 0x0a, 0x06, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x21,
+	0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x2d, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x72, 0x61,
+	0x63, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x22, 0xa8, 0x01, 0x0a,
 	0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65,
 	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x63, 0x6c, 0x69,
 	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
 	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x61,
 	0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f,
 	0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a,
-	0x73, 0x6f, 0x6e, 0x22, 0x57, 0x0a, 0x04, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63,
-	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08,
-	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73,
-	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49,
-	0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0xe8, 0x01, 0x0a,
-	0x07, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x06, 0x6d, 0x73, 0x67, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x05, 0x6d, 0x73, 0x67, 0x49, 0x64, 0x12,
-	0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52,
-	0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75,
-	0x73, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73,
-	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a,
-	0x08, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x6d, 0x73, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f,
-	0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a,
-	0x73, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
-	0x52, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x22, 0x84, 0x01, 0x0a, 0x07, 0x4d, 0x62, 0x75, 0x73,
-	0x4f, 0x70, 0x74, 0x12, 0x32, 0x0a, 0x09, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75,
-	0x73, 0x4f, 0x70, 0x74, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x6d,
-	0x62, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x06, 0x52, 0x0b, 0x73, 0x75,
-	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x22, 0x23, 0x0a, 0x08, 0x4d, 0x62, 0x75,
-	0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10,
-	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x10, 0x01, 0x22, 0xc1,
-	0x01, 0x0a, 0x09, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07,
-	0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d,
-	0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x06, 0x52, 0x0b, 0x73,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x22, 0x46, 0x0a, 0x0a, 0x4d, 0x62,
-	0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x49, 0x4e, 0x54, 0x49,
-	0x41, 0x4c, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x55, 0x42, 0x53,
-	0x43, 0x52, 0x49, 0x42, 0x45, 0x52, 0x53, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x4f,
-	0x53, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44,
-	0x10, 0x03, 0x22, 0x7b, 0x0a, 0x0b, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x6e, 0x66,
-	0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x33,
-	0x0a, 0x0c, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22,
-	0xa9, 0x02, 0x0a, 0x0a, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x12, 0x24,
-	0x0a, 0x0e, 0x73, 0x72, 0x63, 0x5f, 0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x0c, 0x73, 0x72, 0x63, 0x53, 0x79, 0x6e, 0x65, 0x72,
-	0x65, 0x78, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x73, 0x67,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x25, 0x0a,
-	0x06, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x06, 0x64, 0x65,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c,
-	0x79, 0x48, 0x00, 0x52, 0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x00, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x12, 0x1f, 0x0a, 0x04, 0x6d, 0x62, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x48, 0x00, 0x52, 0x04, 0x6d,
-	0x62, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x6d, 0x73, 0x67, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73,
-	0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x42, 0x0b,
-	0x0a, 0x09, 0x6d, 0x73, 0x67, 0x5f, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x22, 0x44, 0x0a, 0x0a, 0x50,
-	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73,
-	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f,
-	0x6e, 0x2a, 0x3f, 0x0a, 0x0b, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x11, 0x0a, 0x0d, 0x42, 0x49, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x41,
-	0x4c, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x4f, 0x4e, 0x4c,
-	0x59, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x4f, 0x4e, 0x4c, 0x59,
-	0x10, 0x02, 0x2a, 0x44, 0x0a, 0x07, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a,
-	0x06, 0x44, 0x45, 0x4d, 0x41, 0x4e, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x55, 0x50,
-	0x50, 0x4c, 0x59, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x10,
-	0x02, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x42, 0x55, 0x53, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x4d,
-	0x42, 0x55, 0x53, 0x4d, 0x53, 0x47, 0x10, 0x04, 0x32, 0xa6, 0x07, 0x0a, 0x07, 0x53, 0x79, 0x6e,
-	0x65, 0x72, 0x65, 0x78, 0x12, 0x2c, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x44, 0x65,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e,
-	0x64, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x53, 0x75, 0x70, 0x70,
-	0x6c, 0x79, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x1a,
-	0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x2d, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x44, 0x65, 0x6d, 0x61, 0x6e,
-	0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x0d,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x2d, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79,
-	0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x1a, 0x0d, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33,
-	0x0a, 0x0c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0b,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x0c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x44, 0x65, 0x6d,
-	0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x73, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22, 0x77, 0x0a, 0x04, 0x4d, 0x62, 0x75, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x06, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07,
+	0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d,
+	0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e,
+	0x12, 0x1e, 0x0a, 0x03, 0x6f, 0x70, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4f, 0x70, 0x74, 0x52, 0x03, 0x6f, 0x70, 0x74,
+	0x22, 0x8b, 0x02, 0x0a, 0x07, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x15, 0x0a, 0x06,
+	0x6d, 0x73, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x05, 0x6d, 0x73,
+	0x67, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x06, 0x52, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x06, 0x52, 0x06,
+	0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x61, 0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x63, 0x64, 0x61, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x72, 0x61, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x22, 0xa1,
+	0x01, 0x0a, 0x07, 0x4d, 0x62, 0x75, 0x73, 0x4f, 0x70, 0x74, 0x12, 0x32, 0x0a, 0x09, 0x6d, 0x62,
+	0x75, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4f, 0x70, 0x74, 0x2e, 0x4d, 0x62, 0x75, 0x73,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x6d, 0x62, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x06, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x06, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x23, 0x0a,
+	0x08, 0x4d, 0x62, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x55, 0x42,
+	0x4c, 0x49, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45,
+	0x10, 0x01, 0x22, 0x5d, 0x0a, 0x0b, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x17,
+	0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52,
+	0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65,
+	0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x06, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x22, 0xc1, 0x01, 0x0a, 0x09, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d,
+	0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x73,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x06,
+	0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x73, 0x22, 0x46, 0x0a,
+	0x0a, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x49,
+	0x4e, 0x54, 0x49, 0x41, 0x4c, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53,
+	0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x52, 0x53, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06,
+	0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41,
+	0x4c, 0x49, 0x44, 0x10, 0x03, 0x22, 0xd6, 0x01, 0x0a, 0x09, 0x4d, 0x62, 0x75, 0x73, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x06, 0x52, 0x06, 0x6d, 0x62, 0x75, 0x73, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06, 0x52,
+	0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x0a, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x06, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x72, 0x73, 0x22, 0x38, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
+	0x4a, 0x4f, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54,
+	0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x03, 0x22, 0x7b,
+	0x0a, 0x0b, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x0c, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0b, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0d, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0xe2, 0x03, 0x0a, 0x0a,
+	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x72,
+	0x63, 0x5f, 0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x06, 0x52, 0x0c, 0x73, 0x72, 0x63, 0x53, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x49, 0x64,
+	0x12, 0x27, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x64, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x06, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x25, 0x0a, 0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52,
+	0x06, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x48, 0x00, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x1f,
+	0x0a, 0x04, 0x6d, 0x62, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x62, 0x75, 0x73, 0x12,
+	0x29, 0x0a, 0x08, 0x6d, 0x62, 0x75, 0x73, 0x5f, 0x6d, 0x73, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x48,
+	0x00, 0x52, 0x07, 0x6d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x2f, 0x0a, 0x0a, 0x6d, 0x62,
+	0x75, 0x73, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x09, 0x6d, 0x62, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x0b, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x68, 0x6f, 0x70, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x68, 0x6f, 0x70, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x69,
+	0x73, 0x69, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x06, 0x52, 0x07, 0x76, 0x69, 0x73,
+	0x69, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f,
+	0x69, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x06, 0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x49, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x6d, 0x73, 0x67, 0x5f, 0x6f, 0x6e, 0x65, 0x6f, 0x66,
+	0x22, 0x44, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x06, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x72, 0x67, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x72, 0x67, 0x4a, 0x73, 0x6f, 0x6e, 0x2a, 0x3f, 0x0a, 0x0b, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61,
+	0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x49, 0x44, 0x49, 0x52, 0x45, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x57, 0x52, 0x49, 0x54,
+	0x45, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x41, 0x44,
+	0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x2a, 0x53, 0x0a, 0x07, 0x4d, 0x73, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x45, 0x4d, 0x41, 0x4e, 0x44, 0x10, 0x00, 0x12, 0x0a,
+	0x0a, 0x06, 0x53, 0x55, 0x50, 0x50, 0x4c, 0x59, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x41,
+	0x52, 0x47, 0x45, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x42, 0x55, 0x53, 0x10, 0x03,
+	0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x42, 0x55, 0x53, 0x4d, 0x53, 0x47, 0x10, 0x04, 0x12, 0x0d, 0x0a,
+	0x09, 0x4d, 0x42, 0x55, 0x53, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x05, 0x2a, 0x3f, 0x0a, 0x0a,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45,
+	0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x55, 0x50, 0x50, 0x4c, 0x59, 0x10, 0x00, 0x12, 0x11, 0x0a,
+	0x0d, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x44, 0x45, 0x4d, 0x41, 0x4e, 0x44, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x10, 0x02, 0x32, 0xa9, 0x09,
+	0x0a, 0x07, 0x53, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x12, 0x2c, 0x0a, 0x0c, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x79, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75,
+	0x70, 0x70, 0x6c, 0x79, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65,
+	0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x53,
+	0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70,
+	0x6c, 0x79, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x0c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x75, 0x70,
+	0x70, 0x6c, 0x79, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
 	0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x27, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x72, 0x6d, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x30, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x44, 0x65,
-	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x1a, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x22,
-	0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
-	0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x27, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d,
-	0x62, 0x75, 0x73, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4f, 0x70,
-	0x74, 0x1a, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x22, 0x00, 0x12, 0x27,
-	0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x09, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d,
-	0x62, 0x75, 0x73, 0x1a, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73,
-	0x67, 0x22, 0x00, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x62, 0x75,
-	0x73, 0x4d, 0x73, 0x67, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d,
-	0x73, 0x67, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x2b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x1a, 0x0e,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x00,
-	0x12, 0x39, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x47, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x22, 0x00, 0x30, 0x01, 0x12, 0x34, 0x0a, 0x10, 0x46,
-	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x54, 0x6f, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12,
-	0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67,
-	0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x33, 0x0a, 0x12, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64,
-	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x12, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53,
-	0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x0c, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x10, 0x43,
-	0x6c, 0x6f, 0x73, 0x65, 0x41, 0x6c, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12,
-	0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x44,
-	0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x2f, 0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x5f,
-	0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x0c, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x1a, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x27, 0x0a,
+	0x07, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65,
+	0x6d, 0x61, 0x6e, 0x64, 0x22, 0x00, 0x30, 0x01, 0x12, 0x30, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x0c, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x33, 0x0a, 0x0d, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0b, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12,
+	0x33, 0x0a, 0x0d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79,
+	0x12, 0x0b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x1a, 0x0f, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x6b, 0x22, 0x00,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x27, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x62,
+	0x75, 0x73, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4f, 0x70, 0x74,
+	0x1a, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x22, 0x00, 0x12, 0x27, 0x0a,
+	0x09, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x4d, 0x62, 0x75, 0x73, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62,
+	0x75, 0x73, 0x1a, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73, 0x67,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x2c, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x62, 0x75, 0x73,
+	0x4d, 0x73, 0x67, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x73,
+	0x67, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x2b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x1a, 0x0e, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x00, 0x12,
+	0x33, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x73, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x62,
+	0x75, 0x73, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x4d, 0x62, 0x75, 0x73, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x1a, 0x0d, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2a, 0x0a, 0x09,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x62, 0x75, 0x73, 0x12, 0x09, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x4d, 0x62, 0x75, 0x73, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4d, 0x62, 0x75, 0x73, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x10, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x0f,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x34, 0x0a, 0x10, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x54, 0x6f,
+	0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x4d, 0x73, 0x67, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x12, 0x43, 0x6c, 0x6f,
+	0x73, 0x65, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12,
+	0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x0d, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33,
+	0x0a, 0x12, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x0c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x10, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x41, 0x6c, 0x6c, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x44, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x2f,
+	0x73, 0x79, 0x6e, 0x65, 0x72, 0x65, 0x78, 0x5f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1552,90 +2192,113 @@ func file_synerex_proto_rawDescGZIP() []byte {
 	return file_synerex_proto_rawDescData
 }
 
-var file_synerex_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_synerex_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_synerex_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_synerex_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_synerex_proto_goTypes = []interface{}{
 	(GatewayType)(0),            // 0: api.GatewayType
 	(MsgType)(0),                // 1: api.MsgType
-	(MbusOpt_MbusType)(0),       // 2: api.MbusOpt.MbusType
-	(MbusState_MbusStatus)(0),   // 3: api.MbusState.MbusStatus
-	(*Response)(nil),            // 4: api.Response
-	(*ConfirmResponse)(nil),     // 5: api.ConfirmResponse
-	(*Content)(nil),             // 6: api.Content
-	(*Supply)(nil),              // 7: api.Supply
-	(*Demand)(nil),              // 8: api.Demand
-	(*Target)(nil),              // 9: api.Target
-	(*Channel)(nil),             // 10: api.Channel
-	(*Mbus)(nil),                // 11: api.Mbus
-	(*MbusMsg)(nil),             // 12: api.MbusMsg
-	(*MbusOpt)(nil),             // 13: api.MbusOpt
-	(*MbusState)(nil),           // 14: api.MbusState
-	(*GatewayInfo)(nil),         // 15: api.GatewayInfo
-	(*GatewayMsg)(nil),          // 16: api.GatewayMsg
-	(*ProviderID)(nil),          // 17: api.ProviderID
-	(*duration.Duration)(nil),   // 18: google.protobuf.Duration
-	(*timestamp.Timestamp)(nil), // 19: google.protobuf.Timestamp
+	(TargetType)(0),             // 2: api.TargetType
+	(MbusOpt_MbusType)(0),       // 3: api.MbusOpt.MbusType
+	(MbusState_MbusStatus)(0),   // 4: api.MbusState.MbusStatus
+	(MbusEvent_EventType)(0),    // 5: api.MbusEvent.EventType
+	(*Response)(nil),            // 6: api.Response
+	(*PublishAck)(nil),          // 7: api.PublishAck
+	(*ConfirmResponse)(nil),     // 8: api.ConfirmResponse
+	(*Content)(nil),             // 9: api.Content
+	(*Chunk)(nil),               // 10: api.Chunk
+	(*Supply)(nil),              // 11: api.Supply
+	(*Demand)(nil),              // 12: api.Demand
+	(*Target)(nil),              // 13: api.Target
+	(*Channel)(nil),             // 14: api.Channel
+	(*Mbus)(nil),                // 15: api.Mbus
+	(*MbusMsg)(nil),             // 16: api.MbusMsg
+	(*MbusOpt)(nil),             // 17: api.MbusOpt
+	(*MbusMembers)(nil),         // 18: api.MbusMembers
+	(*MbusState)(nil),           // 19: api.MbusState
+	(*MbusEvent)(nil),           // 20: api.MbusEvent
+	(*GatewayInfo)(nil),         // 21: api.GatewayInfo
+	(*GatewayMsg)(nil),          // 22: api.GatewayMsg
+	(*ProviderID)(nil),          // 23: api.ProviderID
+	(*duration.Duration)(nil),   // 24: google.protobuf.Duration
+	(*timestamp.Timestamp)(nil), // 25: google.protobuf.Timestamp
 }
 var file_synerex_proto_depIdxs = []int32{
-	18, // 0: api.ConfirmResponse.wait:type_name -> google.protobuf.Duration
-	19, // 1: api.Supply.ts:type_name -> google.protobuf.Timestamp
-	6,  // 2: api.Supply.cdata:type_name -> api.Content
-	19, // 3: api.Demand.ts:type_name -> google.protobuf.Timestamp
-	6,  // 4: api.Demand.cdata:type_name -> api.Content
-	18, // 5: api.Target.wait:type_name -> google.protobuf.Duration
-	6,  // 6: api.MbusMsg.cdata:type_name -> api.Content
-	2,  // 7: api.MbusOpt.mbus_type:type_name -> api.MbusOpt.MbusType
-	3,  // 8: api.MbusState.status:type_name -> api.MbusState.MbusStatus
-	0,  // 9: api.GatewayInfo.gateway_type:type_name -> api.GatewayType
-	1,  // 10: api.GatewayMsg.msg_type:type_name -> api.MsgType
-	8,  // 11: api.GatewayMsg.demand:type_name -> api.Demand
-	7,  // 12: api.GatewayMsg.supply:type_name -> api.Supply
-	9,  // 13: api.GatewayMsg.target:type_name -> api.Target
-	11, // 14: api.GatewayMsg.mbus:type_name -> api.Mbus
-	12, // 15: api.GatewayMsg.mbus_msg:type_name -> api.MbusMsg
-	8,  // 16: api.Synerex.NotifyDemand:input_type -> api.Demand
-	7,  // 17: api.Synerex.NotifySupply:input_type -> api.Supply
-	8,  // 18: api.Synerex.ProposeDemand:input_type -> api.Demand
-	7,  // 19: api.Synerex.ProposeSupply:input_type -> api.Supply
-	9,  // 20: api.Synerex.SelectSupply:input_type -> api.Target
-	9,  // 21: api.Synerex.SelectDemand:input_type -> api.Target
-	9,  // 22: api.Synerex.Confirm:input_type -> api.Target
-	10, // 23: api.Synerex.SubscribeDemand:input_type -> api.Channel
-	10, // 24: api.Synerex.SubscribeSupply:input_type -> api.Channel
-	13, // 25: api.Synerex.CreateMbus:input_type -> api.MbusOpt
-	11, // 26: api.Synerex.CloseMbus:input_type -> api.Mbus
-	11, // 27: api.Synerex.SubscribeMbus:input_type -> api.Mbus
-	12, // 28: api.Synerex.SendMbusMsg:input_type -> api.MbusMsg
-	11, // 29: api.Synerex.GetMbusState:input_type -> api.Mbus
-	15, // 30: api.Synerex.SubscribeGateway:input_type -> api.GatewayInfo
-	16, // 31: api.Synerex.ForwardToGateway:input_type -> api.GatewayMsg
-	10, // 32: api.Synerex.CloseDemandChannel:input_type -> api.Channel
-	10, // 33: api.Synerex.CloseSupplyChannel:input_type -> api.Channel
-	17, // 34: api.Synerex.CloseAllChannels:input_type -> api.ProviderID
-	4,  // 35: api.Synerex.NotifyDemand:output_type -> api.Response
-	4,  // 36: api.Synerex.NotifySupply:output_type -> api.Response
-	4,  // 37: api.Synerex.ProposeDemand:output_type -> api.Response
-	4,  // 38: api.Synerex.ProposeSupply:output_type -> api.Response
-	5,  // 39: api.Synerex.SelectSupply:output_type -> api.ConfirmResponse
-	5,  // 40: api.Synerex.SelectDemand:output_type -> api.ConfirmResponse
-	4,  // 41: api.Synerex.Confirm:output_type -> api.Response
-	8,  // 42: api.Synerex.SubscribeDemand:output_type -> api.Demand
-	7,  // 43: api.Synerex.SubscribeSupply:output_type -> api.Supply
-	11, // 44: api.Synerex.CreateMbus:output_type -> api.Mbus
-	4,  // 45: api.Synerex.CloseMbus:output_type -> api.Response
-	12, // 46: api.Synerex.SubscribeMbus:output_type -> api.MbusMsg
-	4,  // 47: api.Synerex.SendMbusMsg:output_type -> api.Response
-	14, // 48: api.Synerex.GetMbusState:output_type -> api.MbusState
-	16, // 49: api.Synerex.SubscribeGateway:output_type -> api.GatewayMsg
-	4,  // 50: api.Synerex.ForwardToGateway:output_type -> api.Response
-	4,  // 51: api.Synerex.CloseDemandChannel:output_type -> api.Response
-	4,  // 52: api.Synerex.CloseSupplyChannel:output_type -> api.Response
-	4,  // 53: api.Synerex.CloseAllChannels:output_type -> api.Response
-	35, // [35:54] is the sub-list for method output_type
-	16, // [16:35] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	24, // 0: api.Response.retry_after:type_name -> google.protobuf.Duration
+	24, // 1: api.PublishAck.retry_after:type_name -> google.protobuf.Duration
+	24, // 2: api.ConfirmResponse.wait:type_name -> google.protobuf.Duration
+	10, // 3: api.Content.chunk:type_name -> api.Chunk
+	25, // 4: api.Supply.ts:type_name -> google.protobuf.Timestamp
+	9,  // 5: api.Supply.cdata:type_name -> api.Content
+	25, // 6: api.Demand.ts:type_name -> google.protobuf.Timestamp
+	9,  // 7: api.Demand.cdata:type_name -> api.Content
+	24, // 8: api.Target.wait:type_name -> google.protobuf.Duration
+	17, // 9: api.Mbus.opt:type_name -> api.MbusOpt
+	9,  // 10: api.MbusMsg.cdata:type_name -> api.Content
+	3,  // 11: api.MbusOpt.mbus_type:type_name -> api.MbusOpt.MbusType
+	4,  // 12: api.MbusState.status:type_name -> api.MbusState.MbusStatus
+	5,  // 13: api.MbusEvent.event_type:type_name -> api.MbusEvent.EventType
+	0,  // 14: api.GatewayInfo.gateway_type:type_name -> api.GatewayType
+	1,  // 15: api.GatewayMsg.msg_type:type_name -> api.MsgType
+	12, // 16: api.GatewayMsg.demand:type_name -> api.Demand
+	11, // 17: api.GatewayMsg.supply:type_name -> api.Supply
+	13, // 18: api.GatewayMsg.target:type_name -> api.Target
+	15, // 19: api.GatewayMsg.mbus:type_name -> api.Mbus
+	16, // 20: api.GatewayMsg.mbus_msg:type_name -> api.MbusMsg
+	20, // 21: api.GatewayMsg.mbus_event:type_name -> api.MbusEvent
+	2,  // 22: api.GatewayMsg.target_type:type_name -> api.TargetType
+	12, // 23: api.Synerex.NotifyDemand:input_type -> api.Demand
+	11, // 24: api.Synerex.NotifySupply:input_type -> api.Supply
+	12, // 25: api.Synerex.ProposeDemand:input_type -> api.Demand
+	11, // 26: api.Synerex.ProposeSupply:input_type -> api.Supply
+	13, // 27: api.Synerex.SelectSupply:input_type -> api.Target
+	13, // 28: api.Synerex.SelectDemand:input_type -> api.Target
+	13, // 29: api.Synerex.Confirm:input_type -> api.Target
+	14, // 30: api.Synerex.SubscribeDemand:input_type -> api.Channel
+	14, // 31: api.Synerex.SubscribeSupply:input_type -> api.Channel
+	12, // 32: api.Synerex.PublishDemand:input_type -> api.Demand
+	11, // 33: api.Synerex.PublishSupply:input_type -> api.Supply
+	17, // 34: api.Synerex.CreateMbus:input_type -> api.MbusOpt
+	15, // 35: api.Synerex.CloseMbus:input_type -> api.Mbus
+	15, // 36: api.Synerex.SubscribeMbus:input_type -> api.Mbus
+	16, // 37: api.Synerex.SendMbusMsg:input_type -> api.MbusMsg
+	15, // 38: api.Synerex.GetMbusState:input_type -> api.Mbus
+	18, // 39: api.Synerex.AddMbusMembers:input_type -> api.MbusMembers
+	18, // 40: api.Synerex.RemoveMbusMembers:input_type -> api.MbusMembers
+	15, // 41: api.Synerex.WatchMbus:input_type -> api.Mbus
+	21, // 42: api.Synerex.SubscribeGateway:input_type -> api.GatewayInfo
+	22, // 43: api.Synerex.ForwardToGateway:input_type -> api.GatewayMsg
+	14, // 44: api.Synerex.CloseDemandChannel:input_type -> api.Channel
+	14, // 45: api.Synerex.CloseSupplyChannel:input_type -> api.Channel
+	23, // 46: api.Synerex.CloseAllChannels:input_type -> api.ProviderID
+	6,  // 47: api.Synerex.NotifyDemand:output_type -> api.Response
+	6,  // 48: api.Synerex.NotifySupply:output_type -> api.Response
+	6,  // 49: api.Synerex.ProposeDemand:output_type -> api.Response
+	6,  // 50: api.Synerex.ProposeSupply:output_type -> api.Response
+	8,  // 51: api.Synerex.SelectSupply:output_type -> api.ConfirmResponse
+	8,  // 52: api.Synerex.SelectDemand:output_type -> api.ConfirmResponse
+	6,  // 53: api.Synerex.Confirm:output_type -> api.Response
+	12, // 54: api.Synerex.SubscribeDemand:output_type -> api.Demand
+	11, // 55: api.Synerex.SubscribeSupply:output_type -> api.Supply
+	7,  // 56: api.Synerex.PublishDemand:output_type -> api.PublishAck
+	7,  // 57: api.Synerex.PublishSupply:output_type -> api.PublishAck
+	15, // 58: api.Synerex.CreateMbus:output_type -> api.Mbus
+	6,  // 59: api.Synerex.CloseMbus:output_type -> api.Response
+	16, // 60: api.Synerex.SubscribeMbus:output_type -> api.MbusMsg
+	6,  // 61: api.Synerex.SendMbusMsg:output_type -> api.Response
+	19, // 62: api.Synerex.GetMbusState:output_type -> api.MbusState
+	6,  // 63: api.Synerex.AddMbusMembers:output_type -> api.Response
+	6,  // 64: api.Synerex.RemoveMbusMembers:output_type -> api.Response
+	20, // 65: api.Synerex.WatchMbus:output_type -> api.MbusEvent
+	22, // 66: api.Synerex.SubscribeGateway:output_type -> api.GatewayMsg
+	6,  // 67: api.Synerex.ForwardToGateway:output_type -> api.Response
+	6,  // 68: api.Synerex.CloseDemandChannel:output_type -> api.Response
+	6,  // 69: api.Synerex.CloseSupplyChannel:output_type -> api.Response
+	6,  // 70: api.Synerex.CloseAllChannels:output_type -> api.Response
+	47, // [47:71] is the sub-list for method output_type
+	23, // [23:47] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_synerex_proto_init() }
@@ -1657,7 +2320,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfirmResponse); i {
+			switch v := v.(*PublishAck); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1669,7 +2332,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Content); i {
+			switch v := v.(*ConfirmResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1681,7 +2344,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Supply); i {
+			switch v := v.(*Content); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1693,7 +2356,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Demand); i {
+			switch v := v.(*Chunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1705,7 +2368,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Target); i {
+			switch v := v.(*Supply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1717,7 +2380,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Channel); i {
+			switch v := v.(*Demand); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1729,7 +2392,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Mbus); i {
+			switch v := v.(*Target); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1741,7 +2404,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MbusMsg); i {
+			switch v := v.(*Channel); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1753,7 +2416,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MbusOpt); i {
+			switch v := v.(*Mbus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1765,7 +2428,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MbusState); i {
+			switch v := v.(*MbusMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1777,7 +2440,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GatewayInfo); i {
+			switch v := v.(*MbusOpt); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1789,7 +2452,7 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GatewayMsg); i {
+			switch v := v.(*MbusMembers); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1801,6 +2464,54 @@ func file_synerex_proto_init() {
 			}
 		}
 		file_synerex_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MbusState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_synerex_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MbusEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_synerex_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_synerex_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GatewayMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_synerex_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProviderID); i {
 			case 0:
 				return &v.state
@@ -1813,20 +2524,21 @@ func file_synerex_proto_init() {
 			}
 		}
 	}
-	file_synerex_proto_msgTypes[12].OneofWrappers = []interface{}{
+	file_synerex_proto_msgTypes[16].OneofWrappers = []interface{}{
 		(*GatewayMsg_Demand)(nil),
 		(*GatewayMsg_Supply)(nil),
 		(*GatewayMsg_Target)(nil),
 		(*GatewayMsg_Mbus)(nil),
 		(*GatewayMsg_MbusMsg)(nil),
+		(*GatewayMsg_MbusEvent)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_synerex_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   14,
+			NumEnums:      6,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1862,11 +2574,16 @@ type SynerexClient interface {
 	Confirm(ctx context.Context, in *Target, opts ...grpc.CallOption) (*Response, error)
 	SubscribeDemand(ctx context.Context, in *Channel, opts ...grpc.CallOption) (Synerex_SubscribeDemandClient, error)
 	SubscribeSupply(ctx context.Context, in *Channel, opts ...grpc.CallOption) (Synerex_SubscribeSupplyClient, error)
+	PublishDemand(ctx context.Context, opts ...grpc.CallOption) (Synerex_PublishDemandClient, error)
+	PublishSupply(ctx context.Context, opts ...grpc.CallOption) (Synerex_PublishSupplyClient, error)
 	CreateMbus(ctx context.Context, in *MbusOpt, opts ...grpc.CallOption) (*Mbus, error)
 	CloseMbus(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (*Response, error)
 	SubscribeMbus(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (Synerex_SubscribeMbusClient, error)
 	SendMbusMsg(ctx context.Context, in *MbusMsg, opts ...grpc.CallOption) (*Response, error)
 	GetMbusState(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (*MbusState, error)
+	AddMbusMembers(ctx context.Context, in *MbusMembers, opts ...grpc.CallOption) (*Response, error)
+	RemoveMbusMembers(ctx context.Context, in *MbusMembers, opts ...grpc.CallOption) (*Response, error)
+	WatchMbus(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (Synerex_WatchMbusClient, error)
 	SubscribeGateway(ctx context.Context, in *GatewayInfo, opts ...grpc.CallOption) (Synerex_SubscribeGatewayClient, error)
 	ForwardToGateway(ctx context.Context, in *GatewayMsg, opts ...grpc.CallOption) (*Response, error)
 	CloseDemandChannel(ctx context.Context, in *Channel, opts ...grpc.CallOption) (*Response, error)
@@ -2009,6 +2726,68 @@ func (x *synerexSubscribeSupplyClient) Recv() (*Supply, error) {
 	return m, nil
 }
 
+func (c *synerexClient) PublishDemand(ctx context.Context, opts ...grpc.CallOption) (Synerex_PublishDemandClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[2], "/api.Synerex/PublishDemand", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &synerexPublishDemandClient{stream}
+	return x, nil
+}
+
+type Synerex_PublishDemandClient interface {
+	Send(*Demand) error
+	Recv() (*PublishAck, error)
+	grpc.ClientStream
+}
+
+type synerexPublishDemandClient struct {
+	grpc.ClientStream
+}
+
+func (x *synerexPublishDemandClient) Send(m *Demand) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *synerexPublishDemandClient) Recv() (*PublishAck, error) {
+	m := new(PublishAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *synerexClient) PublishSupply(ctx context.Context, opts ...grpc.CallOption) (Synerex_PublishSupplyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[3], "/api.Synerex/PublishSupply", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &synerexPublishSupplyClient{stream}
+	return x, nil
+}
+
+type Synerex_PublishSupplyClient interface {
+	Send(*Supply) error
+	Recv() (*PublishAck, error)
+	grpc.ClientStream
+}
+
+type synerexPublishSupplyClient struct {
+	grpc.ClientStream
+}
+
+func (x *synerexPublishSupplyClient) Send(m *Supply) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *synerexPublishSupplyClient) Recv() (*PublishAck, error) {
+	m := new(PublishAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *synerexClient) CreateMbus(ctx context.Context, in *MbusOpt, opts ...grpc.CallOption) (*Mbus, error) {
 	out := new(Mbus)
 	err := c.cc.Invoke(ctx, "/api.Synerex/CreateMbus", in, out, opts...)
@@ -2028,7 +2807,7 @@ func (c *synerexClient) CloseMbus(ctx context.Context, in *Mbus, opts ...grpc.Ca
 }
 
 func (c *synerexClient) SubscribeMbus(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (Synerex_SubscribeMbusClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[2], "/api.Synerex/SubscribeMbus", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[4], "/api.Synerex/SubscribeMbus", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2077,8 +2856,58 @@ func (c *synerexClient) GetMbusState(ctx context.Context, in *Mbus, opts ...grpc
 	return out, nil
 }
 
+func (c *synerexClient) AddMbusMembers(ctx context.Context, in *MbusMembers, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/api.Synerex/AddMbusMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synerexClient) RemoveMbusMembers(ctx context.Context, in *MbusMembers, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/api.Synerex/RemoveMbusMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *synerexClient) WatchMbus(ctx context.Context, in *Mbus, opts ...grpc.CallOption) (Synerex_WatchMbusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[5], "/api.Synerex/WatchMbus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &synerexWatchMbusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Synerex_WatchMbusClient interface {
+	Recv() (*MbusEvent, error)
+	grpc.ClientStream
+}
+
+type synerexWatchMbusClient struct {
+	grpc.ClientStream
+}
+
+func (x *synerexWatchMbusClient) Recv() (*MbusEvent, error) {
+	m := new(MbusEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *synerexClient) SubscribeGateway(ctx context.Context, in *GatewayInfo, opts ...grpc.CallOption) (Synerex_SubscribeGatewayClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[3], "/api.Synerex/SubscribeGateway", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Synerex_serviceDesc.Streams[6], "/api.Synerex/SubscribeGateway", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2156,11 +2985,16 @@ type SynerexServer interface {
 	Confirm(context.Context, *Target) (*Response, error)
 	SubscribeDemand(*Channel, Synerex_SubscribeDemandServer) error
 	SubscribeSupply(*Channel, Synerex_SubscribeSupplyServer) error
+	PublishDemand(Synerex_PublishDemandServer) error
+	PublishSupply(Synerex_PublishSupplyServer) error
 	CreateMbus(context.Context, *MbusOpt) (*Mbus, error)
 	CloseMbus(context.Context, *Mbus) (*Response, error)
 	SubscribeMbus(*Mbus, Synerex_SubscribeMbusServer) error
 	SendMbusMsg(context.Context, *MbusMsg) (*Response, error)
 	GetMbusState(context.Context, *Mbus) (*MbusState, error)
+	AddMbusMembers(context.Context, *MbusMembers) (*Response, error)
+	RemoveMbusMembers(context.Context, *MbusMembers) (*Response, error)
+	WatchMbus(*Mbus, Synerex_WatchMbusServer) error
 	SubscribeGateway(*GatewayInfo, Synerex_SubscribeGatewayServer) error
 	ForwardToGateway(context.Context, *GatewayMsg) (*Response, error)
 	CloseDemandChannel(context.Context, *Channel) (*Response, error)
@@ -2199,6 +3033,12 @@ func (*UnimplementedSynerexServer) SubscribeDemand(*Channel, Synerex_SubscribeDe
 func (*UnimplementedSynerexServer) SubscribeSupply(*Channel, Synerex_SubscribeSupplyServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeSupply not implemented")
 }
+func (*UnimplementedSynerexServer) PublishDemand(Synerex_PublishDemandServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishDemand not implemented")
+}
+func (*UnimplementedSynerexServer) PublishSupply(Synerex_PublishSupplyServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishSupply not implemented")
+}
 func (*UnimplementedSynerexServer) CreateMbus(context.Context, *MbusOpt) (*Mbus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateMbus not implemented")
 }
@@ -2214,6 +3054,15 @@ func (*UnimplementedSynerexServer) SendMbusMsg(context.Context, *MbusMsg) (*Resp
 func (*UnimplementedSynerexServer) GetMbusState(context.Context, *Mbus) (*MbusState, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMbusState not implemented")
 }
+func (*UnimplementedSynerexServer) AddMbusMembers(context.Context, *MbusMembers) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMbusMembers not implemented")
+}
+func (*UnimplementedSynerexServer) RemoveMbusMembers(context.Context, *MbusMembers) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMbusMembers not implemented")
+}
+func (*UnimplementedSynerexServer) WatchMbus(*Mbus, Synerex_WatchMbusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMbus not implemented")
+}
 func (*UnimplementedSynerexServer) SubscribeGateway(*GatewayInfo, Synerex_SubscribeGatewayServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeGateway not implemented")
 }
@@ -2402,6 +3251,58 @@ func (x *synerexSubscribeSupplyServer) Send(m *Supply) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Synerex_PublishDemand_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SynerexServer).PublishDemand(&synerexPublishDemandServer{stream})
+}
+
+type Synerex_PublishDemandServer interface {
+	Send(*PublishAck) error
+	Recv() (*Demand, error)
+	grpc.ServerStream
+}
+
+type synerexPublishDemandServer struct {
+	grpc.ServerStream
+}
+
+func (x *synerexPublishDemandServer) Send(m *PublishAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *synerexPublishDemandServer) Recv() (*Demand, error) {
+	m := new(Demand)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Synerex_PublishSupply_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SynerexServer).PublishSupply(&synerexPublishSupplyServer{stream})
+}
+
+type Synerex_PublishSupplyServer interface {
+	Send(*PublishAck) error
+	Recv() (*Supply, error)
+	grpc.ServerStream
+}
+
+type synerexPublishSupplyServer struct {
+	grpc.ServerStream
+}
+
+func (x *synerexPublishSupplyServer) Send(m *PublishAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *synerexPublishSupplyServer) Recv() (*Supply, error) {
+	m := new(Supply)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Synerex_CreateMbus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MbusOpt)
 	if err := dec(in); err != nil {
@@ -2495,6 +3396,63 @@ func _Synerex_GetMbusState_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Synerex_AddMbusMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MbusMembers)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynerexServer).AddMbusMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Synerex/AddMbusMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynerexServer).AddMbusMembers(ctx, req.(*MbusMembers))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synerex_RemoveMbusMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MbusMembers)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SynerexServer).RemoveMbusMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Synerex/RemoveMbusMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SynerexServer).RemoveMbusMembers(ctx, req.(*MbusMembers))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Synerex_WatchMbus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Mbus)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SynerexServer).WatchMbus(m, &synerexWatchMbusServer{stream})
+}
+
+type Synerex_WatchMbusServer interface {
+	Send(*MbusEvent) error
+	grpc.ServerStream
+}
+
+type synerexWatchMbusServer struct {
+	grpc.ServerStream
+}
+
+func (x *synerexWatchMbusServer) Send(m *MbusEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Synerex_SubscribeGateway_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(GatewayInfo)
 	if err := stream.RecvMsg(m); err != nil {
@@ -2636,6 +3594,14 @@ var _Synerex_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetMbusState",
 			Handler:    _Synerex_GetMbusState_Handler,
 		},
+		{
+			MethodName: "AddMbusMembers",
+			Handler:    _Synerex_AddMbusMembers_Handler,
+		},
+		{
+			MethodName: "RemoveMbusMembers",
+			Handler:    _Synerex_RemoveMbusMembers_Handler,
+		},
 		{
 			MethodName: "ForwardToGateway",
 			Handler:    _Synerex_ForwardToGateway_Handler,
@@ -2664,11 +3630,28 @@ var _Synerex_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Synerex_SubscribeSupply_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PublishDemand",
+			Handler:       _Synerex_PublishDemand_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PublishSupply",
+			Handler:       _Synerex_PublishSupply_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "SubscribeMbus",
 			Handler:       _Synerex_SubscribeMbus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchMbus",
+			Handler:       _Synerex_WatchMbus_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeGateway",
 			Handler:       _Synerex_SubscribeGateway_Handler,
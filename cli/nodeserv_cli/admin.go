@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Commands for admin service of synerex server
+
+var (
+	admin       = flag.String("admin", "127.0.0.1:10080", "Admin service address of synerex server")
+	authKey     = flag.String("authkey", os.Getenv("SX_AUTH_KEY"), "Key shared with synerex server and node server for signing admin token (empty for no auth)")
+	subscribers = flag.Bool("subscribers", false, "Show subscribers of synerex server")
+	gateways    = flag.Bool("gateways", false, "Show gateways of synerex server")
+	mbuses      = flag.Bool("mbus", false, "Show mbuses of synerex server")
+	channel     = flag.Int("channel", 0, "Channel type for -subscribers (0 for all)")
+	node        = flag.Int("node", 0, "Node ID for -subscribers, -gateways and -mbus (0 for all)")
+	closeSub    = flag.String("closesub", "", "Force close subscriber [demand|supply],[channel type],[client id]")
+	closeMbus   = flag.Uint64("closembus", 0, "Force close mbus with mbus id")
+	drain       = flag.Bool("drain", false, "Drain synerex server (stop publishes and move providers)")
+	drainTime   = flag.Duration("draintimeout", 0, "Timeout for -drain (0 for server default)")
+	shutdown    = flag.Bool("shutdown", false, "Shutdown synerex server after -drain")
+	logLevel    = flag.String("loglevel", "", "Set log level of synerex server [component=]level (\"show\" for current levels)")
+)
+
+func isAdminCommand() bool {
+	return *subscribers || *gateways || *mbuses || *closeSub != "" || *closeMbus != 0 || *drain || *logLevel != ""
+}
+
+// adminTokenInterceptor sends admin token signed with authKey
+func adminTokenInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	token := nodeapi.SignNodeToken([]byte(*authKey), nodeapi.AdminNodeID, time.Now().Add(time.Minute))
+	ctx = metadata.AppendToOutgoingContext(ctx, nodeapi.TokenMetadataKey, token)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func connectAdmin() (api.SynerexAdminClient, error) {
+	dopt, err := tlsOption.DialOption()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{dopt}
+	if *authKey != "" {
+		opts = append(opts, grpc.WithUnaryInterceptor(adminTokenInterceptor))
+	}
+	aconn, err := grpc.Dial(*admin, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewSynerexAdminClient(aconn), nil
+}
+
+func OutputSubscribers(aclient api.SynerexAdminClient, filter *api.AdminFilter) {
+	list, err := aclient.ListSubscribers(context.Background(), filter)
+	if err != nil {
+		log.Printf("Error on ListSubscribers: %v", err)
+		return
+	}
+	fmt.Printf("  SUBSCRIBER\n")
+	fmt.Printf("  Chan Type   Node ClientID             Queue Filter\n")
+	for _, si := range list.Subscribers {
+		fmt.Printf("  %4d %-6.6s %4d %-20d %5d/%-5d %v\n",
+			si.ChannelType,
+			si.MsgType,
+			si.NodeId,
+			si.ClientId,
+			si.QueueLen,
+			si.QueueCap,
+			si.Filtered)
+	}
+}
+
+func OutputGateways(aclient api.SynerexAdminClient, filter *api.AdminFilter) {
+	list, err := aclient.ListGateways(context.Background(), filter)
+	if err != nil {
+		log.Printf("Error on ListGateways: %v", err)
+		return
+	}
+	fmt.Printf("  GATEWAY\n")
+	fmt.Printf("  Node ClientID             Type          Queue Channels\n")
+	for _, ge := range list.Gateways {
+		fmt.Printf("  %4d %-20d %-13.13s %5d %v\n",
+			ge.NodeId,
+			ge.ClientId,
+			ge.GatewayType,
+			ge.QueueLen,
+			ge.Channels)
+	}
+}
+
+func OutputMbus(aclient api.SynerexAdminClient, filter *api.AdminFilter) {
+	list, err := aclient.ListMbus(context.Background(), filter)
+	if err != nil {
+		log.Printf("Error on ListMbus: %v", err)
+		return
+	}
+	fmt.Printf("  MBUS\n")
+	fmt.Printf("  MbusID               Owner                Private Status      Pending Subscribers\n")
+	for _, me := range list.Mbuses {
+		fmt.Printf("  %-20d %-20d %-7v %-11.11s %7d %v\n",
+			me.MbusId,
+			me.Owner,
+			me.Private,
+			me.Status,
+			me.Pending,
+			me.Subscribers)
+	}
+}
+
+func CloseSubscriber(aclient api.SynerexAdminClient, arg string) {
+	items := strings.Split(arg, ",")
+	if len(items) != 3 {
+		fmt.Printf("  Please specify [demand|supply],[channel type],[client id]\n")
+		return
+	}
+	si := &api.SubscriberInfo{}
+	switch items[0] {
+	case "demand":
+		si.MsgType = api.MsgType_DEMAND
+	case "supply":
+		si.MsgType = api.MsgType_SUPPLY
+	default:
+		fmt.Printf("  Subscriber type is invalid\n")
+		return
+	}
+	ctype, err := strconv.ParseUint(items[1], 10, 32)
+	if err != nil {
+		fmt.Printf("  Channel type is invalid\n")
+		return
+	}
+	si.ChannelType = uint32(ctype)
+	si.ClientId, err = strconv.ParseUint(items[2], 10, 64)
+	if err != nil {
+		fmt.Printf("  Client ID is invalid\n")
+		return
+	}
+	resp, err := aclient.CloseSubscriber(context.Background(), si)
+	if err != nil {
+		log.Printf("Error on CloseSubscriber: %v", err)
+		return
+	}
+	fmt.Printf("  CloseSubscriber %v %s\n", resp.Ok, resp.Err)
+}
+
+func CloseMbus(aclient api.SynerexAdminClient, mbusId uint64) {
+	resp, err := aclient.ForceCloseMbus(context.Background(), &api.Mbus{MbusId: mbusId})
+	if err != nil {
+		log.Printf("Error on ForceCloseMbus: %v", err)
+		return
+	}
+	fmt.Printf("  CloseMbus %v %s\n", resp.Ok, resp.Err)
+}
+
+func DrainServer(aclient api.SynerexAdminClient) {
+	dr := &api.DrainRequest{Shutdown: *shutdown}
+	if *drainTime > 0 {
+		dr.Timeout = ptypes.DurationProto(*drainTime)
+	}
+	resp, err := aclient.Drain(context.Background(), dr)
+	if err != nil {
+		log.Printf("Error on Drain: %v", err)
+		return
+	}
+	fmt.Printf("  Drain %v %s\n", resp.Ok, resp.Err)
+}
+
+// SetLogLevel sets log level of the component and shows current levels
+func SetLogLevel(aclient api.SynerexAdminClient, arg string) {
+	ll := &api.LogLevel{}
+	ll.Component, ll.Level = parseLogLevelArg(arg)
+	resp, err := aclient.SetLogLevel(context.Background(), ll)
+	if err != nil {
+		log.Printf("Error on SetLogLevel: %v", err)
+		return
+	}
+	levels := make(map[string]string)
+	for _, lv := range resp.GetLevels() {
+		levels[lv.Component] = lv.Level
+	}
+	outputLogLevels(levels)
+}
+
+// parseLogLevelArg parses [component=]level ("show" for only listing levels)
+func parseLogLevelArg(arg string) (component, level string) {
+	if arg == "show" {
+		return "", ""
+	}
+	if kv := strings.SplitN(arg, "=", 2); len(kv) == 2 {
+		return kv[0], kv[1]
+	}
+	return "", arg
+}
+
+// outputLogLevels shows levels sorted by component
+func outputLogLevels(levels map[string]string) {
+	components := make([]string, 0, len(levels))
+	for component := range levels {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		name := component
+		if name == "" {
+			name = "(default)"
+		}
+		fmt.Printf("  %-24s %s\n", name, levels[component])
+	}
+}
+
+// runAdminCommand runs admin commands specified by flags
+func runAdminCommand() {
+	aclient, err := connectAdmin()
+	if err != nil {
+		log.Printf("fail to connect admin service: %v", err)
+		return
+	}
+	filter := &api.AdminFilter{ChannelType: uint32(*channel), NodeId: int32(*node)}
+	if *closeSub != "" {
+		CloseSubscriber(aclient, *closeSub)
+	}
+	if *closeMbus != 0 {
+		CloseMbus(aclient, *closeMbus)
+	}
+	if *subscribers {
+		OutputSubscribers(aclient, filter)
+	}
+	if *gateways {
+		OutputGateways(aclient, filter)
+	}
+	if *mbuses {
+		OutputMbus(aclient, filter)
+	}
+	if *logLevel != "" {
+		SetLogLevel(aclient, *logLevel)
+	}
+	if *drain {
+		DrainServer(aclient)
+	}
+}
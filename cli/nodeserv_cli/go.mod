@@ -6,12 +6,15 @@ require (
 	github.com/golang/protobuf v1.4.2
 	github.com/google/gops v0.3.6
 	github.com/kardianos/osext v0.0.0-20190222173326-2bc1f35cddc0 // indirect
-	github.com/synerex/synerex_api v0.3.1
-	github.com/synerex/synerex_nodeapi v0.5.3
+	github.com/synerex/synerex_api v0.4.2
+	github.com/synerex/synerex_nodeapi v0.5.4
 	github.com/synerex/synerex_nodeserv_controlapi v0.1.0
 	github.com/synerex/synerex_sxutil v0.4.7
-	golang.org/x/net v0.0.0-20200602114024-627f9648deb9 // indirect
-	golang.org/x/sys v0.0.0-20200610111108-226ff32320da // indirect
-	google.golang.org/genproto v0.0.0-20200612171551-7676ae05be11 // indirect
-	google.golang.org/grpc v1.29.1
+	google.golang.org/grpc v1.32.0
 )
+
+replace github.com/synerex/synerex_api => ../../api
+
+replace github.com/synerex/synerex_nodeapi => ../../nodeapi
+
+replace github.com/synerex/synerex_sxutil => ../../sxutil
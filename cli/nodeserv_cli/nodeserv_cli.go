@@ -12,15 +12,18 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	synerex_nodeapi "github.com/synerex/synerex_nodeapi"
 	nodecapi "github.com/synerex/synerex_nodeserv_controlapi"
+	sxutil "github.com/synerex/synerex_sxutil"
 	"google.golang.org/grpc"
 )
 
 var (
-	nodesrv = flag.String("nodesrv", "127.0.0.1:9990", "Node Server adderess and port")
-	show    = flag.Bool("show", true, "Show Nodeserv information")
-	sxmove  = flag.String("sxmove", "", "Move provider to different synerex sever [provier id],[synerex id]")
-	client  nodecapi.NodeControlClient
-	conn    *grpc.ClientConn
+	nodesrv      = flag.String("nodesrv", "127.0.0.1:9990", "Node Server adderess and port")
+	show         = flag.Bool("show", true, "Show Nodeserv information")
+	sxmove       = flag.String("sxmove", "", "Move provider to different synerex sever [provier id],[synerex id]")
+	nodeLogLevel = flag.String("nodeloglevel", "", "Set log level of node server [component=]level (\"show\" for current levels)")
+	tlsOption    = sxutil.TLSFlags() // -tls_client, -tls_ca, -tls_cert, -tls_key, -tls_server_name
+	client       nodecapi.NodeControlClient
+	conn         *grpc.ClientConn
 )
 
 // for git versions
@@ -41,7 +44,7 @@ func SwitchServer(prvId, srvId int32) {
 	filter.NodeType = synerex_nodeapi.NodeType_PROVIDER
 	nodeinfos, err := client.QueryNodeInfos(context.Background(), &filter)
 	if err != nil {
-		log.Printf("Error on QueryNodeInfos\n", err)
+		log.Printf("Error on QueryNodeInfos: %v", err)
 		return
 	}
 
@@ -60,7 +63,7 @@ func SwitchServer(prvId, srvId int32) {
 	filter.NodeType = synerex_nodeapi.NodeType_SERVER
 	nodeinfos, err = client.QueryNodeInfos(context.Background(), &filter)
 	if err != nil {
-		log.Printf("Error on QueryNodeInfos\n", err)
+		log.Printf("Error on QueryNodeInfos: %v", err)
 		return
 	}
 
@@ -95,7 +98,7 @@ func SwitchServer(prvId, srvId int32) {
 
 	_, err = client.ControlNodes(context.Background(), &order)
 	if err != nil {
-		log.Printf("Error on ControlNodes\n", err)
+		log.Printf("Error on ControlNodes: %v", err)
 		return
 	}
 }
@@ -107,7 +110,7 @@ func OutputCurrentSP() {
 	filter.NodeType = synerex_nodeapi.NodeType_GATEWAY
 	nodeinfos, err := client.QueryNodeInfos(context.Background(), &filter)
 	if err != nil {
-		log.Printf("Error on QueryNodeInfos\n", err)
+		log.Printf("Error on QueryNodeInfos: %v", err)
 		return
 	}
 
@@ -128,7 +131,7 @@ func OutputCurrentSP() {
 	filter.NodeType = synerex_nodeapi.NodeType_SERVER
 	nodeinfos, err = client.QueryNodeInfos(context.Background(), &filter)
 	if err != nil {
-		log.Printf("Error on QueryNodeInfos\n", err)
+		log.Printf("Error on QueryNodeInfos: %v", err)
 		return
 	}
 	srvinfos := nodeinfos
@@ -151,7 +154,7 @@ func OutputCurrentSP() {
 	filter.NodeType = synerex_nodeapi.NodeType_PROVIDER
 	nodeinfos, err = client.QueryNodeInfos(context.Background(), &filter)
 	if err != nil {
-		log.Printf("Error on QueryNodeInfos\n", err)
+		log.Printf("Error on QueryNodeInfos: %v", err)
 		return
 	}
 
@@ -183,6 +186,22 @@ func OutputCurrentSP() {
 
 }
 
+// SetNodeLogLevel sets log level of the component of node server and shows current levels
+func SetNodeLogLevel(arg string) {
+	ll := &synerex_nodeapi.LogLevel{}
+	ll.Component, ll.Level = parseLogLevelArg(arg)
+	resp, err := synerex_nodeapi.NewNodeClient(conn).SetLogLevel(context.Background(), ll)
+	if err != nil {
+		log.Printf("Error on SetLogLevel: %v", err)
+		return
+	}
+	levels := make(map[string]string)
+	for _, lv := range resp.GetLevels() {
+		levels[lv.Component] = lv.Level
+	}
+	outputLogLevels(levels)
+}
+
 func main() {
 	var err error
 	var Provider, Server int
@@ -191,8 +210,21 @@ func main() {
 
 	log.Printf("nodeserv_cli(%s) built %s sha1 %s", gitver, buildTime, sha1ver)
 
+	if isAdminCommand() { // commands for synerex server
+		runAdminCommand()
+		return
+	}
+
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure()) // insecure
+	dopt, err := tlsOption.DialOption() // insecure if TLS is not specified
+	if err != nil {
+		log.Printf("fail to load TLS certificates: %v", err)
+		os.Exit(1)
+	}
+	opts = append(opts, dopt)
+	if *authKey != "" {
+		opts = append(opts, grpc.WithUnaryInterceptor(adminTokenInterceptor))
+	}
 	conn, err = grpc.Dial(*nodesrv, opts...)
 	if err != nil {
 		log.Printf("fail to dial: %v", err)
@@ -201,7 +233,9 @@ func main() {
 
 	client = nodecapi.NewNodeControlClient(conn)
 
-	if *sxmove != "" {
+	if *nodeLogLevel != "" {
+		SetNodeLogLevel(*nodeLogLevel)
+	} else if *sxmove != "" {
 		//
 		ids := strings.Split(*sxmove, ",")
 		if len(ids) != 2 {
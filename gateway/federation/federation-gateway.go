@@ -31,6 +31,7 @@ var (
 	name            = flag.String("name", "Federation-Gateway", "Name of Fedration Gateway")
 	server          = flag.String("server", "", "Speficy Synerex Server name")
 	port            = flag.Int("port", 10070, "Federataion Gateway API Port")
+	tlsOption       = sxutil.TLSFlags() // TLS for nodeserv and synerex servers
 	version         = "0.01"
 	assetsDir       http.FileSystem
 	idlist          []uint64
@@ -127,6 +128,7 @@ func listenGatewayMsg(sg api.Synerex_SubscribeGatewayClient) {
 
 func main() {
 	flag.Parse()
+	sxutil.SetTLSOption(tlsOption)
 	go sxutil.HandleSigInt()
 	sxutil.RegisterDeferFunction(sxutil.UnRegisterNode)
 
@@ -162,7 +164,7 @@ func main() {
 			Channels:    channels,
 		}
 		ctx := context.Background() //
-		sg0, err := client0.SubscribeGateway(ctx, gi)
+		sg0, err := client0.Client.SubscribeGateway(ctx, gi)
 		if err != nil {
 			log.Printf("Synerex subscribe Error %v\n", err)
 		} else {
@@ -4,8 +4,14 @@ go 1.12
 
 require (
 	github.com/mtfelian/golang-socketio v1.5.2
-	github.com/synerex/synerex_api v0.2.1
-	github.com/synerex/synerex_nodeapi v0.4.6
-	github.com/synerex/synerex_sxutil v0.3.10
-	golang.org/x/net v0.0.0-20191116160921-f9c825593386
+	github.com/synerex/synerex_api v0.4.2
+	github.com/synerex/synerex_nodeapi v0.5.4
+	github.com/synerex/synerex_sxutil v0.6.2
+	golang.org/x/net v0.0.0-20200927032502-5d4f70055728
 )
+
+replace github.com/synerex/synerex_api => ../../api
+
+replace github.com/synerex/synerex_nodeapi => ../../nodeapi
+
+replace github.com/synerex/synerex_sxutil => ../../sxutil
@@ -6,8 +6,7 @@ require (
 	cloud.google.com/go v0.46.3 // indirect
 	cloud.google.com/go/bigquery v1.0.1
 	github.com/creack/pty v1.1.9 // indirect
-	github.com/golang/protobuf v1.3.2
-	github.com/google/go-cmp v0.3.1 // indirect
+	github.com/golang/protobuf v1.4.2
 	github.com/google/pprof v0.0.0-20190908185732-236ed259b199 // indirect
 	github.com/gorilla/websocket v1.4.1 // indirect
 	github.com/hashicorp/golang-lru v0.5.3 // indirect
@@ -15,23 +14,28 @@ require (
 	github.com/mtfelian/golang-socketio v1.5.2
 	github.com/rogpeppe/go-internal v1.4.0 // indirect
 	github.com/stretchr/objx v0.2.0 // indirect
-	github.com/stretchr/testify v1.4.0 // indirect
 	github.com/synerex/proto_fleet v0.0.1
-	github.com/synerex/synerex_api v0.2.1
-	github.com/synerex/synerex_nodeapi v0.4.6
-	github.com/synerex/synerex_proto v0.1.1
-	github.com/synerex/synerex_sxutil v0.3.10
+	github.com/synerex/synerex_api v0.4.2
+	github.com/synerex/synerex_nodeapi v0.5.4
+	github.com/synerex/synerex_proto v0.1.9
+	github.com/synerex/synerex_sxutil v0.6.2
 	go.opencensus.io v0.22.1 // indirect
-	golang.org/x/crypto v0.0.0-20190923035154-9ee001bba392 // indirect
 	golang.org/x/exp v0.0.0-20190919035709-81c71964d733 // indirect
 	golang.org/x/image v0.0.0-20190910094157-69e4b8554b2a // indirect
 	golang.org/x/mobile v0.0.0-20190923204409-d3ece3b6da5f // indirect
-	golang.org/x/net v0.0.0-20190923162816-aa69164e4478
+	golang.org/x/net v0.0.0-20200927032502-5d4f70055728
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
 	golang.org/x/time v0.0.0-20190921001708-c4c64cad1fd0 // indirect
 	golang.org/x/tools v0.0.0-20190925153023-9c4a82ab3253 // indirect
 	google.golang.org/api v0.10.0 // indirect
 	google.golang.org/appengine v1.6.3 // indirect
-	google.golang.org/grpc v1.23.1
+	google.golang.org/grpc v1.32.0
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
+
+replace github.com/synerex/synerex_api => ../../api
+
+replace github.com/synerex/synerex_nodeapi => ../../nodeapi
+
+replace github.com/synerex/synerex_sxutil => ../../sxutil
@@ -18,18 +18,24 @@ var (
 	readOnly        = flag.Bool("ro", false, "Read Only flag")
 	name            = flag.String("name", "SimpleGW", "GW Name")
 	servers         = flag.String("servers", "", "Speficy Synerex Server names")
+	tlsOption       = sxutil.TLSFlags() // TLS for nodeserv and synerex servers
 	idlist          []uint64
 	spMap           map[uint64]*sxutil.SupplyOpts
 	mu              sync.Mutex
 	sxServerAddress string
 )
 
-func forwardGatewayMsg(sg api.Synerex_SubscribeGatewayClient, client api.SynerexClient) {
-	ctx := context.Background() //
+func forwardGatewayMsg(sg api.Synerex_SubscribeGatewayClient, client api.SynerexClient, gwId uint64) {
 	for {
 		msg, err := sg.Recv()
 		if err == nil {
-			client.ForwardToGateway(ctx, msg)
+			msg.GatewayId = gwId // server checks the gateway type
+			// continue the trace of the message (SX_TRACE)
+			ctx, span := sxutil.StartSpan(sxutil.ContextWithGatewayMsg(context.Background(), msg), "gateway.forward", sxutil.SpanKindInternal)
+			span.SetAttr("synerex.msg_type", msg.GetMsgType().String())
+			_, ferr := client.ForwardToGateway(ctx, msg)
+			span.SetError(ferr)
+			span.End()
 		} else {
 			log.Printf("Error on gateway receive! :%v", err)
 		}
@@ -38,6 +44,7 @@ func forwardGatewayMsg(sg api.Synerex_SubscribeGatewayClient, client api.Synerex
 
 func main() {
 	flag.Parse()
+	sxutil.SetTLSOption(tlsOption)
 	go sxutil.HandleSigInt()
 	sxutil.RegisterDeferFunction(sxutil.UnRegisterNode)
 
@@ -69,7 +76,7 @@ func main() {
 		Channels:    channels,
 	}
 	ctx := context.Background() //
-	sg0, err := client0.SubscribeGateway(ctx, gi)
+	sg0, err := client0.Client.SubscribeGateway(ctx, gi)
 	if err != nil {
 		log.Printf("Synerex subscribe Error %v\n", err)
 	}
@@ -80,13 +87,13 @@ func main() {
 			log.Printf("Duplicated server!")
 		} else {
 			client1 := sxutil.GrpcConnectServer(servers[1])
-			sg1, err1 := client1.SubscribeGateway(ctx, gi)
+			sg1, err1 := client1.Client.SubscribeGateway(ctx, gi)
 			if err1 != nil {
 				log.Printf("Synerex subscribe Error to %s %v\n", servers[1], err1)
 			}
 			wg.Add(2)
-			go forwardGatewayMsg(sg1, client0)
-			go forwardGatewayMsg(sg0, client1)
+			go forwardGatewayMsg(sg1, client0.Client, gi.ClientId)
+			go forwardGatewayMsg(sg0, client1.Client, gi.ClientId)
 		}
 	} else {
 		for {
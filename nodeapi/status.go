@@ -0,0 +1,7 @@
+package synerex_nodeapi
+
+// node_status of NodeUpdate from synerex servers
+const (
+	NodeStatusRunning  int32 = 0
+	NodeStatusDraining int32 = 1 // server is draining, providers should move to other servers
+)
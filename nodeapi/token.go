@@ -0,0 +1,70 @@
+package synerex_nodeapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node token for authentication of Synerex API calls.
+// Node server signs the token at RegisterNode (and refreshes it at KeepAlive) with the key
+// shared with synerex servers. Providers send it with gRPC metadata TokenMetadataKey.
+// token format: "<node_id>.<expire unix time>.<base64url HMAC-SHA256>"
+
+// TokenMetadataKey is the gRPC metadata key for node token
+const TokenMetadataKey = "sx-token"
+
+// AdminNodeID is the node id of tokens for admin service of synerex servers.
+// Node server never issues it, so admin tokens are signed only by holders of the shared key.
+const AdminNodeID int32 = -1
+
+var (
+	ErrTokenFormat    = errors.New("invalid token format")
+	ErrTokenSignature = errors.New("invalid token signature")
+	ErrTokenExpired   = errors.New("token expired")
+)
+
+func tokenSignature(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignNodeToken creates token for node id which is valid until expire
+func SignNodeToken(key []byte, nodeID int32, expire time.Time) string {
+	payload := fmt.Sprintf("%d.%d", nodeID, expire.Unix())
+	return payload + "." + tokenSignature(key, payload)
+}
+
+// VerifyNodeToken checks the token and returns node id of the token
+func VerifyNodeToken(key []byte, token string, now time.Time) (int32, error) {
+	pos := strings.LastIndex(token, ".")
+	if pos < 0 {
+		return 0, ErrTokenFormat
+	}
+	payload, sig := token[:pos], token[pos+1:]
+	items := strings.Split(payload, ".")
+	if len(items) != 2 {
+		return 0, ErrTokenFormat
+	}
+	if !hmac.Equal([]byte(sig), []byte(tokenSignature(key, payload))) {
+		return 0, ErrTokenSignature
+	}
+	nodeID, err := strconv.ParseInt(items[0], 10, 32)
+	if err != nil {
+		return 0, ErrTokenFormat
+	}
+	expire, err := strconv.ParseInt(items[1], 10, 64)
+	if err != nil {
+		return 0, ErrTokenFormat
+	}
+	if now.Unix() > expire {
+		return 0, ErrTokenExpired
+	}
+	return int32(nodeID), nil
+}
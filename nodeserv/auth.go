@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	nodepb "github.com/synerex/synerex_nodeapi"
+)
+
+// Node token for authentication of Synerex API (synerex servers verify it with the same key)
+
+var (
+	authKey = flag.String("authkey", os.Getenv("SX_AUTH_KEY"), "Key for signing node tokens shared with synerex servers (empty for no auth)")
+	authTTL = flag.Duration("authttl", getAuthTTL(), "Valid duration of node tokens (refreshed by KeepAlive)")
+)
+
+func getAuthTTL() time.Duration {
+	env := os.Getenv("SX_AUTH_TTL")
+	if env != "" {
+		d, _ := time.ParseDuration(env)
+		return d
+	} else {
+		return 24 * time.Hour
+	}
+}
+
+// nodeToken returns signed token for node (empty if auth is disabled)
+func nodeToken(nodeID int32) string {
+	if *authKey == "" {
+		return ""
+	}
+	return nodepb.SignNodeToken([]byte(*authKey), nodeID, time.Now().Add(*authTTL))
+}
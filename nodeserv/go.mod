@@ -7,9 +7,16 @@ require (
 	github.com/google/gops v0.3.13
 	github.com/synerex/synerex_nodeapi v0.5.4
 	github.com/synerex/synerex_nodeserv_controlapi v0.1.1
+	github.com/synerex/synerex_sxutil v0.6.2
 	golang.org/x/net v0.0.0-20201207224615-747e23833adb // indirect
 	golang.org/x/sys v0.0.0-20201207223542-d4d67f95c62d // indirect
 	golang.org/x/text v0.3.4 // indirect
 	google.golang.org/genproto v0.0.0-20201207150747-9ee31aac76e7 // indirect
 	google.golang.org/grpc v1.34.0
 )
+
+replace github.com/synerex/synerex_nodeapi => ../nodeapi
+
+replace github.com/synerex/synerex_sxutil => ../sxutil
+
+replace github.com/synerex/synerex_api => ../api
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net"
+	"os"
+	"time"
+
+	nodepb "github.com/synerex/synerex_nodeapi"
+	"github.com/synerex/synerex_sxutil/sxlog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Levelled loggers of the node server.
+// Levels are given by -loglevel (e.g. "info,nodeserv.keepalive=debug") and changed by SetLogLevel.
+
+var (
+	logLevel  = flag.String("loglevel", getLogLevel(), "Log levels (e.g. \"info,nodeserv.keepalive=debug\")")
+	logFormat = flag.String("logformat", getLogFormat(), "Log format (text or json)")
+)
+
+var (
+	logger       = sxlog.New("nodeserv")
+	keepaliveLog = logger.Component("keepalive") // node listings on keepalive
+)
+
+func getLogLevel() string {
+	env := os.Getenv("SX_LOG_LEVEL")
+	if env != "" {
+		return env
+	} else {
+		return "info"
+	}
+}
+
+func getLogFormat() string {
+	env := os.Getenv("SX_LOG_FORMAT")
+	if env != "" {
+		return env
+	} else {
+		return "text"
+	}
+}
+
+// setupLogging applies log flags, and standard log is written as info of the node server
+func setupLogging() {
+	if err := sxlog.SetLevels(*logLevel); err != nil {
+		logger.Fatalf("Invalid -loglevel: %v", err)
+	}
+	if err := sxlog.SetFormat(*logFormat); err != nil {
+		logger.Fatalf("Invalid -logformat: %v", err)
+	}
+	sxlog.RedirectStdLog(logger)
+}
+
+// checkAdmin allows admin token (with -authkey), or loopback peers without auth
+func checkAdmin(ctx context.Context) error {
+	if *authKey == "" {
+		if pr, ok := peer.FromContext(ctx); ok {
+			if addr, ok := pr.Addr.(*net.TCPAddr); ok && addr.IP.IsLoopback() {
+				return nil
+			}
+		}
+		return status.Error(codes.PermissionDenied, "admin request is allowed only from loopback without -authkey")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(nodepb.TokenMetadataKey)
+	if len(tokens) == 0 {
+		return status.Error(codes.Unauthenticated, "no admin token")
+	}
+	nodeID, err := nodepb.VerifyNodeToken([]byte(*authKey), tokens[0], time.Now())
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if nodeID != nodepb.AdminNodeID {
+		return status.Errorf(codes.PermissionDenied, "node %d is not allowed to set log level", nodeID)
+	}
+	return nil
+}
+
+// SetLogLevel sets level of the component and returns current levels (only returns levels if level is empty)
+func (s *srvNodeInfo) SetLogLevel(ctx context.Context, ll *nodepb.LogLevel) (*nodepb.LogLevelList, error) {
+	if err := checkAdmin(ctx); err != nil {
+		logger.Warnf("SetLogLevel denied: %v", err)
+		return nil, err
+	}
+	if ll.GetLevel() != "" {
+		lv, err := sxlog.ParseLevel(ll.GetLevel())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		sxlog.SetLevel(ll.GetComponent(), lv)
+		logger.Infof("Set log level of %q to %s", ll.GetComponent(), lv)
+	}
+	list := &nodepb.LogLevelList{}
+	for component, lv := range sxlog.Levels() {
+		list.Levels = append(list.Levels, &nodepb.LogLevel{Component: component, Level: lv.String()})
+	}
+	return list, nil
+}
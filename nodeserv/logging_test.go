@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	nodepb "github.com/synerex/synerex_nodeapi"
+	"github.com/synerex/synerex_sxutil/sxlog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 10000}})
+}
+
+func tokenContext(ctx context.Context, nodeID int32) context.Context {
+	token := nodepb.SignNodeToken([]byte(*authKey), nodeID, time.Now().Add(time.Minute))
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(nodepb.TokenMetadataKey, token))
+}
+
+func TestCheckAdmin(t *testing.T) {
+	defer func(key string) { *authKey = key }(*authKey)
+
+	*authKey = ""
+	if err := checkAdmin(peerContext("127.0.0.1")); err != nil {
+		t.Errorf("loopback without authkey: %v", err)
+	}
+	if err := checkAdmin(peerContext("192.0.2.1")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("remote without authkey: %v", err)
+	}
+
+	*authKey = "secret"
+	remote := peerContext("192.0.2.1")
+	if err := checkAdmin(remote); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no token: %v", err)
+	}
+	if err := checkAdmin(peerContext("127.0.0.1")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("loopback without token when authkey is set: %v", err)
+	}
+	if err := checkAdmin(tokenContext(remote, 12)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("node token: %v", err)
+	}
+	if err := checkAdmin(tokenContext(remote, nodepb.AdminNodeID)); err != nil {
+		t.Errorf("admin token: %v", err)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	defer func(key string) { *authKey = key }(*authKey)
+	*authKey = ""
+	defer sxlog.SetLevel("nodeserv.keepalive", sxlog.Levels()[""])
+
+	s := &srvNodeInfo{}
+	ctx := peerContext("127.0.0.1")
+	list, err := s.SetLogLevel(ctx, &nodepb.LogLevel{Component: "nodeserv.keepalive", Level: "debug"})
+	if err != nil {
+		t.Fatalf("SetLogLevel: %v", err)
+	}
+	found := false
+	for _, ll := range list.GetLevels() {
+		if ll.GetComponent() == "nodeserv.keepalive" {
+			found = true
+			if ll.GetLevel() != "debug" {
+				t.Errorf("level = %q, want debug", ll.GetLevel())
+			}
+		}
+	}
+	if !found {
+		t.Errorf("nodeserv.keepalive is not listed in %v", list.GetLevels())
+	}
+
+	if _, err := s.SetLogLevel(ctx, &nodepb.LogLevel{Level: "verbose"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid level: %v", err)
+	}
+	if _, err := s.SetLogLevel(peerContext("192.0.2.1"), &nodepb.LogLevel{Level: "debug"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("remote peer: %v", err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	nodepb "github.com/synerex/synerex_nodeapi"
 	nodecapi "github.com/synerex/synerex_nodeserv_controlapi"
+	sxutil "github.com/synerex/synerex_sxutil"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 )
@@ -72,6 +73,7 @@ type SynerexServerInfo struct {
 	AreaId       string
 	NodeName     string
 	PendingNodes []int32 // Pending for close subscription nodes for next KeepAlive
+	Draining     bool    // server is draining (no new providers)
 }
 
 type SynerexGatewayInfo struct {
@@ -96,6 +98,7 @@ var (
 	version    = flag.Bool("version", getVersion(), "show version")
 	verbose    = flag.Bool("verbose", getVerbose(), "show detailed modules information")
 	restart    = flag.Bool("restart", getRestart(), "Restart flag: if true, load nodeinfo.json ")
+	tlsOption  = sxutil.TLSFlags() // -tls_ca, -tls_cert, -tls_key, -tls_client_auth
 	srvInfo    srvNodeInfo
 	sxProfile        = make([]SynerexServerInfo, 0, 1)
 	lastNode   int32 = MaxServerID // start ID from MAX_SERVER_ID to MAX_NODE_NUM
@@ -214,12 +217,12 @@ func getNextNodeID(nodeType nodepb.NodeType) int32 {
 func loadSxProfile() {
 	bytes, err := ioutil.ReadFile(defaultSxProfile)
 	if err != nil {
-		log.Println("Error on reading sxprofile.json ", err)
+		logger.Warnf("Error on reading sxprofile.json: %v", err)
 		return
 	}
 	jsonErr := json.Unmarshal(bytes, &sxProfile)
 	if jsonErr != nil {
-		log.Println("Can't unmarshall json ", jsonErr)
+		logger.Errorf("Can't unmarshall sxprofile.json: %v", jsonErr)
 		return
 	}
 }
@@ -227,13 +230,13 @@ func loadNodeMap(s *srvNodeInfo) {
 	nmmu.Lock() // not need..
 	bytes, err := ioutil.ReadFile(defaultNodeInfoFile)
 	if err != nil {
-		log.Println("Error on reading nodeinfo.json ", err)
+		logger.Warnf("Error on reading nodeinfo.json: %v", err)
 		return
 	}
 	nodeLists := make([]nodeInfo, 0)
 	jsonErr := json.Unmarshal(bytes, &nodeLists)
 	if jsonErr != nil {
-		log.Println("Can't unmarshall json ", jsonErr)
+		logger.Errorf("Can't unmarshall nodeinfo.json: %v", jsonErr)
 		return
 	}
 	for i, ninfo := range nodeLists {
@@ -248,11 +251,11 @@ func loadNodeMap(s *srvNodeInfo) {
 func saveSxProfile() {
 	bytes, err := json.MarshalIndent(sxProfile, "", "  ")
 	if err != nil {
-		log.Printf("Cant marshal sxprofile")
+		logger.Errorf("Cant marshal sxprofile")
 	}
 	err = ioutil.WriteFile(defaultSxProfile, bytes, 0666)
 	if err != nil {
-		log.Println("Error on writing sxprofile.json ", err)
+		logger.Errorf("Error on writing sxprofile.json: %v", err)
 	}
 }
 
@@ -268,11 +271,11 @@ func saveNodeMap(s *srvNodeInfo) {
 	}
 	bytes, err := json.MarshalIndent(nodeLists, "", "  ")
 	if err != nil {
-		log.Println("Can't Marshal NodeInfo data! ", err)
+		logger.Errorf("Can't Marshal NodeInfo data! %v", err)
 	} else {
 		ferr := ioutil.WriteFile(defaultNodeInfoFile, bytes, 0666)
 		if ferr != nil {
-			log.Println("Error on writing nodeinfo.json ", ferr)
+			logger.Errorf("Error on writing nodeinfo.json: %v", ferr)
 		}
 		saveSxProfile()
 	}
@@ -300,7 +303,7 @@ func appendNonDup(base []int32, add []int32) []int32 {
 func addPendingNodesToServers(killNodes []int32) {
 	for i := range sxProfile {
 		sxProfile[i].PendingNodes = appendNonDup(sxProfile[i].PendingNodes, killNodes)
-		fmt.Printf("SxProfile[%d] = %v", i, sxProfile[i].PendingNodes)
+		logger.Debugf("SxProfile[%d] = %v", i, sxProfile[i].PendingNodes)
 	}
 
 }
@@ -321,7 +324,7 @@ func keepNodes(s *srvNodeInfo) {
 		if len(killNodes) > 0 {
 			// remove nodes
 			// flush nodelist
-			log.Printf("Kill Nodes by SynerexServer Timeout %#v", killNodes)
+			logger.Warnf("Kill Nodes by SynerexServer Timeout %#v", killNodes)
 			for _, k := range killNodes {
 				// we need to remove k from sxProfile
 				ni := s.nodeMap[k]
@@ -358,7 +361,7 @@ func (s *srvNodeInfo) listNodes() {
 	for i := range nk {
 		eni := s.nodeMap[nk[i]]
 		sub := time.Now().Sub(eni.LastAlive) / time.Second
-		log.Printf("%2d[%1d]%20s %-6.6s %-7.7s %14s %3d %2d:%3d %s\n", nk[i], eni.NodeType, eni.NodeName, eni.NodePBase, eni.NodeBinVersion, eni.Address, int(sub), eni.Count, eni.Status, eni.Arg)
+		keepaliveLog.Debugf("%2d[%1d]%20s %-6.6s %-7.7s %14s %3d %2d:%3d %s", nk[i], eni.NodeType, eni.NodeName, eni.NodePBase, eni.NodeBinVersion, eni.Address, int(sub), eni.Count, eni.Status, eni.Arg)
 	}
 	nmmu.RUnlock()
 }
@@ -386,7 +389,7 @@ func getSynerexServerForGw(ServerNames string) string {
 func getSynerexServer(ServerId int32) string {
 	for i := range sxProfile {
 		if ServerId == sxProfile[i].NodeId {
-			log.Printf("Server %d ServerInfo %s\n", ServerId, sxProfile[i].ServerInfo)
+			logger.Debugf("Server %d ServerInfo %s", ServerId, sxProfile[i].ServerInfo)
 			return (sxProfile[i].ServerInfo)
 		}
 	}
@@ -403,7 +406,7 @@ func (s *srvNodeInfo) RegisterNode(cx context.Context, ni *nodepb.NodeInfo) (nid
 		_, ok := s.nodeMap[ni.WithNodeId]
 		if ok {
 			nn := getNextNodeID(ni.NodeType)
-			log.Printf("Duplicated node ID request. Ignore %d and assign id %d", ni.WithNodeId, nn)
+			logger.Warnf("Duplicated node ID request. Ignore %d and assign id %d", ni.WithNodeId, nn)
 			n = nn
 		} else {
 			n = ni.WithNodeId
@@ -437,7 +440,7 @@ func (s *srvNodeInfo) RegisterNode(cx context.Context, ni *nodepb.NodeInfo) (nid
 		Duration: DefaultDuration,
 	}
 
-	log.Println("Node Connection from :", ipaddr, ",", ni.NodeName)
+	logger.Infof("Node Connection from : %s, %s", ipaddr, ni.NodeName)
 	nmmu.Lock()
 
 	s.nodeMap[n] = &eni
@@ -451,6 +454,7 @@ func (s *srvNodeInfo) RegisterNode(cx context.Context, ni *nodepb.NodeInfo) (nid
 				sxProfile[k].ClusterId = ni.ClusterId
 				sxProfile[k].AreaId = ni.AreaId
 				sxProfile[k].NodeName = ni.NodeName
+				sxProfile[k].Draining = false
 				break
 			}
 		}
@@ -469,7 +473,7 @@ func (s *srvNodeInfo) RegisterNode(cx context.Context, ni *nodepb.NodeInfo) (nid
 
 	}
 	nmmu.Unlock()
-	log.Println("------------------------------------------------------")
+	keepaliveLog.Debugf("------------------------------------------------------")
 	s.listNodes()
 	//	log.Println("------------------------------------------------------")
 
@@ -495,6 +499,7 @@ func (s *srvNodeInfo) RegisterNode(cx context.Context, ni *nodepb.NodeInfo) (nid
 		Secret:            r,
 		ServerInfo:        serverInfo,
 		KeepaliveDuration: eni.Duration,
+		Token:             nodeToken(n),
 	}
 	saveNodeMap(s)
 
@@ -509,10 +514,10 @@ func (s *srvNodeInfo) QueryNode(cx context.Context, nid *nodepb.NodeID) (ni *nod
 	n := nid.NodeId
 	eni, ok := s.nodeMap[n]
 	if !ok {
-		fmt.Println("QueryNode: Can't find Node ID:", n)
+		logger.Warnf("QueryNode: Can't find Node ID: %d", n)
 		return nil, errors.New("unregistered NodeID")
 	}
-	ni = &nodepb.NodeInfo{NodeName: eni.NodeName}
+	ni = &nodepb.NodeInfo{NodeName: eni.NodeName, NodeType: eni.NodeType}
 	return ni, nil
 }
 
@@ -522,7 +527,7 @@ func (s *srvNodeInfo) KeepAlive(ctx context.Context, nu *nodepb.NodeUpdate) (nr
 	ni, ok := s.nodeMap[nid]
 	if !ok {
 		// TODO: For enhance security, we need to profile the provider which connect with wrong NodeID.
-		fmt.Println("Can't find node... nodeserv might be restarted or ... :", nid)
+		logger.Warnf("Can't find node... nodeserv might be restarted or ... : %d", nid)
 		pr, ok := peer.FromContext(ctx)
 		var ipaddr string
 		if ok {
@@ -530,8 +535,7 @@ func (s *srvNodeInfo) KeepAlive(ctx context.Context, nu *nodepb.NodeUpdate) (nr
 		} else {
 			ipaddr = "0.0.0.0"
 		}
-		fmt.Println("Client from :", ipaddr)
-		fmt.Println("") // debug workaround
+		logger.Debugf("Client from : %s", ipaddr)
 		return &nodepb.Response{Ok: false, Command: nodepb.KeepAliveCommand_RECONNECT, Err: "Killed at Nodeserv"}, nil
 	}
 	if r != ni.Secret {
@@ -542,38 +546,60 @@ func (s *srvNodeInfo) KeepAlive(ctx context.Context, nu *nodepb.NodeUpdate) (nr
 	ni.Count = nu.UpdateCount
 	ni.Status = nu.NodeStatus
 	ni.Arg = nu.NodeArg
+	if ni.NodeType == nodepb.NodeType_SERVER || len(nu.ChannelTypes) > 0 { // channel types in use
+		ni.ChannelTypes = nu.ChannelTypes
+		if ni.NodeType == nodepb.NodeType_SERVER {
+			nmmu.Lock()
+			for i := range sxProfile {
+				if sxProfile[i].NodeId == nid {
+					sxProfile[i].ChannelTypes = nu.ChannelTypes
+					break
+				}
+			}
+			nmmu.Unlock()
+		}
+	}
+	if ni.NodeType == nodepb.NodeType_SERVER && nu.NodeStatus == nodepb.NodeStatusDraining {
+		drainServer(nid)
+	}
 
 	if ni.LastAlive.Sub(lastPrint) > time.Second*time.Duration(DefaultDuration/2) {
-		log.Println("---KeepAlive------------------------------------------")
+		keepaliveLog.Debugf("---KeepAlive------------------------------------------")
 		s.listNodes()
 		//		log.Println("------------------------------------------------------")
 	}
 
 	if ni.NodeType == nodepb.NodeType_SERVER { // if there is pending nodes, send them!
 		//		log.Printf("KeepAlive from Server %#v", ni)
+		var pending []byte
+		nmmu.Lock()
 		for i := range sxProfile {
 			if sxProfile[i].NodeId == nid {
 				if len(sxProfile[i].PendingNodes) > 0 {
-					bytes, _ := json.Marshal(sxProfile[i].PendingNodes)
+					pending, _ = json.Marshal(sxProfile[i].PendingNodes)
 					sxProfile[i].PendingNodes = []int32{} // clean nodes
-					log.Printf("Sending Pending Nodes to SxServ %s", string(bytes))
-					return &nodepb.Response{
-						Ok:      true,
-						Command: nodepb.KeepAliveCommand_PROVIDER_DISCONNECT,
-						Err:     string(bytes),
-					}, nil
 				}
 				break
 			}
 		}
+		nmmu.Unlock()
+		if pending != nil {
+			logger.Infof("Sending Pending Nodes to SxServ %s", string(pending))
+			return &nodepb.Response{
+				Ok:      true,
+				Command: nodepb.KeepAliveCommand_PROVIDER_DISCONNECT,
+				Err:     string(pending),
+				Token:   nodeToken(nid),
+			}, nil
+		}
 	}
 	// Returning SERVER_CHANGE command if threre is server change request for the provider
 	if IsServerChangeRequest(nid) {
-		log.Printf("Returning SERVER_CHANGE command\n")
+		logger.Infof("Returning SERVER_CHANGE command")
 		return &nodepb.Response{Ok: false, Command: nodepb.KeepAliveCommand_SERVER_CHANGE, Err: ""}, nil
 	}
 
-	return &nodepb.Response{Ok: true, Command: nodepb.KeepAliveCommand_NONE, Err: ""}, nil
+	return &nodepb.Response{Ok: true, Command: nodepb.KeepAliveCommand_NONE, Err: "", Token: nodeToken(nid)}, nil
 }
 
 func (s *srvNodeInfo) UnRegisterNode(cx context.Context, nid *nodepb.NodeID) (nr *nodepb.Response, e error) {
@@ -581,13 +607,13 @@ func (s *srvNodeInfo) UnRegisterNode(cx context.Context, nid *nodepb.NodeID) (nr
 	n := nid.NodeId
 	ni, ok := s.nodeMap[n]
 	if !ok {
-		fmt.Printf("Can't find node... It's killed")
+		logger.Warnf("Can't find node... It's killed")
 		return &nodepb.Response{Ok: false, Err: "Killed at Nodeserv"}, e
 	}
 
 	if r != ni.Secret { // secret failed
 		e = errors.New("Secret Failed")
-		log.Println("Invalid unregister")
+		logger.Warnf("Invalid unregister")
 		return &nodepb.Response{Ok: false, Err: "Secret Failed"}, e
 	}
 
@@ -601,7 +627,7 @@ func (s *srvNodeInfo) UnRegisterNode(cx context.Context, nid *nodepb.NodeID) (nr
 		}
 	}
 
-	log.Println("----------- Delete Node -----------", n, s.nodeMap[n].NodeName)
+	logger.Infof("----------- Delete Node ----------- %d %s", n, s.nodeMap[n].NodeName)
 	nmmu.Lock()
 	delete(s.nodeMap, n)
 	nmmu.Unlock()
@@ -696,7 +722,7 @@ func (s *srvNodeInfo) ControlNodes(ctx context.Context, in *nodecapi.Order) (res
 	if in.OrderType == nodecapi.OrderType_SWITCH_SERVER {
 		Provider := in.TargetNode.NodeId
 		Server := in.GetSwitchInfo().SxServer.NodeId
-		log.Printf("%d switch to %d\n", Provider, Server)
+		logger.Infof("%d switch to %d", Provider, Server)
 		AddServerChangeRequest(Provider, Server)
 	}
 
@@ -717,6 +743,7 @@ func main() {
 	// get debug information
 	bi, ok := debug.ReadBuildInfo()
 	flag.Parse()
+	setupLogging()
 	if ok {
 		if *verbose {
 			log.Printf("%s(%s) built %s sha1 %s", bi.Main.Path, gitver, buildTime, sha1ver)
@@ -737,7 +764,7 @@ func main() {
 	}
 
 	if gerr := agent.Listen(agent.Options{}); gerr != nil {
-		log.Fatal(gerr)
+		logger.Fatalf("%v", gerr)
 	}
 
 	// loading nodeinfo from file
@@ -751,10 +778,13 @@ func main() {
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", *addr, *port))
 
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		logger.Fatalf("failed to listen: %v", err)
 	}
 
-	var opts []grpc.ServerOption
+	opts, err := tlsOption.ServerOptions()
+	if err != nil {
+		logger.Fatalf("failed to load TLS certificates: %v", err)
+	}
 
 	nodeServer := prepareGrpcServer(opts...)
 
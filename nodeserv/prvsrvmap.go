@@ -1,11 +1,5 @@
 package main
 
-import (
-	//	"context"
-	"log"
-	//	nodecapi "github.com/synerex/synerex_nodeserv_controlapi"
-)
-
 type NodeServInfo struct {
 	PrvNodeId int32
 	SrvNodeId int32
@@ -20,6 +14,8 @@ var ConnectionMap = make([]NodeServInfo, 0, 1)
 var ChangeSrvList = make([]ChangeServInfo, 0, 1)
 
 func UpdateConnectionMap(PrvId int32, SrvId int32) {
+	nmmu.Lock()
+	defer nmmu.Unlock()
 
 	existFlag := false
 	for ii := range ConnectionMap {
@@ -56,17 +52,53 @@ func GetServerIdForPrv(PrvId int32) int32 {
 			return (SrvId)
 		}
 	}
-	if len(sxProfile) > 0 {
-		return sxProfile[0].NodeId
+	if srvId, ok := getActiveServerId(-1); ok {
+		return srvId
 	}
 	return 0 // default server is 0.
 }
 
+// getActiveServerId returns a server which is not draining (except exceptId)
+func getActiveServerId(exceptId int32) (int32, bool) {
+	for i := range sxProfile {
+		if !sxProfile[i].Draining && sxProfile[i].NodeId != exceptId {
+			return sxProfile[i].NodeId, true
+		}
+	}
+	return 0, false
+}
+
+// drainServer requests providers connected to draining server to change server
+func drainServer(SrvId int32) {
+	nmmu.Lock()
+	defer nmmu.Unlock()
+	for i := range sxProfile {
+		if sxProfile[i].NodeId == SrvId {
+			if sxProfile[i].Draining {
+				return // already requested
+			}
+			sxProfile[i].Draining = true
+			break
+		}
+	}
+	newSrvId, ok := getActiveServerId(SrvId)
+	if !ok {
+		logger.Warnf("Server %d is draining, but no other server for providers", SrvId)
+		return
+	}
+	for ii := range ConnectionMap {
+		if ConnectionMap[ii].SrvNodeId == SrvId && !IsServerChangeRequest(ConnectionMap[ii].PrvNodeId) {
+			logger.Infof("Server %d is draining, provider %d switch to %d", SrvId, ConnectionMap[ii].PrvNodeId, newSrvId)
+			AddServerChangeRequest(ConnectionMap[ii].PrvNodeId, newSrvId)
+		}
+	}
+}
+
 func IsServerChangeRequest(PrvId int32) bool {
 
 	for k := range ChangeSrvList {
 		if PrvId == ChangeSrvList[k].PrvId {
-			log.Printf("ServerChangeRequest for %d connected to %d\n ",
+			logger.Infof("ServerChangeRequest for %d connected to %d",
 				ChangeSrvList[k].PrvId, ChangeSrvList[k].SrvId)
 			return true
 		}
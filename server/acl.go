@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Channel access control list (ACL) policy file.
+// Each entry is a node name, a node id or "*" (all nodes).
+//
+//	{
+//	  "default":  {"publish": ["*"], "subscribe": ["*"]},
+//	  "channels": {"14": {"publish": ["PeopleCounter", "12"], "subscribe": ["Dashboard"]}},
+//	  "mbus":     ["*"],
+//	  "gateway":  ["Gateway"]
+//	}
+//
+// Actions of channels are publish (Notify/Publish), propose (Propose), subscribe (Subscribe/Close channel),
+// select (Select/Confirm) and mbus (Select/Confirm which opens mbus).
+// If the channel has no rule for the action, the rule of "default" is used.
+// If there is no rule, the action is allowed. "mbus" is for all mbus RPCs.
+// "gateway" is for SubscribeGateway/ForwardToGateway, and gateways also need subscribe on the
+// forwarded channels (all channels with rules if no channels are given) and publish on forwarded messages.
+// Nodes are identified by the node token (-authkey is required with ACL).
+// The policy file is reloaded when it is modified.
+
+const (
+	aclPublish   = "publish"
+	aclPropose   = "propose"
+	aclSubscribe = "subscribe"
+	aclSelect    = "select"
+	aclMbus      = "mbus"
+)
+
+const aclNameCacheTime = time.Minute
+
+// aclNodes is a list of node names or ids (both string and number are accepted in json)
+type aclNodes struct {
+	all   bool
+	ids   map[int32]bool
+	names map[string]bool
+}
+
+func (an *aclNodes) UnmarshalJSON(data []byte) error {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	an.ids = make(map[int32]bool)
+	an.names = make(map[string]bool)
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			an.ids[int32(v)] = true
+		case string:
+			if v == "*" {
+				an.all = true
+			} else if id, err := strconv.ParseInt(v, 10, 32); err == nil {
+				an.ids[int32(id)] = true
+			} else {
+				an.names[v] = true
+			}
+		default:
+			return fmt.Errorf("invalid acl entry %v", item)
+		}
+	}
+	return nil
+}
+
+type aclRule map[string]*aclNodes // action -> nodes
+
+type aclPolicy struct {
+	Default  aclRule            `json:"default"`
+	Channels map[string]aclRule `json:"channels"`
+	Mbus     *aclNodes          `json:"mbus"`
+	Gateway  *aclNodes          `json:"gateway"`
+	channels map[uint32]aclRule
+}
+
+type aclNodeName struct {
+	name string
+	ts   time.Time
+}
+
+// aclStore keeps current policy and reloads the file
+type aclStore struct {
+	path    string
+	modTime time.Time
+	policy  *aclPolicy
+	names   map[int32]aclNodeName // cache of node names from nodeserv
+	mutex   sync.RWMutex
+}
+
+func loadACLPolicy(fname string) (*aclPolicy, error) {
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	policy := &aclPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	policy.channels = make(map[uint32]aclRule)
+	for ch, rule := range policy.Channels {
+		ctype, err := strconv.ParseUint(ch, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel type %q in acl", ch)
+		}
+		policy.channels[uint32(ctype)] = rule
+	}
+	return policy, nil
+}
+
+func newACLStore(fname string) (*aclStore, error) {
+	as := &aclStore{
+		path:  fname,
+		names: make(map[int32]aclNodeName),
+	}
+	if err := as.reload(); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// reload loads the policy file if modified
+func (as *aclStore) reload() error {
+	fi, err := os.Stat(as.path)
+	if err != nil {
+		return err
+	}
+	as.mutex.RLock()
+	modified := !fi.ModTime().Equal(as.modTime)
+	as.mutex.RUnlock()
+	if !modified {
+		return nil
+	}
+	policy, err := loadACLPolicy(as.path)
+	if err != nil {
+		return err
+	}
+	as.mutex.Lock()
+	as.policy = policy
+	as.modTime = fi.ModTime()
+	as.mutex.Unlock()
+	logger.Component("acl").Infof("Load ACL policy %s with %d channels", as.path, len(policy.channels))
+	return nil
+}
+
+func (as *aclStore) reloadLoop(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if err := as.reload(); err != nil { // keep current policy
+			logger.Component("acl").Warnf("Can't reload ACL policy %s: %v", as.path, err)
+		}
+	}
+}
+
+// nodeName returns node name from nodeserv (cached)
+func (as *aclStore) nodeName(nodeID int32) string {
+	as.mutex.RLock()
+	nn, ok := as.names[nodeID]
+	as.mutex.RUnlock()
+	if ok && time.Since(nn.ts) < aclNameCacheTime {
+		return nn.name
+	}
+	name := sxutil.GetNodeName(int(nodeID))
+	as.mutex.Lock()
+	as.names[nodeID] = aclNodeName{name, time.Now()}
+	as.mutex.Unlock()
+	return name
+}
+
+func (as *aclStore) allowedNodes(an *aclNodes, nodeID int32) bool {
+	if an == nil || an.all || an.ids[nodeID] {
+		return true
+	}
+	if len(an.names) == 0 {
+		return false
+	}
+	return an.names[as.nodeName(nodeID)]
+}
+
+// allowed checks the action of the node on the channel
+func (as *aclStore) allowed(action string, ctype uint32, nodeID int32) bool {
+	as.mutex.RLock()
+	policy := as.policy
+	as.mutex.RUnlock()
+	an := policy.Default[action]
+	if rule, ok := policy.channels[ctype]; ok && rule[action] != nil {
+		an = rule[action]
+	}
+	return as.allowedNodes(an, nodeID)
+}
+
+// allowedMbus checks mbus RPCs of the node
+func (as *aclStore) allowedMbus(nodeID int32) bool {
+	as.mutex.RLock()
+	policy := as.policy
+	as.mutex.RUnlock()
+	return as.allowedNodes(policy.Mbus, nodeID)
+}
+
+// allowedGateway checks gateway RPCs of the node
+func (as *aclStore) allowedGateway(nodeID int32) bool {
+	as.mutex.RLock()
+	policy := as.policy
+	as.mutex.RUnlock()
+	return as.allowedNodes(policy.Gateway, nodeID)
+}
+
+// ruleChannels returns channel types which have rules (for gateways of all channels)
+func (as *aclStore) ruleChannels() []uint32 {
+	as.mutex.RLock()
+	policy := as.policy
+	as.mutex.RUnlock()
+	ctypes := make([]uint32, 0, len(policy.channels))
+	for ctype := range policy.channels {
+		ctypes = append(ctypes, ctype)
+	}
+	return ctypes
+}
+
+// aclRequest returns actions, channel type and client id of the request
+func aclRequest(method string, req interface{}) (actions []string, ctype uint32, id uint64) {
+	switch r := req.(type) {
+	case *api.Demand:
+		ctype, id = r.GetChannelType(), r.GetSenderId()
+	case *api.Supply:
+		ctype, id = r.GetChannelType(), r.GetSenderId()
+	case *api.Target:
+		ctype, id = r.GetChannelType(), r.GetSenderId()
+	case *api.Channel:
+		ctype, id = r.GetChannelType(), r.GetClientId()
+	case *api.Mbus:
+		id = r.GetClientId()
+	case *api.MbusMsg:
+		id = r.GetSenderId()
+	case *api.MbusOpt:
+		id = r.GetClientId()
+	case *api.MbusMembers:
+		id = r.GetClientId()
+	case *api.GatewayInfo:
+		id = r.GetClientId()
+	case *api.GatewayMsg:
+		id = r.GetGatewayId()
+		switch gm := r.GetMsgOneof().(type) {
+		case *api.GatewayMsg_Demand:
+			ctype = gm.Demand.GetChannelType()
+		case *api.GatewayMsg_Supply:
+			ctype = gm.Supply.GetChannelType()
+		}
+	}
+	switch method {
+	case "NotifyDemand", "NotifySupply", "PublishDemand", "PublishSupply":
+		actions = []string{aclPublish}
+	case "ProposeDemand", "ProposeSupply":
+		actions = []string{aclPropose}
+	case "SubscribeDemand", "SubscribeSupply", "CloseDemandChannel", "CloseSupplyChannel":
+		actions = []string{aclSubscribe}
+	case "ForwardToGateway":
+		if ctype != 0 { // demand or supply
+			actions = []string{aclPublish}
+		}
+	case "SelectSupply", "SelectDemand", "Confirm":
+		actions = []string{aclSelect, aclMbus}
+	}
+	return actions, ctype, id
+}
+
+// check returns PermissionDenied if the request is not allowed.
+// The node is the authenticated node of ctx (node of the client id in the request without auth).
+func (as *aclStore) check(ctx context.Context, method string, req interface{}) error {
+	actions, ctype, id := aclRequest(method, req)
+	nodeID, ok := authenticatedNode(ctx)
+	if !ok {
+		nodeID = idNodeID(id)
+	}
+	switch method {
+	case "CreateMbus", "SubscribeMbus", "SendMbusMsg", "CloseMbus", "GetMbusState",
+		"AddMbusMembers", "RemoveMbusMembers", "WatchMbus":
+		if !as.allowedMbus(nodeID) {
+			return status.Errorf(codes.PermissionDenied, "node %d is not allowed to use mbus", nodeID)
+		}
+		return nil
+	case "SubscribeGateway", "ForwardToGateway":
+		if !as.allowedGateway(nodeID) {
+			return status.Errorf(codes.PermissionDenied, "node %d is not allowed to be a gateway", nodeID)
+		}
+		if gi, ok := req.(*api.GatewayInfo); ok {
+			ctypes := gi.GetChannels()
+			if len(ctypes) == 0 { // all channels
+				ctypes = append(as.ruleChannels(), 0) // 0 for default rule
+			}
+			for _, ct := range ctypes {
+				if !as.allowed(aclSubscribe, ct, nodeID) {
+					return status.Errorf(codes.PermissionDenied, "gateway node %d is not allowed to subscribe channel %d", nodeID, ct)
+				}
+			}
+		}
+	}
+	for _, action := range actions {
+		if !as.allowed(action, ctype, nodeID) {
+			return status.Errorf(codes.PermissionDenied, "node %d is not allowed to %s on channel %d", nodeID, action, ctype)
+		}
+	}
+	return nil
+}
+
+// aclServerStream checks the request of server streaming RPC
+type aclServerStream struct {
+	grpc.ServerStream
+	as     *aclStore
+	method string
+}
+
+func (as *aclServerStream) RecvMsg(m interface{}) error {
+	if err := as.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return as.as.check(as.Context(), as.method, m)
+}
+
+func aclStreamInterceptor(as *aclStore) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &aclServerStream{ServerStream: stream, as: as, method: path.Base(info.FullMethod)})
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// client id (snowflake) of the node
+func nodeClientID(nodeID int32) uint64 {
+	return uint64(nodeID) << nodeShift
+}
+
+func newTestACL(t *testing.T, policy string) *aclStore {
+	dir, err := ioutil.TempDir("", "acl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	fname := filepath.Join(dir, "acl.json")
+	if err := ioutil.WriteFile(fname, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	as, err := newACLStore(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int32{5, 12, 13, 20} { // avoid asking nodeserv
+		as.names[id] = aclNodeName{"Unknown", time.Now()}
+	}
+	as.names[3] = aclNodeName{"Dashboard", time.Now()}
+	return as
+}
+
+const testPolicy = `{
+  "default":  {"publish": ["*"], "subscribe": ["*"]},
+  "channels": {"14": {"publish": [12, "13"], "subscribe": ["Dashboard"]}},
+  "mbus":     [12],
+  "gateway":  [20]
+}`
+
+func TestACLCheck(t *testing.T) {
+	as := newTestACL(t, testPolicy)
+	gm := func(ctype uint32) *api.GatewayMsg {
+		return &api.GatewayMsg{GatewayId: nodeClientID(20), MsgType: api.MsgType_SUPPLY,
+			MsgOneof: &api.GatewayMsg_Supply{Supply: &api.Supply{ChannelType: ctype}}}
+	}
+	tests := []struct {
+		name    string
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		{"publish by id", "NotifySupply", &api.Supply{SenderId: nodeClientID(12), ChannelType: 14}, true},
+		{"publish by string id", "NotifyDemand", &api.Demand{SenderId: nodeClientID(13), ChannelType: 14}, true},
+		{"publish denied", "NotifySupply", &api.Supply{SenderId: nodeClientID(5), ChannelType: 14}, false},
+		{"publish stream denied", "PublishSupply", &api.Supply{SenderId: nodeClientID(5), ChannelType: 14}, false},
+		{"publish default", "NotifySupply", &api.Supply{SenderId: nodeClientID(5), ChannelType: 11}, true},
+		{"no rule", "ProposeSupply", &api.Supply{SenderId: nodeClientID(5), ChannelType: 14}, true},
+		{"subscribe by name", "SubscribeSupply", &api.Channel{ClientId: nodeClientID(3), ChannelType: 14}, true},
+		{"subscribe denied", "SubscribeSupply", &api.Channel{ClientId: nodeClientID(12), ChannelType: 14}, false},
+		{"close channel denied", "CloseSupplyChannel", &api.Channel{ClientId: nodeClientID(12), ChannelType: 14}, false},
+		{"mbus", "SendMbusMsg", &api.MbusMsg{SenderId: nodeClientID(12)}, true},
+		{"mbus denied", "SendMbusMsg", &api.MbusMsg{SenderId: nodeClientID(5)}, false},
+		{"mbus members denied", "AddMbusMembers", &api.MbusMembers{ClientId: nodeClientID(5)}, false},
+		{"watch mbus denied", "WatchMbus", &api.Mbus{ClientId: nodeClientID(5)}, false},
+		{"gateway denied", "SubscribeGateway", &api.GatewayInfo{ClientId: nodeClientID(5), Channels: []uint32{11}}, false},
+		{"gateway channel", "SubscribeGateway", &api.GatewayInfo{ClientId: nodeClientID(20), Channels: []uint32{11}}, true},
+		{"gateway restricted channel", "SubscribeGateway", &api.GatewayInfo{ClientId: nodeClientID(20), Channels: []uint32{14}}, false},
+		{"gateway all channels", "SubscribeGateway", &api.GatewayInfo{ClientId: nodeClientID(20)}, false},
+		{"forward", "ForwardToGateway", gm(11), true},
+		{"forward restricted channel", "ForwardToGateway", gm(14), false},
+	}
+	for _, tt := range tests {
+		err := as.check(context.Background(), tt.method, tt.req)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s: got %v, allowed %v", tt.name, err, tt.allowed)
+		}
+		if err != nil && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s: code %v", tt.name, status.Code(err))
+		}
+	}
+}
+
+func TestACLAuthenticatedNode(t *testing.T) {
+	as := newTestACL(t, testPolicy)
+	// client id of node 12 is declared, but the token is node 5
+	ctx := context.WithValue(context.Background(), authNodeKey{}, int32(5))
+	if err := as.check(ctx, "NotifySupply", &api.Supply{SenderId: nodeClientID(12), ChannelType: 14}); err == nil {
+		t.Error("spoofed sender is allowed")
+	}
+	ctx = context.WithValue(context.Background(), authNodeKey{}, int32(12))
+	if err := as.check(ctx, "NotifySupply", &api.Supply{SenderId: nodeClientID(5), ChannelType: 14}); err != nil {
+		t.Errorf("authenticated node is denied: %v", err)
+	}
+}
+
+func TestLoadACLPolicyError(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "acl")
+	defer os.RemoveAll(dir)
+	for _, policy := range []string{
+		`{"channels": {"abc": {}}}`,
+		`{"default": {"publish": [true]}}`,
+		`{`,
+	} {
+		fname := filepath.Join(dir, "acl.json")
+		ioutil.WriteFile(fname, []byte(policy), 0644)
+		if _, err := loadACLPolicy(fname); err == nil {
+			t.Errorf("no error for %s", policy)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+
+	api "github.com/synerex/synerex_api"
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Admin service for introspection and management of live state.
+// It is served on a separate address (disabled by default).
+// With -authkey, requests need an admin token (node token of nodeapi.AdminNodeID signed with the key).
+// Without -authkey and mutual TLS, it is served only on loopback addresses.
+
+type adminServer struct {
+	s *synerexServerInfo
+}
+
+var errClosedByAdmin = status.Error(codes.Aborted, "subscriber is closed by admin")
+
+func adminFilterMatch(af *api.AdminFilter, ctype uint32, id uint64) bool {
+	if af.GetChannelType() != 0 && af.GetChannelType() != ctype {
+		return false
+	}
+	if af.GetNodeId() != 0 && af.GetNodeId() != idNodeID(id) {
+		return false
+	}
+	return true
+}
+
+func appendSubscribers(list []*api.SubscriberInfo, af *api.AdminFilter, chans map[uint32][]*subscriber, mtype api.MsgType) []*api.SubscriberInfo {
+	for ctype, chs := range chans {
+		for _, sb := range chs {
+			if !adminFilterMatch(af, ctype, uint64(sb.clientID)) {
+				continue
+			}
+			list = append(list, &api.SubscriberInfo{
+				ClientId:    uint64(sb.clientID),
+				NodeId:      idNodeID(uint64(sb.clientID)),
+				ChannelType: ctype,
+				MsgType:     mtype,
+				QueueLen:    int32(len(sb.ch)),
+				QueueCap:    int32(cap(sb.ch)),
+				Filtered:    sb.filter != nil,
+			})
+		}
+	}
+	return list
+}
+
+func (as *adminServer) ListSubscribers(ctx context.Context, af *api.AdminFilter) (*api.SubscriberList, error) {
+	s := as.s
+	var list []*api.SubscriberInfo
+	s.dmu.RLock()
+	list = appendSubscribers(list, af, s.demandChans, api.MsgType_DEMAND)
+	s.dmu.RUnlock()
+	s.smu.RLock()
+	list = appendSubscribers(list, af, s.supplyChans, api.MsgType_SUPPLY)
+	s.smu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ChannelType != list[j].ChannelType {
+			return list[i].ChannelType < list[j].ChannelType
+		}
+		if list[i].MsgType != list[j].MsgType {
+			return list[i].MsgType < list[j].MsgType
+		}
+		return list[i].ClientId < list[j].ClientId
+	})
+	return &api.SubscriberList{Subscribers: list}, nil
+}
+
+func (as *adminServer) ListGateways(ctx context.Context, af *api.AdminFilter) (*api.GatewayList, error) {
+	s := as.s
+	var list []*api.GatewayEntry
+	s.gmu.RLock()
+	for _, gw := range s.gatewayMap {
+		if !adminFilterMatch(af, 0, uint64(gw.id)) {
+			continue
+		}
+		ge := &api.GatewayEntry{
+			ClientId:    uint64(gw.id),
+			NodeId:      idNodeID(uint64(gw.id)),
+			GatewayType: gw.gtype,
+			QueueLen:    int32(len(gw.ch)),
+		}
+		for ctype := range gw.channels {
+			ge.Channels = append(ge.Channels, ctype)
+		}
+		sort.Slice(ge.Channels, func(i, j int) bool { return ge.Channels[i] < ge.Channels[j] })
+		list = append(list, ge)
+	}
+	s.gmu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ClientId < list[j].ClientId })
+	return &api.GatewayList{Gateways: list}, nil
+}
+
+func (as *adminServer) ListMbus(ctx context.Context, af *api.AdminFilter) (*api.MbusList, error) {
+	s := as.s
+	var list []*api.MbusEntry
+	s.mmu.RLock()
+	for id, mi := range s.mbusInfos {
+		if af.GetNodeId() != 0 && af.GetNodeId() != idNodeID(uint64(mi.owner)) {
+			continue
+		}
+		list = append(list, &api.MbusEntry{
+			MbusId:      id,
+			Owner:       uint64(mi.owner),
+			Private:     mi.private,
+			Status:      mi.status,
+			Subscribers: mi.subscriberIDs(),
+			Pending:     int32(len(mi.pending)),
+		})
+	}
+	s.mmu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].MbusId < list[j].MbusId })
+	return &api.MbusList{Mbuses: list}, nil
+}
+
+func (as *adminServer) CloseSubscriber(ctx context.Context, si *api.SubscriberInfo) (*api.Response, error) {
+	s := as.s
+	idt := sxutil.IDType(si.GetClientId())
+	tp := si.GetChannelType()
+	var sb *subscriber
+	switch si.GetMsgType() {
+	case api.MsgType_DEMAND:
+		s.dmu.Lock()
+		if sb = s.demandMap[tp][idt]; sb != nil {
+			removeSubscriber(s.demandChans, s.demandMap, sb)
+		}
+		s.dmu.Unlock()
+	case api.MsgType_SUPPLY:
+		s.smu.Lock()
+		if sb = s.supplyMap[tp][idt]; sb != nil {
+			removeSubscriber(s.supplyChans, s.supplyMap, sb)
+		}
+		s.smu.Unlock()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid subscriber type %v", si.GetMsgType())
+	}
+	if sb == nil {
+		msg := fmt.Sprintf("Cannot find %v subscriber %d channel %d", si.GetMsgType(), idt, tp)
+		return &api.Response{Ok: false, Err: msg}, nil
+	}
+	sb.finish(errClosedByAdmin)
+	s.updateNodeChannels()
+	adminLog.Infof("Admin closed %v subscriber %d channel %d", si.GetMsgType(), idt, tp)
+	return &api.Response{Ok: true}, nil
+}
+
+func (as *adminServer) ForceCloseMbus(ctx context.Context, mb *api.Mbus) (*api.Response, error) {
+	s := as.s
+	s.mmu.Lock()
+	mi, ok := s.mbusInfos[mb.GetMbusId()]
+	if !ok {
+		s.mmu.Unlock()
+		return &api.Response{Ok: false, Err: fmt.Sprintf("Cannot find mbus %d", mb.GetMbusId())}, nil
+	}
+	mi.close()
+	okFlag, okMsg := s.closeMbusChans(mb.GetMbusId())
+	s.mmu.Unlock()
+	if s.hasGateway() {
+		s.sendGatewayMsg(mbusEventGatewayMsg(mb.GetMbusId(), api.MbusEvent_CLOSED, 0))
+	}
+	adminLog.Infof("Admin closed mbus %d", mb.GetMbusId())
+	return &api.Response{Ok: okFlag, Err: okMsg}, nil
+}
+
+// adminUnaryInterceptor checks admin token of the request
+func adminUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	nodeID, err := authNodeID(ctx)
+	if err == nil && nodeID != nodeapi.AdminNodeID {
+		err = status.Errorf(codes.PermissionDenied, "node %d is not allowed to use admin service", nodeID)
+	}
+	if err != nil {
+		adminLog.Warnf("method %s, auth failed %v", path.Base(info.FullMethod), err)
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// isLoopbackAddr returns true if addr listens only on loopback interface
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// startAdminServer serves admin service at addr (clientAuth is true with mutual TLS)
+func (s *synerexServerInfo) startAdminServer(addr string, clientAuth bool, opts ...grpc.ServerOption) {
+	if *authKey != "" {
+		opts = append(opts, grpc.UnaryInterceptor(adminUnaryInterceptor))
+	} else if !clientAuth {
+		if !isLoopbackAddr(addr) {
+			adminLog.Errorf("admin service at %s requires -authkey or mutual TLS (only loopback address without them)", addr)
+			return
+		}
+		adminLog.Warnf("admin service at %s is not authenticated", addr)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		adminLog.Errorf("failed to listen admin address %s: %v", addr, err)
+		return
+	}
+	gs := grpc.NewServer(opts...)
+	api.RegisterSynerexAdminServer(gs, &adminServer{s: s})
+	adminLog.Infof("Start admin service at %s", addr)
+	if err := gs.Serve(lis); err != nil {
+		adminLog.Errorf("Admin service error %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminUnaryInterceptor(t *testing.T) {
+	orig := *authKey
+	*authKey = "testkey"
+	defer func() { *authKey = orig }()
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.SynerexAdmin/Drain"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	exp := time.Now().Add(time.Minute)
+	tests := []struct {
+		token string
+		code  codes.Code
+	}{
+		{nodeapi.SignNodeToken([]byte("testkey"), nodeapi.AdminNodeID, exp), codes.OK},
+		{nodeapi.SignNodeToken([]byte("testkey"), 12, exp), codes.PermissionDenied},
+		{nodeapi.SignNodeToken([]byte("otherkey"), nodeapi.AdminNodeID, exp), codes.Unauthenticated},
+		{nodeapi.SignNodeToken([]byte("testkey"), nodeapi.AdminNodeID, time.Now().Add(-time.Minute)), codes.Unauthenticated},
+		{"", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(nodeapi.TokenMetadataKey, tt.token))
+		}
+		resp, err := adminUnaryInterceptor(ctx, nil, info, handler)
+		if status.Code(err) != tt.code {
+			t.Errorf("token %q: %v, want %v", tt.token, err, tt.code)
+		}
+		if err == nil && resp != "ok" {
+			t.Errorf("handler is not called")
+		}
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:10080": true,
+		"localhost:10080": true,
+		"[::1]:10080":     true,
+		"0.0.0.0:10080":   false,
+		":10080":          false,
+		"10.0.0.1:10080":  false,
+		"example.com:80":  false,
+		"127.0.0.1":       false,
+	} {
+		if got := isLoopbackAddr(addr); got != want {
+			t.Errorf("isLoopbackAddr(%s) = %v", addr, got)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authentication of Synerex API calls with node tokens signed by node server.
+// Client ids (snowflake) in requests should belong to the node of the token,
+// and gateway RPCs are allowed only for nodes registered as gateway.
+
+// authNodeID verifies node token in metadata and returns node id
+func authNodeID(ctx context.Context) (int32, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(nodeapi.TokenMetadataKey)) == 0 {
+		return 0, status.Error(codes.Unauthenticated, "no node token")
+	}
+	nodeID, err := nodeapi.VerifyNodeToken([]byte(*authKey), md.Get(nodeapi.TokenMetadataKey)[0], time.Now())
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nodeID, nil
+}
+
+type authNodeKey struct{}
+
+// authenticatedNode returns node id of the verified token in ctx (false without auth)
+func authenticatedNode(ctx context.Context) (int32, bool) {
+	nodeID, ok := ctx.Value(authNodeKey{}).(int32)
+	return nodeID, ok
+}
+
+// node types from nodeserv are cached for gateway RPCs
+const nodeTypeCacheTime = time.Minute
+
+type cachedNodeType struct {
+	ntype nodeapi.NodeType
+	ts    time.Time
+}
+
+type nodeTypeCache struct {
+	mutex sync.RWMutex
+	types map[int32]cachedNodeType
+	query func(nodeID int32) (nodeapi.NodeType, error)
+}
+
+var nodeTypes = &nodeTypeCache{
+	types: make(map[int32]cachedNodeType),
+	query: func(nodeID int32) (nodeapi.NodeType, error) {
+		return sxutil.GetNodeType(int(nodeID))
+	},
+}
+
+// get returns node type of the node (cached)
+func (nc *nodeTypeCache) get(nodeID int32) (nodeapi.NodeType, error) {
+	nc.mutex.RLock()
+	nt, ok := nc.types[nodeID]
+	nc.mutex.RUnlock()
+	if ok && time.Since(nt.ts) < nodeTypeCacheTime {
+		return nt.ntype, nil
+	}
+	ntype, err := nc.query(nodeID)
+	if err != nil {
+		return ntype, err
+	}
+	nc.mutex.Lock()
+	nc.types[nodeID] = cachedNodeType{ntype, time.Now()}
+	nc.mutex.Unlock()
+	return ntype, nil
+}
+
+// checkGatewayNode returns error if the node is not registered as gateway
+func checkGatewayNode(nodeID int32) error {
+	ntype, err := nodeTypes.get(nodeID)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "can't check node %d: %v", nodeID, err)
+	}
+	if ntype != nodeapi.NodeType_GATEWAY {
+		return status.Errorf(codes.PermissionDenied, "node %d is not a gateway (%s)", nodeID, ntype)
+	}
+	return nil
+}
+
+// snowflake node id of client id
+func idNodeID(id uint64) int32 {
+	return int32(int64(id) & nodeMask >> nodeShift)
+}
+
+// checkRequestOwner checks ids of the request belong to the node
+func checkRequestOwner(nodeID int32, req interface{}) error {
+	var id uint64
+	switch r := req.(type) {
+	case *api.Demand:
+		id = r.GetSenderId()
+	case *api.Supply:
+		id = r.GetSenderId()
+	case *api.Target:
+		id = r.GetSenderId()
+	case *api.Channel:
+		id = r.GetClientId()
+	case *api.Mbus:
+		id = r.GetClientId()
+	case *api.MbusMsg:
+		id = r.GetSenderId()
+	case *api.MbusOpt:
+		if r.GetClientId() == 0 { // public mbus without owner
+			return nil
+		}
+		id = r.GetClientId()
+	case *api.MbusMembers:
+		id = r.GetClientId()
+	case *api.GatewayInfo:
+		if err := checkGatewayNode(nodeID); err != nil {
+			return err
+		}
+		id = r.GetClientId()
+	case *api.GatewayMsg: // inner messages are from other nodes, so only gateway nodes can forward
+		if err := checkGatewayNode(nodeID); err != nil {
+			return err
+		}
+		id = r.GetGatewayId()
+	case *api.ProviderID: // node id (not snowflake)
+		if int32(r.GetClientId()) != nodeID {
+			return status.Errorf(codes.PermissionDenied, "node %d can't close channels of node %d", nodeID, r.GetClientId())
+		}
+		return nil
+	default:
+		return nil
+	}
+	if idNodeID(id) != nodeID {
+		return status.Errorf(codes.PermissionDenied, "id %d does not belong to node %d", id, nodeID)
+	}
+	return nil
+}
+
+// authServerStream checks the request of server streaming RPC
+type authServerStream struct {
+	grpc.ServerStream
+	nodeID int32
+}
+
+// Context returns context with the authenticated node
+func (as *authServerStream) Context() context.Context {
+	return context.WithValue(as.ServerStream.Context(), authNodeKey{}, as.nodeID)
+}
+
+func (as *authServerStream) RecvMsg(m interface{}) error {
+	if err := as.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkRequestOwner(as.nodeID, m)
+}
+
+func authStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		nodeID, err := authNodeID(stream.Context())
+		if err != nil {
+			unaryLog.Warnf("Stream %s auth failed %v", info.FullMethod, err)
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: stream, nodeID: nodeID})
+	}
+}
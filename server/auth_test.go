@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stubNodeTypes replaces node types from nodeserv
+func stubNodeTypes(t *testing.T, types map[int32]nodeapi.NodeType) *int {
+	queries := 0
+	orig := nodeTypes
+	nodeTypes = &nodeTypeCache{
+		types: make(map[int32]cachedNodeType),
+		query: func(nodeID int32) (nodeapi.NodeType, error) {
+			queries++
+			ntype, ok := types[nodeID]
+			if !ok {
+				return nodeapi.NodeType_PROVIDER, errors.New("unregistered NodeID")
+			}
+			return ntype, nil
+		},
+	}
+	t.Cleanup(func() { nodeTypes = orig })
+	return &queries
+}
+
+func TestCheckRequestOwner(t *testing.T) {
+	stubNodeTypes(t, map[int32]nodeapi.NodeType{12: nodeapi.NodeType_PROVIDER, 20: nodeapi.NodeType_GATEWAY})
+	gm := func(gid, sender uint64) *api.GatewayMsg {
+		return &api.GatewayMsg{GatewayId: gid, MsgType: api.MsgType_SUPPLY,
+			MsgOneof: &api.GatewayMsg_Supply{Supply: &api.Supply{SenderId: sender, ChannelType: 3}}}
+	}
+	tests := []struct {
+		name    string
+		nodeID  int32
+		req     interface{}
+		allowed bool
+	}{
+		{"own supply", 12, &api.Supply{SenderId: nodeClientID(12)}, true},
+		{"other supply", 12, &api.Supply{SenderId: nodeClientID(13)}, false},
+		{"own channel", 12, &api.Channel{ClientId: nodeClientID(12)}, true},
+		{"public mbus", 12, &api.MbusOpt{}, true},
+		{"own provider", 12, &api.ProviderID{ClientId: 12}, true},
+		{"other provider", 12, &api.ProviderID{ClientId: 13}, false},
+		{"gateway subscribe", 20, &api.GatewayInfo{ClientId: nodeClientID(20)}, true},
+		{"provider subscribe gateway", 12, &api.GatewayInfo{ClientId: nodeClientID(12)}, false},
+		{"gateway forward", 20, gm(nodeClientID(20), nodeClientID(12)), true},
+		{"provider forward", 12, gm(nodeClientID(12), nodeClientID(12)), false},
+		{"gateway forward with other id", 20, gm(nodeClientID(21), nodeClientID(12)), false},
+		{"unknown node forward", 30, gm(nodeClientID(30), 0), false},
+	}
+	for _, tt := range tests {
+		err := checkRequestOwner(tt.nodeID, tt.req)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if err != nil && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s: code %v", tt.name, status.Code(err))
+		}
+	}
+}
+
+func TestNodeTypeCache(t *testing.T) {
+	queries := stubNodeTypes(t, map[int32]nodeapi.NodeType{20: nodeapi.NodeType_GATEWAY})
+	for i := 0; i < 3; i++ {
+		if err := checkGatewayNode(20); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *queries != 1 {
+		t.Errorf("queried %d times", *queries)
+	}
+	// expired type is queried again
+	nodeTypes.types[20] = cachedNodeType{nodeapi.NodeType_GATEWAY, time.Now().Add(-2 * nodeTypeCacheTime)}
+	checkGatewayNode(20)
+	if *queries != 2 {
+		t.Errorf("queried %d times", *queries)
+	}
+	// errors are not cached
+	checkGatewayNode(30)
+	checkGatewayNode(30)
+	if *queries != 4 {
+		t.Errorf("queried %d times", *queries)
+	}
+}
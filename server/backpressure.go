@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Backpressure policies for each subscriber queue.
+//   When the queue of a subscriber is full, the policy of the channel type decides
+//   what to do with the message.
+
+type backpressurePolicy int
+
+const (
+	DropNewest     backpressurePolicy = iota // drop the new message (default)
+	DropOldest                               // evict the oldest queued message
+	BlockTimeout                             // wait for free space until timeout
+	DisconnectSlow                           // disconnect the slow subscriber
+)
+
+const defaultBlockTimeout = 100 * time.Millisecond
+
+// time for keeping dropped chunks of a message (same as sxutil.ChunkTimeout)
+const chunkDropTime = 30 * time.Second
+
+var policyNames = map[string]backpressurePolicy{
+	"drop-newest": DropNewest,
+	"drop-oldest": DropOldest,
+	"block":       BlockTimeout,
+	"disconnect":  DisconnectSlow,
+}
+
+func (bp backpressurePolicy) String() string {
+	for k, v := range policyNames {
+		if v == bp {
+			return k
+		}
+	}
+	return "unknown"
+}
+
+type bpConfig struct {
+	policy  backpressurePolicy
+	timeout time.Duration // only for BlockTimeout
+}
+
+// bpPolicies keeps backpressure config for each channel type.
+type bpPolicies struct {
+	def   bpConfig
+	chMap map[uint32]bpConfig
+}
+
+func (bps *bpPolicies) get(ctype uint32) bpConfig {
+	if bps == nil {
+		return bpConfig{policy: DropNewest}
+	}
+	if bp, ok := bps.chMap[ctype]; ok {
+		return bp
+	}
+	return bps.def
+}
+
+// parseBackpressure parses policy string like "*=drop-newest,11=drop-oldest,14=block:500ms,3=disconnect"
+func parseBackpressure(str string) (*bpPolicies, error) {
+	bps := &bpPolicies{
+		def:   bpConfig{policy: DropNewest},
+		chMap: make(map[uint32]bpConfig),
+	}
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid backpressure item %q", item)
+		}
+		pv := strings.SplitN(kv[1], ":", 2)
+		policy, ok := policyNames[pv[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown backpressure policy %q", pv[0])
+		}
+		bp := bpConfig{policy: policy}
+		if policy == BlockTimeout {
+			bp.timeout = defaultBlockTimeout
+			if len(pv) == 2 {
+				d, err := time.ParseDuration(pv[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid block timeout %q: %v", pv[1], err)
+				}
+				bp.timeout = d
+			}
+		}
+		if kv[0] == "*" {
+			bps.def = bp
+		} else {
+			ctype, err := strconv.ParseUint(kv[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid channel type %q", kv[0])
+			}
+			bps.chMap[uint32(ctype)] = bp
+		}
+	}
+	return bps, nil
+}
+
+var errSlowConsumer = status.Error(codes.ResourceExhausted, "subscriber is disconnected as a slow consumer")
+
+// subscriber keeps the queue of each SubscribeDemand/SubscribeSupply stream.
+type subscriber struct {
+	clientID sxutil.IDType
+	chType   uint32
+	ch       chan queuedMsg
+	done     chan struct{} // closed when the subscriber is finished
+	stopped  chan struct{} // closed when the stream is ended
+	once     sync.Once
+	err      error      // reason of finish (nil for graceful close)
+	filter   filterExpr // server side filter (nil for all messages)
+	cmu      sync.Mutex
+	dropped  map[uint64]time.Time // messages whose chunks are dropped for the subscriber
+}
+
+func newSubscriber(id sxutil.IDType, ctype uint32, size int) *subscriber {
+	return &subscriber{
+		clientID: id,
+		chType:   ctype,
+		ch:       make(chan queuedMsg, size),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// subscriberBufferSize decides queue size from requested size of api.Channel
+func subscriberBufferSize(req uint32) int {
+	if req == 0 {
+		return MessageChannelBufferSize
+	}
+	max := *maxBuffer
+	if max <= 0 { // invalid -maxbuffer
+		max = defaultMaxBuffer
+	}
+	if int(req) > max {
+		return max
+	}
+	return int(req)
+}
+
+// finish stops the subscriber stream with reason err.
+func (sb *subscriber) finish(err error) {
+	sb.once.Do(func() {
+		sb.err = err
+		close(sb.done)
+	})
+}
+
+// push enqueues msg (shared by subscribers) following the backpressure config.
+// returns false if msg is not delivered to the subscriber.
+func (sb *subscriber) push(em *encodedMsg, bp bpConfig) bool {
+	qm := queuedMsg{em, time.Now()}
+	select {
+	case sb.ch <- qm:
+		return true
+	case <-sb.done:
+		return false
+	default:
+	}
+	switch bp.policy {
+	case DropOldest:
+		for i := 0; i <= cap(sb.ch); i++ {
+			select {
+			case old := <-sb.ch:
+				sb.evict(old)
+			default:
+			}
+			select {
+			case sb.ch <- qm:
+				return true
+			default:
+			}
+		}
+	case BlockTimeout:
+		tm := time.NewTimer(bp.timeout)
+		defer tm.Stop()
+		select {
+		case sb.ch <- qm:
+			return true
+		case <-sb.done:
+		case <-tm.C:
+		}
+	case DisconnectSlow:
+		sb.log().Errorf("Disconnect slow subscriber (queue %d)", len(sb.ch))
+		sb.finish(errSlowConsumer)
+	}
+	return false
+}
+
+// queuedInfo returns id and chunk info of queued demand or supply
+func queuedInfo(msg proto.Message) (uint64, *api.Chunk) {
+	switch m := msg.(type) {
+	case *api.Supply:
+		return m.GetId(), m.GetCdata().GetChunk()
+	case *api.Demand:
+		return m.GetId(), m.GetCdata().GetChunk()
+	}
+	return 0, nil
+}
+
+// evict logs the evicted message. If it is a chunk, the rest chunks of the message are dropped
+// (the receiver discards the incomplete message).
+func (sb *subscriber) evict(qm queuedMsg) {
+	id, ck := queuedInfo(qm.em.msg)
+	if ck == nil {
+		sb.log().Warnf("Evict oldest message %d of channel %d", id, sb.chType)
+		return
+	}
+	sb.cmu.Lock()
+	sb.dropChunks(ck.GetMsgId(), ck.GetIndex()+1 >= ck.GetTotal())
+	sb.cmu.Unlock()
+	sb.log().Warnf("Evict oldest chunk %d of message %d of channel %d", ck.GetIndex(), ck.GetMsgId(), sb.chType)
+}
+
+// pushMsg enqueues a chunk (ck != nil) with pushChunk, or other message with push
+func (sb *subscriber) pushMsg(em *encodedMsg, bp bpConfig, ck *api.Chunk) bool {
+	if ck != nil {
+		return sb.pushChunk(em, ck)
+	}
+	return sb.push(em, bp)
+}
+
+// pushChunk enqueues a chunk of large content all-or-nothing, regardless of the backpressure policy.
+// The first chunk is queued only if the queue has space for all chunks (or is empty for
+// chunks more than the queue), and the rest of a message is dropped after a dropped chunk,
+// so that the receiver discards incomplete message. Chunks never wait, not to stall fan-out.
+func (sb *subscriber) pushChunk(em *encodedMsg, ck *api.Chunk) bool {
+	mid := ck.GetMsgId()
+	last := ck.GetIndex()+1 >= ck.GetTotal()
+	sb.cmu.Lock()
+	defer sb.cmu.Unlock()
+	if ck.GetIndex() == 0 {
+		need := int(ck.GetTotal())
+		if need > cap(sb.ch) {
+			need = cap(sb.ch)
+		}
+		if cap(sb.ch)-len(sb.ch) < need {
+			sb.dropChunks(mid, last)
+			return false
+		}
+	} else if _, ok := sb.dropped[mid]; ok {
+		if last {
+			delete(sb.dropped, mid)
+		}
+		return false
+	}
+	select {
+	case sb.ch <- queuedMsg{em, time.Now()}:
+		return true
+	case <-sb.done:
+	default:
+	}
+	sb.dropChunks(mid, last)
+	return false
+}
+
+// dropChunks records the message to drop the rest chunks (need to lock cmu)
+func (sb *subscriber) dropChunks(mid uint64, last bool) {
+	now := time.Now()
+	for id, ts := range sb.dropped { // sender may stop sending chunks
+		if now.Sub(ts) > chunkDropTime {
+			delete(sb.dropped, id)
+		}
+	}
+	if last {
+		return
+	}
+	if sb.dropped == nil {
+		sb.dropped = make(map[uint64]time.Time)
+	}
+	sb.dropped[mid] = now
+}
+
+// addSubscriber registers sb, maps for the channel type are created lazily.
+func addSubscriber(chans map[uint32][]*subscriber, smap map[uint32]map[sxutil.IDType]*subscriber, sb *subscriber) {
+	m, ok := smap[sb.chType]
+	if !ok {
+		m = make(map[sxutil.IDType]*subscriber)
+		smap[sb.chType] = m
+	}
+	m[sb.clientID] = sb
+	chs := chans[sb.chType]
+	chans[sb.chType] = append(chs[:len(chs):len(chs)], sb) // copy on write for fan-out without lock
+}
+
+// removeSubscriber removes sb and reclaims maps for the channel type if empty.
+// returns false if sb is already removed.
+func removeSubscriber(chans map[uint32][]*subscriber, smap map[uint32]map[sxutil.IDType]*subscriber, sb *subscriber) bool {
+	m := smap[sb.chType]
+	if m[sb.clientID] != sb {
+		return false
+	}
+	delete(m, sb.clientID)
+	if len(m) == 0 {
+		delete(smap, sb.chType)
+		delete(chans, sb.chType)
+	} else {
+		chans[sb.chType] = removeSubscriberFromSlice(chans[sb.chType], sb)
+	}
+	return true
+}
+
+// remove subscriber from slice (returns new slice, since fan-out may read the old one without lock)
+func removeSubscriberFromSlice(sl []*subscriber, sb *subscriber) []*subscriber {
+	for i, s := range sl {
+		if s == sb {
+			return append(append(make([]*subscriber, 0, len(sl)-1), sl[:i]...), sl[i+1:]...)
+		}
+	}
+	subLog.Warnf("Cant find subscriber %d in removeSubscriber", sb.clientID)
+	return sl
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	api "github.com/synerex/synerex_api"
+)
+
+func testChunkMsg(mid uint64, index, total uint32) (*encodedMsg, *api.Chunk) {
+	ck := &api.Chunk{MsgId: mid, Index: index, Total: total}
+	return newEncodedMsg(&api.Supply{Id: uint64(index + 1), Cdata: &api.Content{Chunk: ck}}), ck
+}
+
+// pushChunks pushes all chunks of the message and returns pushed indices
+func pushChunks(sb *subscriber, mid uint64, total uint32) []uint32 {
+	var pushed []uint32
+	for i := uint32(0); i < total; i++ {
+		if sb.pushChunk(testChunkMsg(mid, i, total)) {
+			pushed = append(pushed, i)
+		}
+	}
+	return pushed
+}
+
+func TestPushChunkAllOrNothing(t *testing.T) {
+	sb := newSubscriber(1, 3, 4)
+	if pushed := pushChunks(sb, 1, 3); len(pushed) != 3 {
+		t.Fatalf("pushed %v", pushed)
+	}
+	// 1 space left for 3 chunks
+	if pushed := pushChunks(sb, 2, 3); len(pushed) != 0 {
+		t.Errorf("pushed %v without space", pushed)
+	}
+	if len(sb.dropped) != 0 {
+		t.Errorf("dropped messages are kept: %v", sb.dropped)
+	}
+	for len(sb.ch) > 0 {
+		<-sb.ch
+	}
+	// queue becomes full in the middle of a message
+	sb.pushChunk(testChunkMsg(3, 0, 3))
+	sb.push(newEncodedMsg(&api.Supply{Id: 10}), bpConfig{policy: DropNewest})
+	sb.push(newEncodedMsg(&api.Supply{Id: 11}), bpConfig{policy: DropNewest})
+	sb.push(newEncodedMsg(&api.Supply{Id: 12}), bpConfig{policy: DropNewest})
+	if sb.pushChunk(testChunkMsg(3, 1, 3)) {
+		t.Fatal("chunk is pushed to full queue")
+	}
+	<-sb.ch // space for the last chunk, but the message is already broken
+	if sb.pushChunk(testChunkMsg(3, 2, 3)) {
+		t.Error("rest of dropped message is pushed")
+	}
+	if len(sb.dropped) != 0 {
+		t.Errorf("dropped messages are kept: %v", sb.dropped)
+	}
+}
+
+func TestPushChunkLargerThanQueue(t *testing.T) {
+	sb := newSubscriber(1, 3, 2)
+	sb.push(newEncodedMsg(&api.Supply{Id: 10}), bpConfig{policy: DropNewest})
+	if sb.pushChunk(testChunkMsg(1, 0, 5)) {
+		t.Error("first chunk is pushed to non empty queue")
+	}
+	<-sb.ch
+	var pushed int
+	for i := uint32(0); i < 5; i++ { // receiver reads the queue
+		if sb.pushChunk(testChunkMsg(2, i, 5)) {
+			pushed++
+			<-sb.ch
+		}
+	}
+	if pushed != 5 {
+		t.Errorf("pushed %d chunks", pushed)
+	}
+}
+
+func TestPushMsgPolicy(t *testing.T) {
+	sb := newSubscriber(1, 3, 1)
+	bp := bpConfig{policy: DisconnectSlow}
+	sb.pushMsg(newEncodedMsg(&api.Supply{Id: 1}), bp, nil)
+	em, ck := testChunkMsg(1, 0, 2)
+	if sb.pushMsg(em, bp, ck) {
+		t.Error("chunk is pushed to full queue")
+	}
+	select {
+	case <-sb.done:
+		t.Error("subscriber is disconnected by chunk")
+	default:
+	}
+	if sb.pushMsg(newEncodedMsg(&api.Supply{Id: 2}), bp, nil) {
+		t.Error("message is pushed to full queue")
+	}
+	select {
+	case <-sb.done:
+	default:
+		t.Error("slow subscriber is not disconnected")
+	}
+}
+
+func TestDropOldestEvictsChunk(t *testing.T) {
+	sb := newSubscriber(1, 3, 3)
+	dropOldest := bpConfig{policy: DropOldest}
+	sb.pushChunk(testChunkMsg(1, 0, 3))
+	sb.pushChunk(testChunkMsg(1, 1, 3))
+	sb.push(newEncodedMsg(&api.Supply{Id: 10}), dropOldest)
+	if !sb.push(newEncodedMsg(&api.Supply{Id: 11}), dropOldest) { // evicts chunk 0
+		t.Fatal("message is not pushed with drop-oldest")
+	}
+	if _, ok := sb.dropped[1]; !ok {
+		t.Fatal("message of evicted chunk is not dropped")
+	}
+	<-sb.ch
+	if sb.pushChunk(testChunkMsg(1, 2, 3)) {
+		t.Error("rest of evicted message is pushed")
+	}
+	if len(sb.dropped) != 0 {
+		t.Errorf("dropped messages are kept: %v", sb.dropped)
+	}
+	if id, ck := queuedInfo((<-sb.ch).em.msg); id != 10 || ck != nil {
+		t.Errorf("queued message %d %v", id, ck)
+	}
+}
+
+func TestMaxBuffer(t *testing.T) {
+	defer func(n int) { *maxBuffer = n }(*maxBuffer)
+	for env, want := range map[string]int{"": defaultMaxBuffer, "500": 500, "abc": defaultMaxBuffer, "0": defaultMaxBuffer, "-1": defaultMaxBuffer} {
+		os.Setenv("SX_SERVER_MAX_BUFFER", env)
+		if got := getMaxBuffer(); got != want {
+			t.Errorf("SX_SERVER_MAX_BUFFER=%q: %d, want %d", env, got, want)
+		}
+	}
+	os.Unsetenv("SX_SERVER_MAX_BUFFER")
+
+	*maxBuffer = 500
+	if n := subscriberBufferSize(1000); n != 500 {
+		t.Errorf("requested buffer is not capped: %d", n)
+	}
+	if n := subscriberBufferSize(0); n != MessageChannelBufferSize {
+		t.Errorf("default buffer: %d", n)
+	}
+	*maxBuffer = 0
+	if n := subscriberBufferSize(1000); n != 1000 {
+		t.Errorf("buffer with invalid -maxbuffer: %d", n)
+	}
+}
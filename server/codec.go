@@ -0,0 +1,58 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Encode-once fan-out.
+// A published message is shared by queues of all subscribers as encodedMsg, and it is
+// serialized only once on the first delivery. The codec is set only on the synerex server
+// (grpc.CustomCodec), so that the serialized bytes are sent as is to every stream.
+// Other messages are marshaled as "proto" codec, and other gRPC servers and clients are not affected.
+
+// encodedMsg is a message shared by subscriber queues
+type encodedMsg struct {
+	msg  proto.Message
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func newEncodedMsg(msg proto.Message) *encodedMsg {
+	return &encodedMsg{msg: msg}
+}
+
+// bytes returns serialized message (marshaled only on the first call)
+func (em *encodedMsg) bytes() ([]byte, error) {
+	em.once.Do(func() {
+		em.data, em.err = proto.Marshal(em.msg)
+	})
+	return em.data, em.err
+}
+
+const fanoutCodecName = "sx-fanout"
+
+// fanoutCodec is proto codec which sends encodedMsg without marshaling again
+type fanoutCodec struct{}
+
+func (fanoutCodec) Marshal(v interface{}) ([]byte, error) {
+	if em, ok := v.(*encodedMsg); ok {
+		return em.bytes()
+	}
+	return proto.Marshal(v.(proto.Message))
+}
+
+func (fanoutCodec) Unmarshal(data []byte, v interface{}) error {
+	return proto.Unmarshal(data, v.(proto.Message))
+}
+
+func (fanoutCodec) Name() string {
+	return fanoutCodecName
+}
+
+// String is for grpc.Codec of grpc.CustomCodec
+func (fanoutCodec) String() string {
+	return fanoutCodecName
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestFanoutCodec(t *testing.T) {
+	sp := &api.Supply{Id: 1, SenderId: 2, ChannelType: 14, SupplyName: "sp", Cdata: &api.Content{Entity: []byte("data")}}
+	codec := fanoutCodec{}
+	for _, v := range []interface{}{newEncodedMsg(sp), sp} { // pre-encoded and ordinary message
+		data, err := codec.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &api.Supply{}
+		if err := codec.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, sp) {
+			t.Errorf("%T: decoded %v", v, got)
+		}
+	}
+	em := newEncodedMsg(sp)
+	d1, _ := codec.Marshal(em)
+	d2, _ := codec.Marshal(em)
+	if &d1[0] != &d2[0] {
+		t.Error("encoded message is marshaled again")
+	}
+	if c := encoding.GetCodec("proto"); c == nil || c.Name() != "proto" {
+		t.Errorf("default proto codec is replaced: %v", c)
+	}
+}
+
+// TestFanoutCodecServer checks messages received from synerex server with fanout codec
+func TestFanoutCodecServer(t *testing.T) {
+	s := newServerInfo()
+	lis := bufconn.Listen(1 << 20)
+	gs := prepareGrpcServer(s)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := api.NewSynerexClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.SubscribeSupply(ctx, &api.Channel{ClientId: nodeClientID(1), ChannelType: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for { // wait for subscription
+		s.smu.RLock()
+		n := len(s.supplyChans[14])
+		s.smu.RUnlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	sp := &api.Supply{Id: 10, SenderId: nodeClientID(2), ChannelType: 14, SupplyName: "sp", ArgJson: "{}", Cdata: &api.Content{Entity: []byte("data")}}
+	resp, err := client.NotifySupply(ctx, sp) // ordinary response
+	if err != nil || !resp.GetOk() {
+		t.Fatalf("NotifySupply %v, %v", resp, err)
+	}
+	got, err := stream.Recv() // pre-encoded message
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Seq, got.TraceParent = 0, "" // set by server
+	if !proto.Equal(got, sp) {
+		t.Errorf("received %v, want %v", got, sp)
+	}
+}
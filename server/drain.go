@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Graceful drain of synerex server.
+//  1. stop accepting publishes and new subscriptions
+//  2. tell nodeserv, so that providers receive SERVER_CHANGE to other servers
+//  3. wait providers to leave, then flush queues of remaining subscribers and close streams with EOF
+
+var errDraining = status.Error(codes.Unavailable, "server is draining")
+
+// methods rejected while draining
+var drainRejectMethods = map[string]bool{
+	"NotifyDemand": true, "NotifySupply": true, "ProposeDemand": true, "ProposeSupply": true,
+	"CreateMbus": true, "SendMbusMsg": true,
+}
+
+func (s *synerexServerInfo) isDraining() bool {
+	return atomic.LoadInt32(&s.draining) == 1
+}
+
+func (s *synerexServerInfo) subscriberCount() int {
+	n := 0
+	s.dmu.RLock()
+	for _, chs := range s.demandChans {
+		n += len(chs)
+	}
+	s.dmu.RUnlock()
+	s.smu.RLock()
+	for _, chs := range s.supplyChans {
+		n += len(chs)
+	}
+	s.smu.RUnlock()
+	return n
+}
+
+// finishSubscribers closes all subscribers gracefully (queued messages are flushed)
+func finishSubscribers(chans map[uint32][]*subscriber, sbs []*subscriber) []*subscriber {
+	for _, chs := range chans {
+		for _, sb := range chs {
+			sb.finish(nil)
+			sbs = append(sbs, sb)
+		}
+	}
+	return sbs
+}
+
+// drain stops publishes and closes subscribers within timeout
+func (s *synerexServerInfo) drain(timeout time.Duration) error {
+	if !atomic.CompareAndSwapInt32(&s.draining, 0, 1) {
+		return status.Error(codes.FailedPrecondition, "server is already draining")
+	}
+	deadline := time.Now().Add(timeout)
+	logger.Infof("Start draining (timeout %v)", timeout)
+
+	// tell nodeserv to move providers
+	sxutil.SetNodeStatus(nodeapi.NodeStatusDraining, "draining")
+	if err := sxutil.SendKeepAlive(); err != nil {
+		logger.Warnf("Can't notify draining to nodeserv: %v", err)
+	}
+
+	// wait providers to move for half of timeout
+	moveDeadline := time.Now().Add(timeout / 2)
+	for time.Now().Before(moveDeadline) && s.subscriberCount() > 0 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// write locks wait for publishes in progress
+	var sbs []*subscriber
+	s.dmu.Lock()
+	sbs = finishSubscribers(s.demandChans, sbs)
+	s.dmu.Unlock()
+	s.smu.Lock()
+	sbs = finishSubscribers(s.supplyChans, sbs)
+	s.smu.Unlock()
+
+	s.mmu.Lock()
+	mbusIDs := make([]uint64, 0, len(s.mbusChans))
+	for id := range s.mbusChans {
+		mbusIDs = append(mbusIDs, id)
+	}
+	for _, id := range mbusIDs {
+		s.closeMbusChans(id)
+	}
+	s.mmu.Unlock()
+
+	// wait flushing
+	remain := 0
+	for _, sb := range sbs {
+		select {
+		case <-sb.stopped:
+		case <-time.After(time.Until(deadline)):
+			remain++
+		}
+	}
+	logger.Infof("Drain finished: %d subscribers closed (%d not flushed), %d mbus closed", len(sbs), remain, len(mbusIDs))
+	return nil
+}
+
+// shutdown calls defer functions (unregister node, close message store) and exit
+func shutdown(code int) {
+	sxutil.CallDeferFunctions()
+	logger.Infof("Shutdown synerex server")
+	os.Exit(code)
+}
+
+// handleDrainSignal drains the server with SIGINT/SIGTERM. Second signal exits immediately.
+func handleDrainSignal(timeout time.Duration) {
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+	if sinfo == nil || timeout <= 0 {
+		logger.Warnf("Signal Interrupt!")
+		shutdown(1)
+	}
+	logger.Warnf("Signal Interrupt! Draining server (send again for immediate exit)")
+	go func() {
+		<-c
+		logger.Warnf("Signal Interrupt! Exit without draining")
+		shutdown(1)
+	}()
+	if err := sinfo.drain(timeout); err != nil {
+		logger.Errorf("Drain error: %v", err)
+	}
+	shutdown(0)
+}
+
+func (as *adminServer) Drain(ctx context.Context, dr *api.DrainRequest) (*api.Response, error) {
+	timeout := *drainTime
+	if dr.GetTimeout() != nil {
+		d, err := ptypes.Duration(dr.GetTimeout())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		timeout = d
+	}
+	if err := as.s.drain(timeout); err != nil {
+		return &api.Response{Ok: false, Err: err.Error()}, nil
+	}
+	if dr.GetShutdown() {
+		go func() {
+			time.Sleep(100 * time.Millisecond) // for sending response
+			shutdown(0)
+		}()
+	}
+	return &api.Response{Ok: true}, nil
+}
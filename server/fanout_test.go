@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+)
+
+// Benchmarks of channel fan-out with large Cdata (like GeoJSON of geography provider).
+//   go test -bench Fanout -benchmem
+
+const benchSubscribers = 16
+
+func benchSupply() *api.Supply {
+	geo := `{"type":"FeatureCollection","features":[` +
+		strings.Repeat(`{"type":"Feature","geometry":{"type":"Point","coordinates":[136.9066,35.1815]},"properties":{"name":"node"}},`, 2000) +
+		`{}]}`
+	return &api.Supply{
+		SenderId:    1,
+		ChannelType: 14,
+		SupplyName:  "geography",
+		Cdata:       &api.Content{Entity: []byte(geo)},
+	}
+}
+
+func benchServer(n int) (*synerexServerInfo, []*subscriber) {
+	s := newServerInfo()
+	sbs := make([]*subscriber, n)
+	for i := range sbs {
+		sbs[i] = newSubscriber(sxutil.IDType(i+1), 14, MessageChannelBufferSize)
+		addSubscriber(s.supplyChans, s.supplyMap, sbs[i])
+	}
+	return s, sbs
+}
+
+// send supply and serialize it for every subscriber (as stream.SendMsg)
+func benchFanout(b *testing.B, marshal func(qm queuedMsg) ([]byte, error)) {
+	s, sbs := benchServer(benchSubscribers)
+	sp := benchSupply()
+	b.SetBytes(int64(proto.Size(sp)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.Id = uint64(i)
+		sendSupply(s, sp, false)
+		for _, sb := range sbs {
+			if _, err := marshal(<-sb.ch); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkFanoutPerSubscriber(b *testing.B) {
+	benchFanout(b, func(qm queuedMsg) ([]byte, error) {
+		return proto.Marshal(qm.em.msg) // previous behavior: marshal for each stream
+	})
+}
+
+func BenchmarkFanoutEncodeOnce(b *testing.B) {
+	benchFanout(b, func(qm queuedMsg) ([]byte, error) {
+		return fanoutCodec{}.Marshal(qm.em)
+	})
+}
+
+// publishes in parallel while subscribers join and leave
+func BenchmarkFanoutParallelSubscribe(b *testing.B) {
+	s, sbs := benchServer(benchSubscribers)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, sb := range sbs {
+		wg.Add(1)
+		go func(sb *subscriber) {
+			defer wg.Done()
+			for {
+				select {
+				case qm := <-sb.ch:
+					fanoutCodec{}.Marshal(qm.em)
+				case <-stop:
+					return
+				}
+			}
+		}(sb)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			sb := newSubscriber(sxutil.IDType(1000+i), 14, 1)
+			s.smu.Lock()
+			addSubscriber(s.supplyChans, s.supplyMap, sb)
+			s.smu.Unlock()
+			s.smu.Lock()
+			removeSubscriber(s.supplyChans, s.supplyMap, sb)
+			s.smu.Unlock()
+		}
+	}()
+	sp := benchSupply()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sendSupply(s, proto.Clone(sp).(*api.Supply), false)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	wg.Wait()
+}
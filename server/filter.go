@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+)
+
+// Server side subscription filter.
+//   Channel.arg_json can carry a filter expression like
+//     {"filter": "arg.lat >= 34.5 && arg.lat < 35.5 && (name ^= \"taxi\" || sender_id == 12345)"}
+//   fields : name, supply_name (supply only), demand_name (demand only), sender_id, arg.<path> (field of arg_json)
+//   ops    : == != < <= > >= ^= (prefix)
+//   values : number, "string", true, false
+//   terms can be combined with && || and ( ).
+
+type filterExpr interface {
+	match(ft *filterTarget) bool
+}
+
+type filterAnd struct{ l, r filterExpr }
+type filterOr struct{ l, r filterExpr }
+
+func (f *filterAnd) match(ft *filterTarget) bool { return f.l.match(ft) && f.r.match(ft) }
+func (f *filterOr) match(ft *filterTarget) bool  { return f.l.match(ft) || f.r.match(ft) }
+
+// literal value in filter expression
+type filterValue struct {
+	text  string // string value or number text
+	isNum bool
+	isStr bool
+	bool  bool // for true/false
+}
+
+type filterCond struct {
+	field string
+	op    string
+	value filterValue
+}
+
+// filterTarget is a message to be checked. arg_json is parsed once for all subscribers.
+type filterTarget struct {
+	name     string
+	isSupply bool
+	isDemand bool
+	senderID uint64
+	argJSON  string
+	args     interface{}
+	parsed   bool
+}
+
+func newFilterTarget(msg interface{}) *filterTarget {
+	switch m := msg.(type) {
+	case *api.Demand:
+		return &filterTarget{name: m.GetDemandName(), isDemand: true, senderID: m.GetSenderId(), argJSON: m.GetArgJson()}
+	case *api.Supply:
+		return &filterTarget{name: m.GetSupplyName(), isSupply: true, senderID: m.GetSenderId(), argJSON: m.GetArgJson()}
+	}
+	return &filterTarget{}
+}
+
+// arg returns the field of arg_json with dotted path.
+func (ft *filterTarget) arg(path string) (interface{}, bool) {
+	if !ft.parsed {
+		ft.parsed = true
+		dec := json.NewDecoder(strings.NewReader(ft.argJSON))
+		dec.UseNumber() // keep precision of ids
+		if dec.Decode(&ft.args) != nil {
+			ft.args = nil
+		}
+	}
+	v := ft.args
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func (fc *filterCond) match(ft *filterTarget) bool {
+	switch {
+	case fc.field == "name":
+		return fc.compareString(ft.name)
+	case fc.field == "supply_name":
+		return ft.isSupply && fc.compareString(ft.name)
+	case fc.field == "demand_name":
+		return ft.isDemand && fc.compareString(ft.name)
+	case fc.field == "sender_id":
+		return fc.compareNumber(strconv.FormatUint(ft.senderID, 10))
+	case strings.HasPrefix(fc.field, "arg."):
+		v, ok := ft.arg(fc.field[4:])
+		if !ok {
+			return false
+		}
+		switch av := v.(type) {
+		case string:
+			return fc.compareString(av)
+		case json.Number:
+			return fc.compareNumber(av.String())
+		case bool:
+			if fc.value.isNum || fc.value.isStr {
+				return false
+			}
+			return compareResult(fc.op, boolCompare(av, fc.value.bool), fc.op == "==" || fc.op == "!=")
+		}
+	}
+	return false
+}
+
+func boolCompare(a, b bool) int {
+	if a == b {
+		return 0
+	}
+	return 1
+}
+
+func (fc *filterCond) compareString(s string) bool {
+	if !fc.value.isStr {
+		return false
+	}
+	if fc.op == "^=" {
+		return strings.HasPrefix(s, fc.value.text)
+	}
+	return compareResult(fc.op, strings.Compare(s, fc.value.text), true)
+}
+
+// compareNumber compares numbers as integer if possible (for snowflake ids), otherwise as float.
+func (fc *filterCond) compareNumber(num string) bool {
+	if !fc.value.isNum || fc.op == "^=" {
+		return false
+	}
+	if a, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if b, err := strconv.ParseUint(fc.value.text, 10, 64); err == nil {
+			c := 0
+			if a < b {
+				c = -1
+			} else if a > b {
+				c = 1
+			}
+			return compareResult(fc.op, c, true)
+		}
+	}
+	a, err1 := strconv.ParseFloat(num, 64)
+	b, err2 := strconv.ParseFloat(fc.value.text, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	c := 0
+	if a < b {
+		c = -1
+	} else if a > b {
+		c = 1
+	}
+	return compareResult(fc.op, c, true)
+}
+
+// compareResult checks comparison result c (-1, 0, 1) with op.
+func compareResult(op string, c int, ordered bool) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	}
+	if !ordered {
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// accepts checks msg with the filter of subscriber.
+// ft is created lazily and shared among subscribers of the message.
+func (sb *subscriber) accepts(msg proto.Message, ft **filterTarget) bool {
+	if sb.filter == nil {
+		return true
+	}
+	if *ft == nil {
+		*ft = newFilterTarget(msg)
+	}
+	return sb.filter.match(*ft)
+}
+
+// parseChannelFilter obtains filter from arg_json of Channel.
+// nil is returned if arg_json has no filter (arg_json may not be json).
+func parseChannelFilter(argJSON string) (filterExpr, error) {
+	var arg struct {
+		Filter string `json:"filter"`
+	}
+	if json.Unmarshal([]byte(argJSON), &arg) != nil || strings.TrimSpace(arg.Filter) == "" {
+		return nil, nil
+	}
+	return parseFilter(arg.Filter)
+}
+
+// limits of client supplied filters (for the stack of recursive parse and match)
+const (
+	maxFilterLen   = 4096
+	maxFilterDepth = 32 // nesting of parentheses
+)
+
+// parseFilter parses filter expression.
+func parseFilter(str string) (filterExpr, error) {
+	if len(str) > maxFilterLen {
+		return nil, fmt.Errorf("filter is longer than %d", maxFilterLen)
+	}
+	toks, err := tokenizeFilter(str)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	fe, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.toks[p.pos].text)
+	}
+	return fe, nil
+}
+
+type filterToken struct {
+	kind byte // 'i': ident, 'n': number, 's': string, 'o': operator
+	text string
+}
+
+var filterCompareOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "^=": true}
+
+var filterOps = []string{"&&", "||", "==", "!=", "<=", ">=", "^=", "<", ">", "(", ")"}
+
+func tokenizeFilter(str string) ([]filterToken, error) {
+	var toks []filterToken
+	rs := []rune(str)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			toks = append(toks, filterToken{'s', sb.String()})
+			i = j + 1
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || strings.ContainsRune(".eE+-", rs[j])) {
+				j++
+			}
+			toks = append(toks, filterToken{'n', string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, filterToken{'i', string(rs[i:j])})
+			i = j
+		default:
+			found := false
+			for _, op := range filterOps {
+				if strings.HasPrefix(string(rs[i:]), op) {
+					toks = append(toks, filterToken{'o', op})
+					i += len([]rune(op))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid character %q in filter", r)
+			}
+		}
+	}
+	return toks, nil
+}
+
+type filterParser struct {
+	toks  []filterToken
+	pos   int
+	depth int // nesting of parentheses
+}
+
+func (p *filterParser) peek(op string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].kind == 'o' && p.toks[p.pos].text == op
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	l, err := p.parseAnd()
+	for err == nil && p.peek("||") {
+		p.pos++
+		var r filterExpr
+		if r, err = p.parseAnd(); err == nil {
+			l = &filterOr{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	l, err := p.parseTerm()
+	for err == nil && p.peek("&&") {
+		p.pos++
+		var r filterExpr
+		if r, err = p.parseTerm(); err == nil {
+			l = &filterAnd{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *filterParser) parseTerm() (filterExpr, error) {
+	if p.peek("(") {
+		if p.depth >= maxFilterDepth {
+			return nil, fmt.Errorf("filter is nested deeper than %d", maxFilterDepth)
+		}
+		p.pos++
+		p.depth++
+		fe, err := p.parseOr()
+		p.depth--
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing ) in filter")
+		}
+		p.pos++
+		return fe, nil
+	}
+	if p.pos+3 > len(p.toks) {
+		return nil, fmt.Errorf("incomplete filter condition")
+	}
+	fld, op, val := p.toks[p.pos], p.toks[p.pos+1], p.toks[p.pos+2]
+	if fld.kind != 'i' {
+		return nil, fmt.Errorf("field is expected, but %q in filter", fld.text)
+	}
+	if fld.text != "name" && fld.text != "supply_name" && fld.text != "demand_name" && fld.text != "sender_id" && !strings.HasPrefix(fld.text, "arg.") {
+		return nil, fmt.Errorf("unknown field %q in filter", fld.text)
+	}
+	if op.kind != 'o' || !filterCompareOps[op.text] {
+		return nil, fmt.Errorf("operator is expected, but %q in filter", op.text)
+	}
+	fc := &filterCond{field: fld.text, op: op.text}
+	switch {
+	case val.kind == 'n':
+		if _, err := strconv.ParseFloat(val.text, 64); err != nil {
+			return nil, fmt.Errorf("invalid number %q in filter", val.text)
+		}
+		fc.value = filterValue{text: val.text, isNum: true}
+	case val.kind == 's':
+		fc.value = filterValue{text: val.text, isStr: true}
+	case val.kind == 'i' && (val.text == "true" || val.text == "false"):
+		fc.value = filterValue{text: val.text, bool: val.text == "true"}
+	default:
+		return nil, fmt.Errorf("value is expected, but %q in filter", val.text)
+	}
+	if op.text == "^=" && !fc.value.isStr {
+		return nil, fmt.Errorf("prefix match requires string value in filter")
+	}
+	p.pos += 3
+	return fc, nil
+}
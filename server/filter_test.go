@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/synerex/synerex_api"
+)
+
+func TestFilterMatch(t *testing.T) {
+	sp := &api.Supply{SupplyName: "taxi-01", SenderId: 1234567890123456789,
+		ArgJson: `{"lat": 34.9, "id": 1234567890123456789, "ok": true, "kind": "car", "pos": {"x": -1.5}, "q": "a\"b'c"}`}
+	dm := &api.Demand{DemandName: "taxi-req", SenderId: 12, ArgJson: `not json`}
+	tests := []struct {
+		filter string
+		msg    interface{}
+		want   bool
+	}{
+		// each field
+		{`name == "taxi-01"`, sp, true},
+		{`name ^= "taxi"`, sp, true},
+		{`name ^= "bus"`, sp, false},
+		{`name != "taxi-01"`, sp, false},
+		{`name < "u"`, sp, true},
+		{`supply_name == "taxi-01"`, sp, true},
+		{`demand_name == "taxi-01"`, sp, false},
+		{`demand_name ^= "taxi"`, dm, true},
+		{`supply_name ^= "taxi"`, dm, false},
+		{`name ^= "taxi"`, dm, true},
+		{`sender_id == 1234567890123456789`, sp, true},
+		{`sender_id == 1234567890123456788`, sp, false},
+		{`sender_id > 11`, dm, true},
+		{`sender_id <= 11`, dm, false},
+		{`sender_id == "12"`, dm, false},
+		{`arg.lat >= 34.5`, sp, true},
+		{`arg.lat < 34.5`, sp, false},
+		{`arg.id == 1234567890123456789`, sp, true},
+		{`arg.id != 1234567890123456788`, sp, true},
+		{`arg.ok == true`, sp, true},
+		{`arg.ok != false`, sp, true},
+		{`arg.ok > false`, sp, false},
+		{`arg.ok == 1`, sp, false},
+		{`arg.kind == "car"`, sp, true},
+		{`arg.kind == 1`, sp, false},
+		{`arg.pos.x > -2`, sp, true},
+		{`arg.pos.x <= -1.5`, sp, true},
+		{`arg.pos.y == 1`, sp, false},
+		{`arg.lat.x == 1`, sp, false},
+		{`arg.missing != 1`, sp, false},
+		{`arg.lat > 1`, dm, false},
+		// quoting and escapes
+		{`arg.q == "a\"b'c"`, sp, true},
+		{`arg.q == 'a"b\'c'`, sp, true},
+		{`arg.kind == 'car'`, sp, true},
+		// precedence
+		{`name == "x" || arg.ok == true && arg.lat > 35`, sp, false},
+		{`name == "x" && arg.ok == true || arg.lat < 35`, sp, true},
+		{`(name == "x" || arg.ok == true) && arg.lat < 35`, sp, true},
+		{`name ^= "taxi" || (arg.ok == false && sender_id == 1)`, sp, true},
+		{`((arg.lat >= 34.5) && (arg.lat < 35.5))`, sp, true},
+	}
+	for _, tt := range tests {
+		fe, err := parseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("parseFilter(%s): %v", tt.filter, err)
+			continue
+		}
+		if got := fe.match(newFilterTarget(tt.msg)); got != tt.want {
+			t.Errorf("%s on %T = %v, want %v", tt.filter, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestFilterParseError(t *testing.T) {
+	for _, f := range []string{
+		`name == "taxi`,
+		`name = "taxi"`,
+		`name == `,
+		`name`,
+		`foo == 1`,
+		`arg.x == 1 &&`,
+		`(arg.x == 1`,
+		`arg.x == 1)`,
+		`arg.x == 1 arg.y == 2`,
+		`arg.x == 1e`,
+		`arg.x == 1.2.3`,
+		`arg.x ^= 1`,
+		`arg.x == nil`,
+		`arg.x && 1`,
+		`1 == arg.x`,
+		`arg.x == 1 # comment`,
+		`|| arg.x == 1`,
+		`()`,
+	} {
+		if fe, err := parseFilter(f); err == nil {
+			t.Errorf("parseFilter(%s) = %v, want error", f, fe)
+		}
+	}
+}
+
+func TestFilterLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "arg.x == 1" + strings.Repeat(")", depth)
+	}
+	if _, err := parseFilter(nested(maxFilterDepth)); err != nil {
+		t.Errorf("filter of max depth: %v", err)
+	}
+	if _, err := parseFilter(nested(maxFilterDepth + 1)); err == nil {
+		t.Error("too deep filter is accepted")
+	}
+	if _, err := parseFilter(strings.Repeat("(", 100000)); err == nil {
+		t.Error("too long filter is accepted")
+	}
+	long := "arg.x == 1" + strings.Repeat(" || arg.x == 1", maxFilterLen/14)
+	if _, err := parseFilter(long); err == nil {
+		t.Errorf("filter of %d bytes is accepted", len(long))
+	}
+}
+
+func TestParseChannelFilter(t *testing.T) {
+	for _, arg := range []string{"", "abc", `{"filter": ""}`, `{"filter": "  "}`, `{"other": 1}`} {
+		if fe, err := parseChannelFilter(arg); fe != nil || err != nil {
+			t.Errorf("parseChannelFilter(%q) = %v, %v", arg, fe, err)
+		}
+	}
+	if _, err := parseChannelFilter(`{"filter": "arg.x =="}`); err == nil {
+		t.Error("invalid filter is accepted")
+	}
+	fe, err := parseChannelFilter(`{"filter": "arg.x == 1"}`)
+	if err != nil || fe == nil {
+		t.Fatalf("parseChannelFilter: %v, %v", fe, err)
+	}
+	sb := &subscriber{filter: fe}
+	var ft *filterTarget
+	if !sb.accepts(&api.Supply{ArgJson: `{"x": 1}`}, &ft) {
+		t.Error("matched message is not accepted")
+	}
+	ft = nil
+	if sb.accepts(&api.Supply{ArgJson: `{"x": 2}`}, &ft) {
+		t.Error("unmatched message is accepted")
+	}
+	ft = nil
+	if !(&subscriber{}).accepts(&api.Supply{}, &ft) {
+		t.Error("message is not accepted without filter")
+	}
+}
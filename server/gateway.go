@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gateway keeps the stream channel and the subscription of each gateway.
+type gateway struct {
+	id       sxutil.IDType
+	gtype    api.GatewayType
+	channels map[uint32]bool // channel types to forward (empty for all channels)
+	ch       chan *api.GatewayMsg
+}
+
+func newGateway(gi *api.GatewayInfo) *gateway {
+	gw := &gateway{
+		id:       sxutil.IDType(gi.GetClientId()),
+		gtype:    gi.GetGatewayType(),
+		channels: make(map[uint32]bool),
+		ch:       make(chan *api.GatewayMsg, MessageChannelBufferSize),
+	}
+	for _, ctype := range gi.GetChannels() {
+		gw.channels[ctype] = true
+	}
+	return gw
+}
+
+// gatewayMsgChannel returns channel type of gm (false for mbus messages).
+func gatewayMsgChannel(gm *api.GatewayMsg) (uint32, bool) {
+	switch gm.GetMsgType() {
+	case api.MsgType_DEMAND:
+		return gm.GetDemand().GetChannelType(), true
+	case api.MsgType_SUPPLY:
+		return gm.GetSupply().GetChannelType(), true
+	case api.MsgType_TARGET:
+		return gm.GetTarget().GetChannelType(), true
+	}
+	return 0, false
+}
+
+// accepts checks whether gm should be sent to the gateway.
+func (gw *gateway) accepts(gm *api.GatewayMsg) bool {
+	if gw.gtype == api.GatewayType_WRITE_ONLY { // no need to receive
+		return false
+	}
+	if ctype, ok := gatewayMsgChannel(gm); ok && len(gw.channels) > 0 {
+		return gw.channels[ctype]
+	}
+	return true
+}
+
+// Loop prevention for gateway messages.
+// Each GatewayMsg keeps visited servers and hop count, and each server keeps
+// recently seen message ids, so messages are relayed through multiple gateways only once.
+
+const seenCacheSize = 10000 // number of recently seen message ids
+
+type seenKey struct {
+	msgType api.MsgType
+	id      uint64
+}
+
+// seenCache is a ring buffer of recently seen message ids.
+type seenCache struct {
+	mutex sync.Mutex
+	seen  map[seenKey]bool
+	ring  []seenKey
+	pt    int
+}
+
+func newSeenCache(size int) *seenCache {
+	return &seenCache{
+		seen: make(map[seenKey]bool),
+		ring: make([]seenKey, size),
+	}
+}
+
+// check returns true if key is already seen, otherwise key is recorded.
+func (sc *seenCache) check(key seenKey) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	if sc.seen[key] {
+		return true
+	}
+	if old := sc.ring[sc.pt]; old.id != 0 {
+		delete(sc.seen, old)
+	}
+	sc.seen[key] = true
+	sc.ring[sc.pt] = key
+	sc.pt = (sc.pt + 1) % len(sc.ring)
+	return false
+}
+
+// gatewayMsgKey returns message id of GatewayMsg.
+// Mbus subscription and events have no id, so they are checked only with visited servers.
+func gatewayMsgKey(gm *api.GatewayMsg) (seenKey, bool) {
+	var id uint64
+	switch gm.GetMsgType() {
+	case api.MsgType_DEMAND:
+		id = gm.GetDemand().GetId()
+	case api.MsgType_SUPPLY:
+		id = gm.GetSupply().GetId()
+	case api.MsgType_TARGET:
+		id = gm.GetTarget().GetId()
+	case api.MsgType_MBUSMSG:
+		id = gm.GetMbusMsg().GetMsgId()
+	}
+	return seenKey{gm.GetMsgType(), id}, id != 0
+}
+
+// checkGatewayLoop returns reason if gm should be dropped.
+func (s *synerexServerInfo) checkGatewayLoop(gm *api.GatewayMsg) string {
+	for _, sid := range gm.GetVisited() {
+		if sid == server_id {
+			return fmt.Sprintf("GatewayMsg loop from %d", gm.GetSrcSynerexId())
+		}
+	}
+	if key, ok := gatewayMsgKey(gm); ok && s.seenMsgs.check(key) {
+		return fmt.Sprintf("GatewayMsg duplicated %s %d", key.msgType, key.id)
+	}
+	return ""
+}
+
+// relayGatewayMsg sends gm from other server to gateways (except the forwarding gateway) with this server as visited.
+func (s *synerexServerInfo) relayGatewayMsg(gm *api.GatewayMsg) {
+	hops := gm.GetHopCount() + 1
+	if int(hops) >= *maxHops {
+		return
+	}
+	s.pushGatewayMsg(&api.GatewayMsg{
+		SrcSynerexId: gm.GetSrcSynerexId(),
+		MsgType:      gm.GetMsgType(),
+		MsgOneof:     gm.GetMsgOneof(),
+		TargetType:   gm.GetTargetType(),
+		HopCount:     hops,
+		Visited:      append(append([]uint64{}, gm.GetVisited()...), server_id),
+	}, sxutil.IDType(gm.GetGatewayId()))
+}
+
+// checkGatewayWriter returns error if the caller is not a subscribed gateway which can write.
+// WRITE_ONLY gateway also subscribes (it receives nothing), and with -authkey
+// the gateway should belong to the authenticated node.
+func (s *synerexServerInfo) checkGatewayWriter(ctx context.Context, gm *api.GatewayMsg) error {
+	gid := sxutil.IDType(gm.GetGatewayId())
+	s.gmu.RLock()
+	gw, ok := s.gatewayMap[gid]
+	s.gmu.RUnlock()
+	if !ok {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("ForwardToGateway from unknown gateway %d", gid))
+	}
+	if nodeID, auth := authenticatedNode(ctx); auth && idNodeID(uint64(gw.id)) != nodeID {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("gateway %d does not belong to node %d", gw.id, nodeID))
+	}
+	if gw.gtype == api.GatewayType_READ_ONLY {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("ForwardToGateway from READ_ONLY gateway %d", gw.id))
+	}
+	return nil
+}
+
+// sendGatewayMsg sends message to all gateways.
+// Message from this server is marked as visited and seen.
+func (s *synerexServerInfo) sendGatewayMsg(gm *api.GatewayMsg) {
+	if len(gm.Visited) == 0 {
+		gm.Visited = []uint64{server_id}
+		if key, ok := gatewayMsgKey(gm); ok {
+			s.seenMsgs.check(key)
+		}
+	}
+	s.pushGatewayMsg(gm, 0)
+}
+
+// pushGatewayMsg sends gm to gateways except src without blocking.
+// Gateways are taken under lock, and gm is dropped for the gateway whose queue is full.
+func (s *synerexServerInfo) pushGatewayMsg(gm *api.GatewayMsg, src sxutil.IDType) {
+	s.gmu.RLock()
+	gws := make([]*gateway, 0, len(s.gatewayMap))
+	for _, gw := range s.gatewayMap {
+		if gw.id != src && gw.accepts(gm) {
+			gws = append(gws, gw)
+		}
+	}
+	s.gmu.RUnlock()
+	for _, gw := range gws {
+		select {
+		case gw.ch <- gm:
+			totalMessages.Inc(1)
+			sendMessages.Inc(1)
+		default:
+			gatewayLog.Warnf("Gateway %d MessageDrop %s", gw.id, gm.GetMsgType())
+		}
+	}
+}
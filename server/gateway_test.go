@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+)
+
+func addTestGateway(s *synerexServerInfo, id uint64, gtype api.GatewayType, channels ...uint32) *gateway {
+	gw := newGateway(&api.GatewayInfo{ClientId: id, GatewayType: gtype, Channels: channels})
+	s.gmu.Lock()
+	s.gatewayMap[gw.id] = gw
+	s.gmu.Unlock()
+	return gw
+}
+
+func supplyGatewayMsg(id uint64, ctype uint32) *api.GatewayMsg {
+	return &api.GatewayMsg{
+		SrcSynerexId: 99,
+		MsgType:      api.MsgType_SUPPLY,
+		MsgOneof:     &api.GatewayMsg_Supply{Supply: &api.Supply{Id: id, ChannelType: ctype}},
+		Visited:      []uint64{99},
+	}
+}
+
+func TestRelayGatewayMsgSkipsSource(t *testing.T) {
+	s := newServerInfo()
+	src := addTestGateway(s, 1, api.GatewayType_BIDIRECTIONAL)
+	other := addTestGateway(s, 2, api.GatewayType_BIDIRECTIONAL)
+	gm := supplyGatewayMsg(10, 3)
+	gm.GatewayId = 1
+	s.relayGatewayMsg(gm)
+	if len(src.ch) != 0 {
+		t.Error("message is relayed to the source gateway")
+	}
+	if len(other.ch) != 1 {
+		t.Fatal("message is not relayed to other gateway")
+	}
+	rgm := <-other.ch
+	if rgm.GetHopCount() != 1 || len(rgm.GetVisited()) != 2 || rgm.Visited[1] != server_id {
+		t.Errorf("relayed hop %d visited %v", rgm.GetHopCount(), rgm.GetVisited())
+	}
+}
+
+func TestSendGatewayMsgFull(t *testing.T) {
+	s := newServerInfo()
+	gw := addTestGateway(s, 1, api.GatewayType_BIDIRECTIONAL)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i <= cap(gw.ch); i++ { // one more than the queue
+			s.sendGatewayMsg(supplyGatewayMsg(uint64(i+1), 3))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sendGatewayMsg blocks on full gateway")
+	}
+	if len(gw.ch) != cap(gw.ch) {
+		t.Errorf("queued %d", len(gw.ch))
+	}
+	// other goroutines can take the lock
+	s.gmu.Lock()
+	delete(s.gatewayMap, sxutil.IDType(1))
+	s.gmu.Unlock()
+}
+
+func TestGatewayAccepts(t *testing.T) {
+	ro := newGateway(&api.GatewayInfo{ClientId: 1, GatewayType: api.GatewayType_READ_ONLY, Channels: []uint32{3}})
+	wo := newGateway(&api.GatewayInfo{ClientId: 2, GatewayType: api.GatewayType_WRITE_ONLY})
+	if !ro.accepts(supplyGatewayMsg(1, 3)) || ro.accepts(supplyGatewayMsg(2, 4)) {
+		t.Error("channels of gateway")
+	}
+	if !ro.accepts(mbusEventGatewayMsg(1, api.MbusEvent_LEFT, 2)) {
+		t.Error("mbus message is not sent to gateway with channels")
+	}
+	if wo.accepts(supplyGatewayMsg(3, 3)) {
+		t.Error("message is sent to WRITE_ONLY gateway")
+	}
+}
+
+func TestCheckGatewayWriter(t *testing.T) {
+	s := newServerInfo()
+	bi := addTestGateway(s, nodeClientID(20), api.GatewayType_BIDIRECTIONAL)
+	addTestGateway(s, nodeClientID(21), api.GatewayType_READ_ONLY)
+	addTestGateway(s, nodeClientID(22), api.GatewayType_WRITE_ONLY)
+	auth := func(nodeID int32) context.Context {
+		return context.WithValue(context.Background(), authNodeKey{}, nodeID)
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		gid     uint64
+		allowed bool
+	}{
+		{"bidirectional", context.Background(), nodeClientID(20), true},
+		{"write only", context.Background(), nodeClientID(22), true},
+		{"read only", context.Background(), nodeClientID(21), false},
+		{"zero id", context.Background(), 0, false},
+		{"unknown", context.Background(), nodeClientID(23), false},
+		{"authenticated", auth(20), nodeClientID(20), true},
+		{"other node", auth(5), nodeClientID(20), false},
+	}
+	for _, tt := range tests {
+		gm := supplyGatewayMsg(1, 3)
+		gm.GatewayId = tt.gid
+		if err := s.checkGatewayWriter(tt.ctx, gm); (err == nil) != tt.allowed {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+	}
+	// forwarded message is rejected before delivery
+	gm := supplyGatewayMsg(2, 3)
+	gm.GatewayId = nodeClientID(21)
+	if _, err := s.ForwardToGateway(context.Background(), gm); err == nil {
+		t.Error("ForwardToGateway from READ_ONLY gateway")
+	}
+	if len(bi.ch) != 0 {
+		t.Error("rejected message is relayed")
+	}
+}
+
+func TestSeenCache(t *testing.T) {
+	sc := newSeenCache(3)
+	key := func(id uint64) seenKey { return seenKey{api.MsgType_SUPPLY, id} }
+	for id := uint64(1); id <= 3; id++ {
+		if sc.check(key(id)) {
+			t.Errorf("new id %d is seen", id)
+		}
+	}
+	if !sc.check(key(2)) || sc.check(seenKey{api.MsgType_DEMAND, 2}) {
+		t.Error("key of message type")
+	}
+	// DEMAND 2 evicted id 1, and id 1 evicts id 2
+	if sc.check(key(1)) {
+		t.Error("evicted id 1 is seen")
+	}
+	if sc.check(key(2)) {
+		t.Error("evicted id 2 is seen")
+	}
+	if len(sc.seen) != 3 {
+		t.Errorf("cache size %d", len(sc.seen))
+	}
+}
+
+func TestCheckGatewayLoop(t *testing.T) {
+	s := newServerInfo()
+	gm := supplyGatewayMsg(1, 3)
+	if reason := s.checkGatewayLoop(gm); reason != "" {
+		t.Errorf("new message: %s", reason)
+	}
+	if reason := s.checkGatewayLoop(supplyGatewayMsg(1, 3)); reason == "" {
+		t.Error("duplicated message is not dropped")
+	}
+	gm = supplyGatewayMsg(2, 3)
+	gm.Visited = append(gm.Visited, server_id)
+	if reason := s.checkGatewayLoop(gm); reason == "" {
+		t.Error("message visited this server is not dropped")
+	}
+	// mbus events have no id, only visited servers are checked
+	ev := mbusEventGatewayMsg(1, api.MbusEvent_LEFT, 2)
+	ev.Visited = []uint64{99}
+	if s.checkGatewayLoop(ev) != "" || s.checkGatewayLoop(ev) != "" {
+		t.Error("mbus event is dropped")
+	}
+}
+
+func TestRelayGatewayMsgHops(t *testing.T) {
+	s := newServerInfo()
+	gw := addTestGateway(s, 1, api.GatewayType_BIDIRECTIONAL)
+	gm := supplyGatewayMsg(1, 3)
+	gm.HopCount = uint32(*maxHops - 1)
+	s.relayGatewayMsg(gm)
+	if len(gw.ch) != 0 {
+		t.Error("message over max hops is relayed")
+	}
+	gm.HopCount = uint32(*maxHops - 2)
+	s.relayGatewayMsg(gm)
+	if len(gw.ch) != 1 {
+		t.Error("message is not relayed")
+	}
+}
@@ -3,7 +3,7 @@ module synerex-server
 go 1.13
 
 require (
-	github.com/golang/protobuf v1.4.3 // indirect
+	github.com/golang/protobuf v1.4.3
 	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.4 // indirect
@@ -19,6 +19,7 @@ require (
 	github.com/synerex/synerex_proto v0.1.9
 	github.com/synerex/synerex_sxutil v0.6.2
 	github.com/x-cray/logrus-prefixed-formatter v0.5.2
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/net v0.0.0-20201207224615-747e23833adb // indirect
 	golang.org/x/sys v0.0.0-20201207223542-d4d67f95c62d // indirect
 	golang.org/x/text v0.3.4 // indirect
@@ -30,4 +31,8 @@ require (
 	gopkg.in/yaml.v2 v2.2.7 // indirect
 )
 
-//replace github.com/synerex/synerex_sxutil => ../sxutil
+replace github.com/synerex/synerex_api => ../api
+
+replace github.com/synerex/synerex_nodeapi => ../nodeapi
+
+replace github.com/synerex/synerex_sxutil => ../sxutil
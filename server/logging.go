@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	api "github.com/synerex/synerex_api"
+	"github.com/synerex/synerex_sxutil/sxlog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Levelled loggers for each component of the server.
+// Levels are given by -loglevel (e.g. "info,server.mbus=debug") and changed by admin SetLogLevel.
+
+var (
+	logLevel  = flag.String("loglevel", getLogLevel(), "Log levels (e.g. \"info,server.mbus=debug,server.subscribe=warn\")")
+	logFormat = flag.String("logformat", getLogFormat(), "Log format (text or json)")
+)
+
+var (
+	logger     = sxlog.New("server")
+	subLog     = logger.Component("subscribe") // subscribe streams and message drops
+	mbusLog    = logger.Component("mbus")
+	gatewayLog = logger.Component("gateway")
+	unaryLog   = logger.Component("unary")
+	adminLog   = logger.Component("admin")
+)
+
+func getLogLevel() string {
+	env := os.Getenv("SX_LOG_LEVEL")
+	if env != "" {
+		return env
+	} else {
+		return "info"
+	}
+}
+
+func getLogFormat() string {
+	env := os.Getenv("SX_LOG_FORMAT")
+	if env != "" {
+		return env
+	} else {
+		return "text"
+	}
+}
+
+// setupLogging applies log flags, and standard log is written as info of the server
+func setupLogging() {
+	if err := sxlog.SetLevels(*logLevel); err != nil {
+		logger.Fatalf("Invalid -loglevel: %v", err)
+	}
+	if err := sxlog.SetFormat(*logFormat); err != nil {
+		logger.Fatalf("Invalid -logformat: %v", err)
+	}
+	sxlog.RedirectStdLog(logger)
+}
+
+// log returns logger with fields of the subscriber
+func (sb *subscriber) log() *sxlog.Logger {
+	return subLog.With("node_id", idNodeID(uint64(sb.clientID))).With("client_id", uint64(sb.clientID)).With("channel_type", sb.chType)
+}
+
+// SetLogLevel sets level of the component and returns current levels (only returns levels if level is empty)
+func (as *adminServer) SetLogLevel(ctx context.Context, ll *api.LogLevel) (*api.LogLevelList, error) {
+	if ll.GetLevel() != "" {
+		lv, err := sxlog.ParseLevel(ll.GetLevel())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		sxlog.SetLevel(ll.GetComponent(), lv)
+		adminLog.Infof("Set log level of %q to %s", ll.GetComponent(), lv)
+	}
+	list := &api.LogLevelList{}
+	for component, lv := range sxlog.Levels() {
+		list.Levels = append(list.Levels, &api.LogLevel{Component: component, Level: lv.String()})
+	}
+	return list, nil
+}
@@ -0,0 +1,561 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mbusInfo keeps the lifecycle, owner and members of each mbus.
+// Mbus is opened by CreateMbus or by Confirm of select (public mbus with the select id),
+// and RPCs for other ids (including removed idle mbus) are rejected with NotFound.
+// Public mbus is learned from other servers with the subscription relayed by gateways,
+// and its options are updated only by the recorded owner. Private mbus is kept on the server created.
+type mbusInfo struct {
+	id          uint64
+	owner       sxutil.IDType
+	private     bool
+	fromGateway bool                   // created by subscription on other server
+	members     map[sxutil.IDType]bool // allowed clients for private mbus (includes owner)
+	status      api.MbusState_MbusStatus
+	subscribers map[sxutil.IDType]int  // number of streams for each subscriber
+	remotes     map[sxutil.IDType]bool // subscribers on other servers (through gateways)
+	lastActive  time.Time              // for idle garbage collection
+	pending     []*api.MbusMsg         // messages sent before the receiver subscribes
+	watchers    map[chan *api.MbusEvent]bool
+}
+
+const mbusWatcherBufferSize = 32
+
+func newMbusInfo(id uint64, mbo *api.MbusOpt) *mbusInfo {
+	mi := &mbusInfo{
+		id:      id,
+		owner:   sxutil.IDType(mbo.GetClientId()),
+		private: mbo.GetMbusType() == api.MbusOpt_PRIVATE,
+		members: make(map[sxutil.IDType]bool),
+
+		status:      api.MbusState_INTIALIZED,
+		subscribers: make(map[sxutil.IDType]int),
+		remotes:     make(map[sxutil.IDType]bool),
+		lastActive:  time.Now(),
+		watchers:    make(map[chan *api.MbusEvent]bool),
+	}
+	if mi.private {
+		mi.members[mi.owner] = true
+		for _, cid := range mbo.GetSubscribers() {
+			mi.members[sxutil.IDType(cid)] = true
+		}
+	}
+	return mi
+}
+
+// opt returns type and members of the mbus for other servers
+func (mi *mbusInfo) opt() *api.MbusOpt {
+	mbo := &api.MbusOpt{ClientId: uint64(mi.owner)}
+	if mi.private {
+		mbo.MbusType = api.MbusOpt_PRIVATE
+		for cid := range mi.members {
+			mbo.Subscribers = append(mbo.Subscribers, uint64(cid))
+		}
+		sort.Slice(mbo.Subscribers, func(i, j int) bool { return mbo.Subscribers[i] < mbo.Subscribers[j] })
+	}
+	return mbo
+}
+
+// updateOpt replaces type and members with the options relayed from other server
+func (mi *mbusInfo) updateOpt(mbo *api.MbusOpt) {
+	opt := newMbusInfo(mi.id, mbo)
+	mi.owner = opt.owner
+	mi.private = opt.private
+	mi.members = opt.members
+}
+
+func (mi *mbusInfo) addSubscriber(cid sxutil.IDType) {
+	mi.subscribers[cid]++
+	if mi.status == api.MbusState_INTIALIZED {
+		mi.status = api.MbusState_SUBSCRIBERS
+	}
+	mi.lastActive = time.Now()
+	if mi.subscribers[cid] == 1 && !mi.remotes[cid] {
+		mi.notify(api.MbusEvent_JOINED, cid)
+	}
+}
+
+func (mi *mbusInfo) removeSubscriber(cid sxutil.IDType) {
+	if mi.subscribers[cid] <= 1 {
+		delete(mi.subscribers, cid)
+		if mi.status != api.MbusState_CLOSED && !mi.remotes[cid] {
+			mi.notify(api.MbusEvent_LEFT, cid)
+		}
+	} else {
+		mi.subscribers[cid]--
+	}
+	mi.updateStatus()
+}
+
+// addRemote adds subscriber on other server. returns false if already added.
+func (mi *mbusInfo) addRemote(cid sxutil.IDType) bool {
+	if mi.remotes[cid] {
+		return false
+	}
+	mi.remotes[cid] = true
+	if mi.status == api.MbusState_INTIALIZED {
+		mi.status = api.MbusState_SUBSCRIBERS
+	}
+	mi.lastActive = time.Now()
+	if mi.subscribers[cid] == 0 {
+		mi.notify(api.MbusEvent_JOINED, cid)
+	}
+	return true
+}
+
+func (mi *mbusInfo) removeRemote(cid sxutil.IDType) {
+	if !mi.remotes[cid] {
+		return
+	}
+	delete(mi.remotes, cid)
+	if mi.status != api.MbusState_CLOSED && mi.subscribers[cid] == 0 {
+		mi.notify(api.MbusEvent_LEFT, cid)
+	}
+	mi.updateStatus()
+}
+
+func (mi *mbusInfo) updateStatus() {
+	if len(mi.subscribers) == 0 && len(mi.remotes) == 0 && mi.status == api.MbusState_SUBSCRIBERS {
+		mi.status = api.MbusState_INTIALIZED
+	}
+	mi.lastActive = time.Now()
+}
+
+// localIDs returns ids of subscribers on this server
+func (mi *mbusInfo) localIDs() []sxutil.IDType {
+	ids := make([]sxutil.IDType, 0, len(mi.subscribers))
+	for cid := range mi.subscribers {
+		ids = append(ids, cid)
+	}
+	return ids
+}
+
+// close marks the mbus closed, pending messages are discarded.
+func (mi *mbusInfo) close() {
+	mi.status = api.MbusState_CLOSED
+	mi.pending = nil
+	mi.lastActive = time.Now()
+	mi.notify(api.MbusEvent_CLOSED, 0)
+	for wch := range mi.watchers { // CLOSED is the last event
+		close(wch)
+		delete(mi.watchers, wch)
+	}
+}
+
+func isMbusReceiver(msg *api.MbusMsg, cid sxutil.IDType) bool {
+	tgt := sxutil.IDType(msg.GetTargetId())
+	return cid != sxutil.IDType(msg.GetSenderId()) && (tgt == 0 || tgt == cid)
+}
+
+// hasReceiver checks whether some local subscriber can receive msg now.
+func (mi *mbusInfo) hasReceiver(msg *api.MbusMsg) bool {
+	for cid := range mi.subscribers {
+		if isMbusReceiver(msg, cid) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRemoteReceiver checks whether some subscriber on other server can receive msg.
+func (mi *mbusInfo) hasRemoteReceiver(msg *api.MbusMsg) bool {
+	for cid := range mi.remotes {
+		if isMbusReceiver(msg, cid) {
+			return true
+		}
+	}
+	return false
+}
+
+// addPending buffers msg until the receiver subscribes. returns false if buffer is full.
+func (mi *mbusInfo) addPending(msg *api.MbusMsg, limit int) bool {
+	if len(mi.pending) >= limit {
+		return false
+	}
+	mi.pending = append(mi.pending, msg)
+	return true
+}
+
+// takePending removes and returns pending messages for the new subscriber.
+func (mi *mbusInfo) takePending(cid sxutil.IDType) []*api.MbusMsg {
+	var msgs []*api.MbusMsg
+	rest := mi.pending[:0]
+	for _, msg := range mi.pending {
+		if isMbusReceiver(msg, cid) {
+			msgs = append(msgs, msg)
+		} else {
+			rest = append(rest, msg)
+		}
+	}
+	mi.pending = rest
+	return msgs
+}
+
+func (mi *mbusInfo) event(tp api.MbusEvent_EventType, cid sxutil.IDType) *api.MbusEvent {
+	return &api.MbusEvent{
+		MbusId:      mi.id,
+		ClientId:    uint64(cid),
+		EventType:   tp,
+		Subscribers: mi.subscriberIDs(),
+	}
+}
+
+// notify sends member event to watchers (slow watcher may lose events)
+func (mi *mbusInfo) notify(tp api.MbusEvent_EventType, cid sxutil.IDType) {
+	if len(mi.watchers) == 0 {
+		return
+	}
+	ev := mi.event(tp, cid)
+	for wch := range mi.watchers {
+		select {
+		case wch <- ev:
+		default:
+			mbusLog.Warnf("MbusEvent drop for mbus %d: %v", mi.id, ev)
+		}
+	}
+}
+
+// subscriberIDs returns sorted ids of current subscribers
+func (mi *mbusInfo) subscriberIDs() []uint64 {
+	ids := make([]uint64, 0, len(mi.subscribers)+len(mi.remotes))
+	for cid := range mi.subscribers {
+		ids = append(ids, uint64(cid))
+	}
+	for cid := range mi.remotes {
+		if mi.subscribers[cid] == 0 {
+			ids = append(ids, uint64(cid))
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// allowed checks whether client can subscribe / send to the mbus
+func (mi *mbusInfo) allowed(cid sxutil.IDType) bool {
+	if mi == nil || !mi.private {
+		return true
+	}
+	return mi.members[cid]
+}
+
+func errMbusClosed(mbid uint64) error {
+	return status.Error(codes.FailedPrecondition, fmt.Sprintf("mbus %d is already closed", mbid))
+}
+
+func errMbusNotFound(mbid uint64) error {
+	return status.Error(codes.NotFound, fmt.Sprintf("mbus %d is not found", mbid))
+}
+
+func errMbusPermission(mbid uint64, cid sxutil.IDType) error {
+	return status.Error(codes.PermissionDenied, fmt.Sprintf("client %d is not a member of private mbus %d", cid, mbid))
+}
+
+// checkMbusMember returns NotFound error if nobody opened the mbus,
+// or PermissionDenied error if client is not allowed on the mbus. (need to lock mmu)
+func (s *synerexServerInfo) checkMbusMember(mbid uint64, cid sxutil.IDType) error {
+	mi, ok := s.mbusInfos[mbid]
+	if !ok {
+		return errMbusNotFound(mbid)
+	}
+	if !mi.allowed(cid) {
+		return errMbusPermission(mbid, cid)
+	}
+	return nil
+}
+
+// checkMbusOwner returns the private mbus if client is the owner. (need to lock mmu)
+func (s *synerexServerInfo) checkMbusOwner(mbid uint64, cid sxutil.IDType) (*mbusInfo, error) {
+	mi, ok := s.mbusInfos[mbid]
+	if !ok {
+		return nil, errMbusNotFound(mbid)
+	}
+	if !mi.private {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("mbus %d is not private", mbid))
+	}
+	if mi.owner != cid {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("client %d is not the owner of mbus %d", cid, mbid))
+	}
+	return mi, nil
+}
+
+// openSelectMbus opens public mbus for confirmed select (mbus id is the select id)
+func (s *synerexServerInfo) openSelectMbus(mbid uint64) {
+	s.mmu.Lock()
+	if _, ok := s.mbusInfos[mbid]; !ok {
+		s.mbusInfos[mbid] = newMbusInfo(mbid, &api.MbusOpt{})
+	}
+	s.mmu.Unlock()
+}
+
+// mbusState returns current state of the mbus for the client.
+// private mbus is hidden from non-members.
+func (s *synerexServerInfo) mbusState(mbid uint64, cid sxutil.IDType) *api.MbusState {
+	mbs := &api.MbusState{
+		MbusId:      mbid,
+		Status:      api.MbusState_INVALID,
+		Subscribers: []uint64{},
+	}
+	s.mmu.RLock()
+	defer s.mmu.RUnlock()
+	mi, ok := s.mbusInfos[mbid]
+	if !ok || !mi.allowed(cid) {
+		return mbs
+	}
+	mbs.Status = mi.status
+	if mi.status == api.MbusState_SUBSCRIBERS {
+		mbs.Subscribers = mi.subscriberIDs()
+	}
+	return mbs
+}
+
+// collectMbus removes mbus which has no subscribers for timeout.
+func (s *synerexServerInfo) collectMbus(timeout time.Duration) {
+	now := time.Now()
+	s.mmu.Lock()
+	for mbid, mi := range s.mbusInfos {
+		if len(mi.subscribers) == 0 && len(mi.remotes) == 0 && len(mi.watchers) == 0 && now.Sub(mi.lastActive) > timeout {
+			mbusLog.Infof("Remove idle mbus %d (%s)", mbid, mi.status)
+			delete(s.mbusInfos, mbid)
+			if len(s.mbusChans[mbid]) == 0 {
+				delete(s.mbusChans, mbid)
+			}
+		}
+	}
+	s.mmu.Unlock()
+}
+
+// mbusGCLoop collects idle mbus periodically.
+func (s *synerexServerInfo) mbusGCLoop(timeout time.Duration) {
+	interval := timeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	for {
+		time.Sleep(interval)
+		s.collectMbus(timeout)
+	}
+}
+
+// closeMbusChans sends close message to all local subscribers. (need to lock mmu)
+func (s *synerexServerInfo) closeMbusChans(mbid uint64) (okFlag bool, okMsg string) {
+	okFlag = true
+	chs := s.mbusChans[mbid] // get channel slice from mbus_id
+	cmsg := &api.MbusMsg{    // this is close message
+		MsgId: 0,
+	}
+	for i := range chs {
+		select {
+		case chs[i] <- cmsg: // run under not blocking state.
+		default:
+			okMsg = fmt.Sprintf("MBusClose MessageDrop %v", cmsg)
+			okFlag = false
+			mbusLog.With("mbus_id", mbid).Warnf("MBusClose MessageDrop")
+		}
+	}
+	return okFlag, okMsg
+}
+
+// closeMbusSubscriber sends close message to the local subscriber (e.g. removed member). (need to lock mmu)
+func (s *synerexServerInfo) closeMbusSubscriber(mbid uint64, cid sxutil.IDType) {
+	if ch, ok := s.mbusMap[cid][mbid]; ok {
+		select {
+		case ch <- &api.MbusMsg{MsgId: 0}: // close message
+		default:
+			mbusLog.Warnf("MBusClose MessageDrop for removed member %d", cid)
+		}
+	}
+}
+
+// sendMbusMsg delivers msg to local subscribers, and to gateways if there are remote receivers.
+// msg is kept in pending buffer if there is no receiver.
+func sendMbusMsg(s *synerexServerInfo, msg *api.MbusMsg, isGateway bool) (okFlag bool, okMsg string) {
+	okFlag = true
+	s.mmu.Lock()
+	mi, ok := s.mbusInfos[msg.GetMbusId()]
+	if !ok { // removed after check
+		s.mmu.Unlock()
+		return false, errMbusNotFound(msg.GetMbusId()).Error()
+	}
+	if mi.status == api.MbusState_CLOSED {
+		s.mmu.Unlock()
+		return false, errMbusClosed(msg.GetMbusId()).Error()
+	}
+	mi.lastActive = time.Now()
+	local := mi.hasReceiver(msg)
+	remote := !isGateway && mi.hasRemoteReceiver(msg)
+	if isGateway && !local { // receiver may be on other servers
+		s.mmu.Unlock()
+		return okFlag, okMsg
+	}
+	if !local && !remote { // keep message until the receiver subscribes
+		if !mi.addPending(msg, *mbusBuffer) {
+			okMsg = fmt.Sprintf("MBus pending buffer is full %v", msg)
+			okFlag = false
+			mbusLog.With("mbus_id", msg.GetMbusId()).Warnf("MBus pending buffer is full, drop message %d", msg.GetMsgId())
+		}
+		s.mmu.Unlock()
+		return okFlag, okMsg
+	}
+	chs := s.mbusChans[msg.GetMbusId()] // get channel slice from mbus_id
+	for i := range chs {
+		select {
+		case chs[i] <- msg: // run under not blocking state.
+		default:
+			okMsg = fmt.Sprintf("MBus MessageDrop %v", msg)
+			okFlag = false
+			mbusLog.With("mbus_id", msg.GetMbusId()).Errorf("MBus MessageDrop %d", msg.GetMsgId())
+		}
+	}
+	s.mmu.Unlock()
+	if remote {
+		s.sendGatewayMsg(mbusMsgGatewayMsg(msg))
+	}
+	return okFlag, okMsg
+}
+
+// mbusGatewayMsg tells the subscriber (or only options if cid is 0) to other servers. (need to lock mmu)
+func mbusGatewayMsg(mi *mbusInfo, cid sxutil.IDType) *api.GatewayMsg {
+	return &api.GatewayMsg{
+		SrcSynerexId: server_id,
+		MsgType:      api.MsgType_MBUS,
+		MsgOneof:     &api.GatewayMsg_Mbus{Mbus: &api.Mbus{ClientId: uint64(cid), MbusId: mi.id, Opt: mi.opt()}},
+	}
+}
+
+func mbusMsgGatewayMsg(msg *api.MbusMsg) *api.GatewayMsg {
+	return &api.GatewayMsg{
+		SrcSynerexId: server_id,
+		MsgType:      api.MsgType_MBUSMSG,
+		MsgOneof:     &api.GatewayMsg_MbusMsg{MbusMsg: msg},
+	}
+}
+
+func mbusEventGatewayMsg(mbid uint64, tp api.MbusEvent_EventType, cid sxutil.IDType) *api.GatewayMsg {
+	return &api.GatewayMsg{
+		SrcSynerexId: server_id,
+		MsgType:      api.MsgType_MBUSEVENT,
+		MsgOneof: &api.GatewayMsg_MbusEvent{MbusEvent: &api.MbusEvent{
+			MbusId:    mbid,
+			ClientId:  uint64(cid),
+			EventType: tp,
+		}},
+	}
+}
+
+// mbusFromGateway registers the subscriber on other server.
+// Unknown public mbus is created with the relayed options (unknown private mbus or mbus without options is rejected).
+// Options of mbus learned from gateways are updated only if the owner is unchanged.
+// Pending messages for the subscriber and local subscribers are sent back through gateways.
+func (s *synerexServerInfo) mbusFromGateway(mb *api.Mbus) (okFlag bool, okMsg string) {
+	cid := sxutil.IDType(mb.GetClientId())
+	mbid := mb.GetMbusId()
+	mbo := mb.GetOpt()
+	var fwd []*api.GatewayMsg
+	s.mmu.Lock()
+	mi, ok := s.mbusInfos[mbid]
+	if !ok {
+		if mbo == nil || mbo.GetMbusType() == api.MbusOpt_PRIVATE {
+			s.mmu.Unlock()
+			mbusLog.Warnf("Reject remote SubscribeMbus: unknown mbus %d without public options", mbid)
+			return false, errMbusNotFound(mbid).Error()
+		}
+		mi = newMbusInfo(mbid, mbo)
+		mi.fromGateway = true
+		s.mbusInfos[mbid] = mi
+	} else if mi.fromGateway && mbo != nil {
+		if sxutil.IDType(mbo.GetClientId()) != mi.owner {
+			s.mmu.Unlock()
+			mbusLog.Warnf("Reject remote options of mbus %d: owner %d is not %d", mbid, mbo.GetClientId(), mi.owner)
+			return false, status.Errorf(codes.PermissionDenied, "options of mbus %d are updated only by the owner", mbid).Error()
+		}
+		mi.updateOpt(mbo)
+		for lid := range mi.subscribers {
+			if !mi.allowed(lid) {
+				s.closeMbusSubscriber(mbid, lid)
+			}
+		}
+	}
+	if cid == 0 { // only options are updated
+		s.mmu.Unlock()
+		return true, ""
+	}
+	if err := s.checkMbusMember(mbid, cid); err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject remote SubscribeMbus: %v", err)
+		return false, err.Error()
+	}
+	if mi.status == api.MbusState_CLOSED {
+		s.mmu.Unlock()
+		s.sendGatewayMsg(mbusEventGatewayMsg(mbid, api.MbusEvent_CLOSED, 0))
+		return false, errMbusClosed(mbid).Error()
+	}
+	if mi.addRemote(cid) { // tell local subscribers to the new server
+		for _, lid := range mi.localIDs() {
+			fwd = append(fwd, mbusGatewayMsg(mi, lid))
+		}
+	}
+	for _, msg := range mi.takePending(cid) {
+		fwd = append(fwd, mbusMsgGatewayMsg(msg))
+	}
+	s.mmu.Unlock()
+	for _, gm := range fwd {
+		s.sendGatewayMsg(gm)
+	}
+	return true, ""
+}
+
+// mbusMsgFromGateway delivers mbus message from other server to local subscribers.
+// Message for unknown mbus is dropped, since there is no local subscriber.
+func (s *synerexServerInfo) mbusMsgFromGateway(msg *api.MbusMsg) (okFlag bool, okMsg string) {
+	s.mmu.RLock()
+	_, ok := s.mbusInfos[msg.GetMbusId()]
+	err := s.checkMbusMember(msg.GetMbusId(), sxutil.IDType(msg.GetSenderId()))
+	s.mmu.RUnlock()
+	if !ok {
+		mbusLog.Debugf("Drop remote SendMbusMsg for unknown mbus %d", msg.GetMbusId())
+		return false, errMbusNotFound(msg.GetMbusId()).Error()
+	}
+	if err != nil {
+		mbusLog.Warnf("Reject remote SendMbusMsg: %v", err)
+		return false, err.Error()
+	}
+	return sendMbusMsg(s, msg, true)
+}
+
+// mbusEventFromGateway handles left member or closed mbus on other server.
+func (s *synerexServerInfo) mbusEventFromGateway(ev *api.MbusEvent) (okFlag bool, okMsg string) {
+	mbid := ev.GetMbusId()
+	cid := sxutil.IDType(ev.GetClientId())
+	s.mmu.Lock()
+	defer s.mmu.Unlock()
+	mi, ok := s.mbusInfos[mbid]
+	if !ok {
+		return true, ""
+	}
+	switch ev.GetEventType() {
+	case api.MbusEvent_LEFT:
+		mi.removeRemote(cid)
+	case api.MbusEvent_CLOSED:
+		if mi.status == api.MbusState_CLOSED {
+			return true, ""
+		}
+		if err := s.checkMbusMember(mbid, cid); err != nil && cid != 0 {
+			mbusLog.Warnf("Reject remote CloseMbus: %v", err)
+			return false, err.Error()
+		}
+		mi.close()
+		return s.closeMbusChans(mbid)
+	}
+	return true, ""
+}
@@ -0,0 +1,559 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	sxutil.InitNodeNum(1) // for GenerateIntID
+}
+
+func privateOpt(owner uint64, members ...uint64) *api.MbusOpt {
+	return &api.MbusOpt{MbusType: api.MbusOpt_PRIVATE, ClientId: owner, Subscribers: members}
+}
+
+func TestMbusFromGatewayUnknown(t *testing.T) {
+	s := newServerInfo()
+	if ok, _ := s.mbusFromGateway(&api.Mbus{ClientId: 10, MbusId: 100}); ok {
+		t.Error("unknown mbus without options is accepted")
+	}
+	if ok, _ := s.mbusMsgFromGateway(&api.MbusMsg{MsgId: 1, SenderId: 10, MbusId: 100}); ok {
+		t.Error("message for unknown mbus is accepted")
+	}
+	if _, ok := s.mbusInfos[100]; ok {
+		t.Error("unknown mbus is created")
+	}
+}
+
+func TestMbusFromGatewayPrivate(t *testing.T) {
+	s := newServerInfo()
+	if ok, _ := s.mbusFromGateway(&api.Mbus{ClientId: 10, MbusId: 100, Opt: privateOpt(10, 11)}); ok {
+		t.Error("unknown private mbus is created from gateway")
+	}
+	if _, ok := s.mbusInfos[100]; ok {
+		t.Fatal("unknown private mbus is created")
+	}
+
+	publicOpt := &api.MbusOpt{ClientId: 10}
+	if ok, msg := s.mbusFromGateway(&api.Mbus{ClientId: 11, MbusId: 100, Opt: publicOpt}); !ok {
+		t.Fatalf("remote subscribe of public mbus: %s", msg)
+	}
+	mi := s.mbusInfos[100]
+	if mi == nil || mi.private || !mi.fromGateway || mi.owner != 10 {
+		t.Fatalf("mbus from gateway: %+v", mi)
+	}
+
+	// options are updated only by the recorded owner
+	if ok, _ := s.mbusFromGateway(&api.Mbus{MbusId: 100, Opt: privateOpt(12, 12)}); ok {
+		t.Error("options from other owner are accepted")
+	}
+	if mi.private || mi.owner != 10 {
+		t.Fatalf("mbus is taken over: %+v", mi)
+	}
+	if ok, msg := s.mbusFromGateway(&api.Mbus{MbusId: 100, Opt: privateOpt(10, 11)}); !ok {
+		t.Fatalf("options from owner: %s", msg)
+	}
+	for cid, allowed := range map[sxutil.IDType]bool{10: true, 11: true, 12: false} {
+		if err := s.checkMbusMember(100, cid); (err == nil) != allowed {
+			t.Errorf("member %d: %v", cid, err)
+		}
+	}
+	if ok, _ := s.mbusFromGateway(&api.Mbus{ClientId: 12, MbusId: 100}); ok {
+		t.Error("remote subscribe of non member is accepted")
+	}
+	if ok, _ := s.mbusMsgFromGateway(&api.MbusMsg{MsgId: 1, SenderId: 12, MbusId: 100}); ok {
+		t.Error("remote message of non member is accepted")
+	}
+
+	// options don't override mbus created on this server
+	s.mbusInfos[200] = newMbusInfo(200, privateOpt(20))
+	s.mbusFromGateway(&api.Mbus{ClientId: 21, MbusId: 200, Opt: &api.MbusOpt{ClientId: 20}})
+	if !s.mbusInfos[200].private || s.checkMbusMember(200, 21) == nil {
+		t.Error("local private mbus is overridden")
+	}
+}
+
+func TestMbusOpt(t *testing.T) {
+	mi := newMbusInfo(1, privateOpt(10, 12, 11))
+	mbo := mi.opt()
+	if mbo.GetMbusType() != api.MbusOpt_PRIVATE || mbo.GetClientId() != 10 {
+		t.Errorf("opt %v", mbo)
+	}
+	want := []uint64{10, 11, 12}
+	if len(mbo.GetSubscribers()) != len(want) {
+		t.Fatalf("subscribers %v", mbo.GetSubscribers())
+	}
+	for i, cid := range want {
+		if mbo.Subscribers[i] != cid {
+			t.Errorf("subscribers %v", mbo.GetSubscribers())
+		}
+	}
+	if newMbusInfo(2, &api.MbusOpt{}).opt().GetMbusType() != api.MbusOpt_PUBLIC {
+		t.Error("public mbus")
+	}
+}
+
+func TestMbusUnknownID(t *testing.T) {
+	s := newServerInfo()
+	for i := uint64(1); i <= 10; i++ {
+		_, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: i, SenderId: 1, MbusId: i})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("send to unknown mbus: %v", err)
+		}
+	}
+	if len(s.mbusInfos) != 0 {
+		t.Errorf("%d mbus are created by SendMbusMsg", len(s.mbusInfos))
+	}
+	if _, err := s.CloseMbus(context.Background(), &api.Mbus{ClientId: 1, MbusId: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("close unknown mbus: %v", err)
+	}
+}
+
+func TestMbusCollectPrivate(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), privateOpt(1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: 1, SenderId: 3, MbusId: mb.MbusId}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("send from non member: %v", err)
+	}
+	s.mbusInfos[mb.MbusId].lastActive = time.Now().Add(-time.Hour)
+	s.collectMbus(time.Minute)
+	if _, ok := s.mbusInfos[mb.MbusId]; ok {
+		t.Fatal("idle mbus is not removed")
+	}
+	// removed private mbus must not become public
+	if _, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: 2, SenderId: 3, MbusId: mb.MbusId}); status.Code(err) != codes.NotFound {
+		t.Errorf("send to removed mbus: %v", err)
+	}
+	if s.mbusState(mb.MbusId, 3).GetStatus() != api.MbusState_INVALID {
+		t.Error("removed mbus is visible")
+	}
+}
+
+func TestMbusSelectConfirm(t *testing.T) {
+	s := newServerInfo()
+	const ctype, id = 3, 12345
+	tch := make(chan *api.Target, 1)
+	s.addWaitConfirm(ctype, id, tch)
+	if _, err := s.Confirm(context.Background(), &api.Target{SenderId: 2, TargetId: id, ChannelType: ctype, MbusId: id}); err != nil {
+		t.Fatal(err)
+	}
+	mi, ok := s.mbusInfos[id]
+	if !ok || mi.private {
+		t.Fatalf("mbus of select is not opened: %+v", mi)
+	}
+	if _, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: 1, SenderId: 2, MbusId: id}); err != nil {
+		t.Errorf("send to select mbus: %v", err)
+	}
+	if len(mi.pending) != 1 {
+		t.Errorf("message is not pending: %d", len(mi.pending))
+	}
+
+	// duplicated Confirm doesn't block after the first one
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Confirm(context.Background(), &api.Target{SenderId: 2, TargetId: id, ChannelType: ctype, MbusId: id})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("duplicated Confirm: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("duplicated Confirm is blocked")
+	}
+	if tg := <-tch; tg.GetSenderId() != 2 {
+		t.Errorf("confirmed target %v", tg)
+	}
+}
+
+func TestMbusStateTransitions(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), privateOpt(1, 2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := s.mbusInfos[mb.MbusId]
+	state := func(cid sxutil.IDType) *api.MbusState { return s.mbusState(mb.MbusId, cid) }
+	if st := state(1).GetStatus(); st != api.MbusState_INTIALIZED {
+		t.Errorf("created mbus = %v", st)
+	}
+	if st := state(9).GetStatus(); st != api.MbusState_INVALID {
+		t.Errorf("private mbus for non member = %v", st)
+	}
+
+	s.mmu.Lock()
+	mi.addSubscriber(2)
+	mi.addSubscriber(2) // second stream of the same client
+	mi.addRemote(3)
+	s.mmu.Unlock()
+	mbs := state(1)
+	if mbs.GetStatus() != api.MbusState_SUBSCRIBERS || len(mbs.GetSubscribers()) != 2 ||
+		mbs.Subscribers[0] != 2 || mbs.Subscribers[1] != 3 {
+		t.Errorf("with subscribers = %v", mbs)
+	}
+
+	s.mmu.Lock()
+	mi.removeSubscriber(2)
+	mi.removeRemote(3)
+	s.mmu.Unlock()
+	if mbs := state(1); mbs.GetStatus() != api.MbusState_SUBSCRIBERS || len(mbs.GetSubscribers()) != 1 {
+		t.Errorf("after one stream left = %v", mbs)
+	}
+	s.mmu.Lock()
+	mi.removeSubscriber(2)
+	s.mmu.Unlock()
+	if mbs := state(1); mbs.GetStatus() != api.MbusState_INTIALIZED || len(mbs.GetSubscribers()) != 0 {
+		t.Errorf("after all left = %v", mbs)
+	}
+
+	if _, err := s.CloseMbus(context.Background(), &api.Mbus{ClientId: 2, MbusId: mb.MbusId}); err != nil {
+		t.Fatal(err)
+	}
+	if st := state(1).GetStatus(); st != api.MbusState_CLOSED {
+		t.Errorf("closed mbus = %v", st)
+	}
+	s.mmu.Lock()
+	mi.addSubscriber(2)
+	mi.removeSubscriber(2)
+	s.mmu.Unlock()
+	if st := state(1).GetStatus(); st != api.MbusState_CLOSED {
+		t.Errorf("closed mbus is reopened = %v", st)
+	}
+	if _, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: 1, SenderId: 1, MbusId: mb.MbusId}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("send to closed mbus: %v", err)
+	}
+}
+
+// fakeWatchStream passes sent events to ch
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	ch  chan *api.MbusEvent
+}
+
+func (fs *fakeWatchStream) Context() context.Context { return fs.ctx }
+
+func (fs *fakeWatchStream) Send(ev *api.MbusEvent) error {
+	fs.ch <- ev
+	return nil
+}
+
+func TestWatchMbusEvents(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &fakeWatchStream{ctx: context.Background(), ch: make(chan *api.MbusEvent, 8)}
+	done := make(chan error, 1)
+	go func() { done <- s.WatchMbus(&api.Mbus{ClientId: 1, MbusId: mb.MbusId}, fs) }()
+	next := func() *api.MbusEvent {
+		select {
+		case ev := <-fs.ch:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return nil
+		}
+	}
+	if ev := next(); ev.GetEventType() != api.MbusEvent_STATE {
+		t.Fatalf("first event = %v", ev)
+	}
+
+	mi := s.mbusInfos[mb.MbusId]
+	s.mmu.Lock()
+	mi.addSubscriber(2)
+	mi.addSubscriber(2) // no event for second stream
+	mi.removeSubscriber(2)
+	mi.removeSubscriber(2)
+	s.mmu.Unlock()
+	if ev := next(); ev.GetEventType() != api.MbusEvent_JOINED || ev.GetClientId() != 2 || len(ev.GetSubscribers()) != 1 {
+		t.Errorf("joined event = %v", ev)
+	}
+	if ev := next(); ev.GetEventType() != api.MbusEvent_LEFT || ev.GetClientId() != 2 || len(ev.GetSubscribers()) != 0 {
+		t.Errorf("left event = %v", ev)
+	}
+
+	s.CloseMbus(context.Background(), &api.Mbus{ClientId: 1, MbusId: mb.MbusId})
+	if ev := next(); ev.GetEventType() != api.MbusEvent_CLOSED {
+		t.Errorf("last event = %v", ev)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WatchMbus: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WatchMbus is not finished by close")
+	}
+}
+
+// fakeMbusStream passes sent messages to ch
+type fakeMbusStream struct {
+	grpc.ServerStream
+	ctx context.Context // background if nil
+	ch  chan *api.MbusMsg
+}
+
+func (fs *fakeMbusStream) Context() context.Context {
+	if fs.ctx == nil {
+		return context.Background()
+	}
+	return fs.ctx
+}
+
+func (fs *fakeMbusStream) Send(msg *api.MbusMsg) error {
+	fs.ch <- msg
+	return nil
+}
+
+func TestMbusPendingUntilSubscribe(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(msgID, sender, target uint64) {
+		r, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: msgID, SenderId: sender, TargetId: target, MbusId: mb.MbusId})
+		if err != nil || !r.Ok {
+			t.Fatalf("send %d: %v %v", msgID, r, err)
+		}
+	}
+	send(1, 1, 0) // broadcast
+	send(2, 1, 3) // for client 3
+	mi := s.mbusInfos[mb.MbusId]
+	if len(mi.pending) != 2 {
+		t.Fatalf("pending = %d", len(mi.pending))
+	}
+
+	fs := &fakeMbusStream{ch: make(chan *api.MbusMsg, 8)}
+	done := make(chan error, 1)
+	go func() { done <- s.SubscribeMbus(&api.Mbus{ClientId: 2, MbusId: mb.MbusId}, fs) }()
+	next := func() *api.MbusMsg {
+		select {
+		case msg := <-fs.ch:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatal("no message")
+			return nil
+		}
+	}
+	if msg := next(); msg.GetMsgId() != 1 {
+		t.Errorf("pending message = %v", msg)
+	}
+	waitMbusSubscribers(t, s, mb.MbusId, 2, 1) // subscribed, next message is sent without pending
+	s.mmu.RLock()
+	rest := len(mi.pending)
+	s.mmu.RUnlock()
+	if rest != 1 {
+		t.Errorf("message for other client is taken: %d pending", rest)
+	}
+	send(3, 1, 0)
+	if msg := next(); msg.GetMsgId() != 3 {
+		t.Errorf("message after subscribe = %v", msg)
+	}
+
+	s.CloseMbus(context.Background(), &api.Mbus{ClientId: 1, MbusId: mb.MbusId})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("SubscribeMbus: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SubscribeMbus is not finished by close")
+	}
+	if len(mi.pending) != 0 {
+		t.Errorf("pending messages are kept after close: %d", len(mi.pending))
+	}
+}
+
+func TestMbusPendingBufferFull(t *testing.T) {
+	defer func(n int) { *mbusBuffer = n }(*mbusBuffer)
+	*mbusBuffer = 2
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(1); i <= 3; i++ {
+		r, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: i, SenderId: 1, MbusId: mb.MbusId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Ok != (i <= 2) {
+			t.Errorf("message %d: %v", i, r)
+		}
+	}
+	// sender's own messages are not delivered to itself
+	s.mmu.Lock()
+	msgs := s.mbusInfos[mb.MbusId].takePending(1)
+	s.mmu.Unlock()
+	if len(msgs) != 0 {
+		t.Errorf("sender takes own messages: %v", msgs)
+	}
+}
+
+func TestMbusMembers(t *testing.T) {
+	s := newServerInfo()
+	if _, err := s.CreateMbus(context.Background(), &api.MbusOpt{MbusType: api.MbusOpt_PRIVATE}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("private mbus without owner: %v", err)
+	}
+	mb, err := s.CreateMbus(context.Background(), privateOpt(1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &fakeMbusStream{ch: make(chan *api.MbusMsg, 8)}
+	if err := s.SubscribeMbus(&api.Mbus{ClientId: 3, MbusId: mb.MbusId}, fs); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("subscribe of non member: %v", err)
+	}
+	members := func(cid uint64, ids ...uint64) *api.MbusMembers {
+		return &api.MbusMembers{ClientId: cid, MbusId: mb.MbusId, Members: ids}
+	}
+	if _, err := s.AddMbusMembers(context.Background(), members(2, 3)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("add by non owner: %v", err)
+	}
+	if _, err := s.AddMbusMembers(context.Background(), members(1, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.checkMbusMember(mb.MbusId, 3); err != nil {
+		t.Errorf("added member: %v", err)
+	}
+
+	// removed member is closed
+	done := make(chan error, 1)
+	go func() { done <- s.SubscribeMbus(&api.Mbus{ClientId: 3, MbusId: mb.MbusId}, fs) }()
+	waitMbusSubscribers(t, s, mb.MbusId, 3, 1)
+	if _, err := s.RemoveMbusMembers(context.Background(), members(1, 1, 3)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("SubscribeMbus of removed member: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("removed member is not closed")
+	}
+	if s.checkMbusMember(mb.MbusId, 3) == nil || s.checkMbusMember(mb.MbusId, 1) != nil {
+		t.Error("owner is removed or member is kept")
+	}
+
+	pub, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddMbusMembers(context.Background(), &api.MbusMembers{ClientId: 1, MbusId: pub.MbusId}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("members of public mbus: %v", err)
+	}
+}
+
+// waitMbusSubscribers waits until the client has n streams on the mbus
+func waitMbusSubscribers(t *testing.T, s *synerexServerInfo, mbid uint64, cid sxutil.IDType, n int) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mmu.RLock()
+		cur := s.mbusInfos[mbid].subscribers[cid]
+		s.mmu.RUnlock()
+		if cur == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("client %d has %d streams, want %d", cid, cur, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSubscribeMbusCancel(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	fs := &fakeMbusStream{ctx: ctx, ch: make(chan *api.MbusMsg, 8)}
+	done := make(chan error, 1)
+	go func() { done <- s.SubscribeMbus(&api.Mbus{ClientId: 2, MbusId: mb.MbusId}, fs) }()
+	waitMbusSubscribers(t, s, mb.MbusId, 2, 1)
+	if mbs := s.mbusState(mb.MbusId, 1); mbs.GetStatus() != api.MbusState_SUBSCRIBERS {
+		t.Fatalf("subscribed mbus = %v", mbs)
+	}
+
+	cancel() // client disconnects
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("SubscribeMbus: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SubscribeMbus is not finished by cancel")
+	}
+	if mbs := s.mbusState(mb.MbusId, 1); mbs.GetStatus() != api.MbusState_INTIALIZED || len(mbs.GetSubscribers()) != 0 {
+		t.Errorf("disconnected client is listed: %v", mbs)
+	}
+	s.mmu.Lock()
+	s.mbusInfos[mb.MbusId].lastActive = time.Now().Add(-time.Hour)
+	s.mmu.Unlock()
+	s.collectMbus(time.Minute)
+	if _, ok := s.mbusInfos[mb.MbusId]; ok {
+		t.Error("mbus of disconnected client is not collected")
+	}
+}
+
+func TestWatchMbusLeftOnDisconnect(t *testing.T) {
+	s := newServerInfo()
+	mb, err := s.CreateMbus(context.Background(), &api.MbusOpt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := &fakeWatchStream{ctx: context.Background(), ch: make(chan *api.MbusEvent, 8)}
+	go s.WatchMbus(&api.Mbus{ClientId: 1, MbusId: mb.MbusId}, ws)
+	next := func() *api.MbusEvent {
+		select {
+		case ev := <-ws.ch:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return nil
+		}
+	}
+	if ev := next(); ev.GetEventType() != api.MbusEvent_STATE {
+		t.Fatalf("first event = %v", ev)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fs := &fakeMbusStream{ctx: ctx, ch: make(chan *api.MbusMsg, 8)}
+	go s.SubscribeMbus(&api.Mbus{ClientId: 2, MbusId: mb.MbusId}, fs)
+	if ev := next(); ev.GetEventType() != api.MbusEvent_JOINED || ev.GetClientId() != 2 {
+		t.Fatalf("joined event = %v", ev)
+	}
+	cancel() // member drops the connection
+	if ev := next(); ev.GetEventType() != api.MbusEvent_LEFT || ev.GetClientId() != 2 || len(ev.GetSubscribers()) != 0 {
+		t.Errorf("left event = %v", ev)
+	}
+
+	// messages for the left member are kept until it subscribes again
+	r, err := s.SendMbusMsg(context.Background(), &api.MbusMsg{MsgId: 1, SenderId: 1, TargetId: 2, MbusId: mb.MbusId})
+	if err != nil || !r.Ok {
+		t.Fatalf("send: %v %v", r, err)
+	}
+	s.mmu.RLock()
+	pending := len(s.mbusInfos[mb.MbusId].pending)
+	s.mmu.RUnlock()
+	if pending != 1 {
+		t.Errorf("message for left member is not pending: %d", pending)
+	}
+	s.CloseMbus(context.Background(), &api.Mbus{ClientId: 1, MbusId: mb.MbusId})
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltMessageStore saves messages in embedded on-disk database (bbolt).
+// Stored messages survive server restarts.
+// AddMessage doesn't wait for the disk, messages are written in batches by the writer goroutine
+// and looked up from pending writes until committed.
+//
+//	messages bucket: mid -> message
+//	order bucket   : timestamp + mid -> nil (for retention)
+//	meta bucket    : "num" -> number of stored messages
+type BoltMessageStore struct {
+	db        *bolt.DB
+	limit     int           // for max number of stored message
+	retention time.Duration // for max age of stored message (0 for no limit)
+	wch       chan boltWrite
+	done      chan struct{}          // closed when the writer is finished
+	pending   map[uint64]boltMessage // messages not committed yet
+	mutex     sync.Mutex
+	cmu       sync.RWMutex // for closed
+	closed    bool
+	dropped   uint64 // number of messages not stored since the write queue is full (atomic)
+}
+
+const (
+	boltWriteBuffer = 4096 // max number of queued messages (AddMessage drops if full)
+	boltMaxBatch    = 1000 // max number of messages in a transaction
+)
+
+type boltWrite struct {
+	mid uint64
+	bm  boltMessage
+}
+
+var (
+	boltMessageBucket = []byte("messages")
+	boltOrderBucket   = []byte("order")
+	boltMetaBucket    = []byte("meta")
+	boltNumKey        = []byte("num")
+)
+
+// json format of the message in bolt
+type boltMessage struct {
+	MsgType string `json:"msg_type"`
+	ChType  int    `json:"ch_type"`
+	Src     uint64 `json:"src"`
+	Dst     uint64 `json:"dst"`
+	Arg     string `json:"arg"`
+	Ts      int64  `json:"ts"` // unix nano
+}
+
+func boltMessageKey(mid uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, mid)
+	return key
+}
+
+func boltOrderKey(ts int64, mid uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(ts))
+	binary.BigEndian.PutUint64(key[8:], mid)
+	return key
+}
+
+// CreateBoltMessageStore opens (or creates) the store file
+func CreateBoltMessageStore(path string, limit int, retention time.Duration) (*BoltMessageStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	mst := &BoltMessageStore{
+		db:        db,
+		limit:     limit,
+		retention: retention,
+		wch:       make(chan boltWrite, boltWriteBuffer),
+		done:      make(chan struct{}),
+		pending:   make(map[uint64]boltMessage),
+	}
+	num := 0
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltMessageBucket); err != nil {
+			return err
+		}
+		ob, err := tx.CreateBucketIfNotExists(boltOrderBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(boltMetaBucket); err != nil {
+			return err
+		}
+		num = ob.Stats().KeyN
+		if err := mst.expire(tx, time.Now(), &num); err != nil {
+			return err
+		}
+		return putBoltNum(tx, num)
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	logger.Infof("Initialize BoltStore %s with %d messages, limit %d retention %v", path, num, limit, retention)
+	go mst.writeLoop()
+	return mst, nil
+}
+
+// number of stored messages is kept in the meta bucket
+func getBoltNum(tx *bolt.Tx) int {
+	val := tx.Bucket(boltMetaBucket).Get(boltNumKey)
+	if len(val) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(val))
+}
+
+func putBoltNum(tx *bolt.Tx, num int) error {
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, uint64(num))
+	return tx.Bucket(boltMetaBucket).Put(boltNumKey, val)
+}
+
+// remove old messages by retention and limit
+func (mst *BoltMessageStore) expire(tx *bolt.Tx, now time.Time, num *int) error {
+	mb := tx.Bucket(boltMessageBucket)
+	c := tx.Bucket(boltOrderBucket).Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.First() {
+		ts := int64(binary.BigEndian.Uint64(k[:8]))
+		if *num <= mst.limit && (mst.retention == 0 || now.Sub(time.Unix(0, ts)) <= mst.retention) {
+			break
+		}
+		if err := mb.Delete(k[8:]); err != nil {
+			return err
+		}
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		*num--
+	}
+	return nil
+}
+
+func (mst *BoltMessageStore) AddMessage(msgType string, chType int, mid uint64, src uint64, dst uint64, arg string) {
+	bm := boltMessage{msgType, chType, src, dst, arg, time.Now().UnixNano()}
+	mst.cmu.RLock()
+	defer mst.cmu.RUnlock()
+	if mst.closed {
+		return
+	}
+	mst.mutex.Lock()
+	mst.pending[mid] = bm
+	mst.mutex.Unlock()
+	select {
+	case mst.wch <- boltWrite{mid, bm}:
+	default: // disk is slow, don't block unary RPCs
+		mst.mutex.Lock()
+		if pbm, ok := mst.pending[mid]; ok && pbm.Ts == bm.Ts {
+			delete(mst.pending, mid)
+		}
+		mst.mutex.Unlock()
+		if n := atomic.AddUint64(&mst.dropped, 1); n == 1 || n%1000 == 0 {
+			logger.Warnf("BoltStore write queue is full, %d messages are not stored", n)
+		}
+	}
+}
+
+// writeLoop writes queued messages in batches until the store is closed
+func (mst *BoltMessageStore) writeLoop() {
+	defer close(mst.done)
+	for w := range mst.wch {
+		batch := []boltWrite{w}
+	drain:
+		for len(batch) < boltMaxBatch {
+			select {
+			case w, ok := <-mst.wch:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, w)
+			default:
+				break drain
+			}
+		}
+		if err := mst.write(batch); err != nil {
+			logger.Errorf("BoltStore AddMessage error %v (%d messages)", err, len(batch))
+		}
+		mst.mutex.Lock()
+		for _, w := range batch {
+			if bm, ok := mst.pending[w.mid]; ok && bm.Ts == w.bm.Ts { // not updated after queued
+				delete(mst.pending, w.mid)
+			}
+		}
+		mst.mutex.Unlock()
+	}
+}
+
+// write stores messages in a transaction
+func (mst *BoltMessageStore) write(batch []boltWrite) error {
+	return mst.db.Update(func(tx *bolt.Tx) error {
+		num := getBoltNum(tx)
+		mb := tx.Bucket(boltMessageBucket)
+		ob := tx.Bucket(boltOrderBucket)
+		for _, w := range batch {
+			val, err := json.Marshal(&w.bm)
+			if err != nil {
+				return err
+			}
+			key := boltMessageKey(w.mid)
+			if old := mb.Get(key); old != nil { // already stored, replace order
+				var obm boltMessage
+				if json.Unmarshal(old, &obm) == nil {
+					if err := ob.Delete(boltOrderKey(obm.Ts, w.mid)); err != nil {
+						return err
+					}
+					num--
+				}
+			}
+			if err := mb.Put(key, val); err != nil {
+				return err
+			}
+			if err := ob.Put(boltOrderKey(w.bm.Ts, w.mid), nil); err != nil {
+				return err
+			}
+			num++
+		}
+		if err := mst.expire(tx, time.Now(), &num); err != nil {
+			return err
+		}
+		return putBoltNum(tx, num)
+	})
+}
+
+func (mst *BoltMessageStore) getSrcId(mid uint64) uint64 {
+	mst.mutex.Lock()
+	bm, found := mst.pending[mid]
+	mst.mutex.Unlock()
+	if !found {
+		err := mst.db.View(func(tx *bolt.Tx) error {
+			val := tx.Bucket(boltMessageBucket).Get(boltMessageKey(mid))
+			if val == nil {
+				return nil
+			}
+			found = true
+			return json.Unmarshal(val, &bm)
+		})
+		if err != nil || !found {
+			return 0
+		}
+	}
+	if mst.retention > 0 && time.Since(time.Unix(0, bm.Ts)) > mst.retention {
+		return 0
+	}
+	return bm.Src
+}
+
+// Close writes queued messages and closes the database
+func (mst *BoltMessageStore) Close() error {
+	mst.cmu.Lock()
+	if !mst.closed {
+		mst.closed = true
+		close(mst.wch)
+	}
+	mst.cmu.Unlock()
+	<-mst.done
+	return mst.db.Close()
+}
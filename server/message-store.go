@@ -1,17 +1,19 @@
 package main
 
 import (
-	"log"
+	"container/list"
+	"fmt"
 	"sync"
+	"time"
 )
 
-// MessageStore saves all massage in memory
-//   this should change into other distributed service.
-/*
-type MessageStore interface{
+// MessageStore saves messages for resolving the sender of proposals.
+// Messages are removed by retention time and max number of messages.
+type MessageStore interface {
 	AddMessage(msgType string, chType int, mid uint64, src uint64, dst uint64, arg string)
 	getSrcId(mid uint64) uint64
-}*/
+	Close() error
+}
 
 type message struct {
 	msgType string
@@ -20,62 +22,103 @@ type message struct {
 	src     uint64
 	dst     uint64
 	arg     string
+	ts      time.Time
+}
+
+// NewMessageStore creates MessageStore with store type ("memory" or "bolt")
+func NewMessageStore(storeType string, path string, limit int, retention time.Duration) (MessageStore, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("invalid message store limit %d", limit)
+	}
+	switch storeType {
+	case "memory":
+		return CreateLocalMessageStore(limit, retention), nil
+	case "bolt":
+		return CreateBoltMessageStore(path, limit, retention)
+	}
+	return nil, fmt.Errorf("unknown message store type %q", storeType)
 }
 
-// real struct for MessageStore
-type MessageStore struct {
-	store     map[uint64]message
-	limit     []uint64 // for storing message history
-	limit_pt  int      // for message index
-	limit_max int      // for max number of stored message
-	count     uint64   // for counting message number (for debug)
+// in-memory struct for MessageStore
+type LocalMessageStore struct {
+	store     map[uint64]*list.Element // message id -> element of message in order
+	order     *list.List               // for storing message history (in arrival order, updated message moves to back)
+	limit     int                      // for max number of stored message
+	retention time.Duration            // for max age of stored message (0 for no limit)
+	count     uint64                   // for counting message number (for debug)
 	mutex     sync.RWMutex
 }
 
-// CreateMessageStore creates base dataset
-func CreateLocalMessageStore() *MessageStore {
-	mst := &MessageStore{}
-	mst.init()
+// CreateLocalMessageStore creates base dataset
+func CreateLocalMessageStore(limit int, retention time.Duration) *LocalMessageStore {
+	mst := &LocalMessageStore{}
+	mst.init(limit, retention)
 	return mst
 }
 
-func (mst *MessageStore) init() {
+func (mst *LocalMessageStore) init(limit int, retention time.Duration) {
 	//	fmt.Println("Initialize LocalStore")
-	mst.store = make(map[uint64]message)
-	mst.mutex = sync.RWMutex{}
-	mst.limit_max = 1000 // todo: Currently, we just store last 1000 messages.
-	mst.limit_pt = 0
+	mst.store = make(map[uint64]*list.Element)
+	mst.order = list.New()
+	mst.limit = limit
 	mst.count = 0
-	mst.limit = make([]uint64, mst.limit_max)
-	log.Printf("Initialize LocalStore with limit %d", mst.limit_max)
+	mst.retention = retention
+	logger.Infof("Initialize LocalStore with limit %d retention %v", limit, retention)
 }
 
-//todo: This is not efficient store. So we need to fix it.
-func (mst *MessageStore) AddMessage(msgType string, chType int, mid uint64, src uint64, dst uint64, arg string) {
+// remove the oldest message (need to lock)
+func (mst *LocalMessageStore) removeOldest() {
+	mes := mst.order.Remove(mst.order.Front()).(message)
+	delete(mst.store, mes.mid)
+}
 
-	mes := message{msgType, chType, mid, src, dst, arg}
-	//	fmt.Printf("AddMessage %v\n",mes)
-	//	fmt.Printf("ls.store %v %d \n",ls.store, mid)
+// remove expired messages (need to lock)
+func (mst *LocalMessageStore) expire(now time.Time) {
+	if mst.retention == 0 {
+		return
+	}
+	for mst.order.Len() > 0 {
+		if now.Sub(mst.order.Front().Value.(message).ts) <= mst.retention {
+			break
+		}
+		mst.removeOldest()
+	}
+}
+
+func (mst *LocalMessageStore) AddMessage(msgType string, chType int, mid uint64, src uint64, dst uint64, arg string) {
+	now := time.Now()
+	mes := message{msgType, chType, mid, src, dst, arg, now}
 	mst.mutex.Lock()
-	if mst.limit[mst.limit_pt] != 0 { // ring buffer, delete last one.
-		delete(mst.store, mst.limit[mst.limit_pt])
-		//		fmt.Printf("mstore: %4d/%7d ",  mst.limit_pt, mst.count)
+	mst.expire(now)
+	if el, ok := mst.store[mid]; ok { // already stored, move to the newest
+		el.Value = mes
+		mst.order.MoveToBack(el)
+		mst.mutex.Unlock()
+		return
 	}
-	mst.store[mid] = mes
+	if mst.order.Len() >= mst.limit { // delete oldest one.
+		mst.removeOldest()
+	}
+	mst.store[mid] = mst.order.PushBack(mes)
 	mst.count++
-	mst.limit[mst.limit_pt] = mid
-	mst.limit_pt = (mst.limit_pt + 1) % mst.limit_max
 	mst.mutex.Unlock()
-	//	fmt.Println("OK.")
 }
 
-func (mst *MessageStore) getSrcId(mid uint64) uint64 {
+func (mst *LocalMessageStore) getSrcId(mid uint64) uint64 {
 	mst.mutex.RLock()
-	mes, ok := mst.store[mid]
-	mst.mutex.RUnlock()
+	defer mst.mutex.RUnlock()
+	el, ok := mst.store[mid]
 	if !ok {
 		//		fmt.Println("Cant find message id Error!")
 		return 0
 	}
+	mes := el.Value.(message)
+	if mst.retention > 0 && time.Since(mes.ts) > mst.retention {
+		return 0
+	}
 	return mes.src
 }
+
+func (mst *LocalMessageStore) Close() error {
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tempStorePath(t testing.TB) string {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "messages.db")
+}
+
+// each test runs for both store types
+func forEachStore(t *testing.T, limit int, retention time.Duration, test func(t *testing.T, mst MessageStore)) {
+	for _, storeType := range []string{"memory", "bolt"} {
+		t.Run(storeType, func(t *testing.T) {
+			mst, err := NewMessageStore(storeType, tempStorePath(t), limit, retention)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mst.Close()
+			test(t, mst)
+		})
+	}
+}
+
+func checkSrc(t *testing.T, mst MessageStore, want map[uint64]uint64) {
+	t.Helper()
+	for mid, src := range want {
+		if got := mst.getSrcId(mid); got != src {
+			t.Errorf("getSrcId(%d) = %d, want %d", mid, got, src)
+		}
+	}
+}
+
+func waitBolt(mst MessageStore) {
+	if bst, ok := mst.(*BoltMessageStore); ok {
+		for {
+			bst.mutex.Lock()
+			n := len(bst.pending)
+			bst.mutex.Unlock()
+			if n == 0 {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestMessageStoreLimit(t *testing.T) {
+	forEachStore(t, 3, 0, func(t *testing.T, mst MessageStore) {
+		for mid := uint64(1); mid <= 4; mid++ {
+			mst.AddMessage("NotifySupply", 1, mid, 100+mid, 0, "")
+			waitBolt(mst) // bolt orders messages by the time of AddMessage
+		}
+		checkSrc(t, mst, map[uint64]uint64{1: 0, 2: 102, 3: 103, 4: 104})
+	})
+}
+
+func TestMessageStoreUpdate(t *testing.T) {
+	forEachStore(t, 3, 0, func(t *testing.T, mst MessageStore) {
+		for mid := uint64(1); mid <= 3; mid++ {
+			mst.AddMessage("NotifySupply", 1, mid, 100+mid, 0, "")
+			waitBolt(mst)
+		}
+		mst.AddMessage("ProposeSupply", 1, 1, 201, 0, "") // updated message becomes the newest
+		waitBolt(mst)
+		mst.AddMessage("NotifySupply", 1, 4, 104, 0, "")
+		waitBolt(mst)
+		checkSrc(t, mst, map[uint64]uint64{1: 201, 2: 0, 3: 103, 4: 104})
+	})
+}
+
+func TestMessageStoreRetention(t *testing.T) {
+	forEachStore(t, 10, 50*time.Millisecond, func(t *testing.T, mst MessageStore) {
+		mst.AddMessage("NotifySupply", 1, 1, 101, 0, "")
+		time.Sleep(60 * time.Millisecond)
+		mst.AddMessage("NotifySupply", 1, 2, 102, 0, "")
+		checkSrc(t, mst, map[uint64]uint64{1: 0, 2: 102})
+	})
+}
+
+func TestBoltMessageStoreReopen(t *testing.T) {
+	path := tempStorePath(t)
+	mst, err := CreateBoltMessageStore(path, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mid := uint64(1); mid <= 20; mid++ {
+		mst.AddMessage("NotifySupply", 1, mid, 100+mid, 0, "")
+	}
+	checkSrc(t, mst, map[uint64]uint64{20: 120}) // pending messages are found before commit
+	if err := mst.Close(); err != nil {          // queued messages are written
+		t.Fatal(err)
+	}
+	mst.AddMessage("NotifySupply", 1, 21, 121, 0, "") // ignored after close
+
+	mst, err = CreateBoltMessageStore(path, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mst.Close()
+	checkSrc(t, mst, map[uint64]uint64{10: 0, 11: 111, 20: 120, 21: 0})
+}
+
+func TestBoltAddMessageQueueFull(t *testing.T) {
+	// store without writer, the queue is full after one message
+	mst := &BoltMessageStore{wch: make(chan boltWrite, 1), pending: make(map[uint64]boltMessage)}
+	done := make(chan struct{})
+	go func() {
+		mst.AddMessage("NotifySupply", 1, 1, 101, 0, "")
+		mst.AddMessage("NotifySupply", 1, 2, 102, 0, "")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AddMessage is blocked by full queue")
+	}
+	if mst.dropped != 1 || len(mst.pending) != 1 || len(mst.wch) != 1 {
+		t.Errorf("dropped %d, pending %d, queued %d", mst.dropped, len(mst.pending), len(mst.wch))
+	}
+	if _, ok := mst.pending[1]; !ok {
+		t.Error("queued message is not pending")
+	}
+}
+
+func BenchmarkBoltAddMessage(b *testing.B) {
+	mst, err := CreateBoltMessageStore(tempStorePath(b), 100000, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mst.AddMessage("NotifySupply", 1, uint64(i+1), 1, 0, "")
+	}
+	mst.Close() // includes writing queued messages
+}
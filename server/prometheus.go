@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Prometheus text format endpoint (/metrics) for server metrics.
+// Counters are kept for each channel type, gauges are obtained at scraping.
+// Channel types are given by clients, so channels over maxMetricChannels are folded into channel="other".
+
+// channel kind for metrics label
+const (
+	kindDemand = "demand"
+	kindSupply = "supply"
+)
+
+// maximum channel types with own label for each metric
+const maxMetricChannels = 256
+
+type channelKey struct {
+	kind  string
+	ctype uint32
+	other bool // folded channels
+}
+
+func (key channelKey) label() string {
+	if key.other {
+		return "other"
+	}
+	return fmt.Sprint(key.ctype)
+}
+
+func channelKeyLess(a, b channelKey) bool {
+	if a.kind != b.kind {
+		return a.kind < b.kind
+	}
+	if a.other != b.other {
+		return b.other
+	}
+	return a.ctype < b.ctype
+}
+
+type channelCount struct {
+	published uint64 // messages received for the channel
+	delivered uint64 // messages queued to subscribers
+	dropped   uint64 // messages dropped by backpressure
+	limited   uint64 // messages rejected by rate limits
+}
+
+// latency histogram buckets (seconds)
+var latencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type latencyHistogram struct {
+	counts []uint64 // for each bucket (not cumulative)
+	count  uint64
+	sum    float64
+}
+
+type serverMetrics struct {
+	channels  map[channelKey]*channelCount
+	latencies map[string]*latencyHistogram // method -> histogram
+	cmu       sync.RWMutex
+	lmu       sync.Mutex
+}
+
+var promMetrics = &serverMetrics{
+	channels:  make(map[channelKey]*channelCount),
+	latencies: make(map[string]*latencyHistogram),
+}
+
+func (sm *serverMetrics) channel(kind string, ctype uint32) *channelCount {
+	key := channelKey{kind: kind, ctype: ctype}
+	sm.cmu.RLock()
+	cc, ok := sm.channels[key]
+	if !ok && len(sm.channels) >= maxMetricChannels {
+		key = channelKey{kind: kind, other: true}
+		cc, ok = sm.channels[key]
+	}
+	sm.cmu.RUnlock()
+	if ok {
+		return cc
+	}
+	sm.cmu.Lock()
+	if cc, ok = sm.channels[key]; !ok {
+		if !key.other && len(sm.channels) >= maxMetricChannels {
+			key = channelKey{kind: kind, other: true}
+		}
+		if cc, ok = sm.channels[key]; !ok {
+			cc = &channelCount{}
+			sm.channels[key] = cc
+		}
+	}
+	sm.cmu.Unlock()
+	return cc
+}
+
+func (sm *serverMetrics) published(kind string, ctype uint32) {
+	atomic.AddUint64(&sm.channel(kind, ctype).published, 1)
+}
+
+func (sm *serverMetrics) delivered(kind string, ctype uint32) {
+	atomic.AddUint64(&sm.channel(kind, ctype).delivered, 1)
+}
+
+func (sm *serverMetrics) dropped(kind string, ctype uint32) {
+	atomic.AddUint64(&sm.channel(kind, ctype).dropped, 1)
+}
+
+func (sm *serverMetrics) limited(kind string, ctype uint32) {
+	atomic.AddUint64(&sm.channel(kind, ctype).limited, 1)
+}
+
+// observeLatency records handler latency of the method
+func (sm *serverMetrics) observeLatency(method string, took time.Duration) {
+	sec := took.Seconds()
+	sm.lmu.Lock()
+	lh, ok := sm.latencies[method]
+	if !ok {
+		lh = &latencyHistogram{counts: make([]uint64, len(latencyBuckets))}
+		sm.latencies[method] = lh
+	}
+	for i, b := range latencyBuckets {
+		if sec <= b {
+			lh.counts[i]++
+			break
+		}
+	}
+	lh.count++
+	lh.sum += sec
+	sm.lmu.Unlock()
+}
+
+func writeMetricHeader(w io.Writer, name, mtype, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, mtype)
+}
+
+func (sm *serverMetrics) writeChannels(w io.Writer) {
+	sm.cmu.RLock()
+	keys := make([]channelKey, 0, len(sm.channels))
+	counts := make(map[channelKey]*channelCount, len(sm.channels))
+	for key, cc := range sm.channels {
+		keys = append(keys, key)
+		counts[key] = cc
+	}
+	sm.cmu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return channelKeyLess(keys[i], keys[j]) })
+	items := []struct {
+		name, help string
+		value      func(cc *channelCount) uint64
+	}{
+		{"synerex_channel_published_total", "Messages published to the channel type.", func(cc *channelCount) uint64 { return atomic.LoadUint64(&cc.published) }},
+		{"synerex_channel_delivered_total", "Messages queued to subscribers of the channel type.", func(cc *channelCount) uint64 { return atomic.LoadUint64(&cc.delivered) }},
+		{"synerex_channel_dropped_total", "Messages dropped for subscribers of the channel type.", func(cc *channelCount) uint64 { return atomic.LoadUint64(&cc.dropped) }},
+		{"synerex_channel_rate_limited_total", "Messages rejected by rate limits of the channel type.", func(cc *channelCount) uint64 { return atomic.LoadUint64(&cc.limited) }},
+	}
+	for _, item := range items {
+		writeMetricHeader(w, item.name, "counter", item.help)
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s{kind=%q,channel=%q} %d\n", item.name, key.kind, key.label(), item.value(counts[key]))
+		}
+	}
+}
+
+func (sm *serverMetrics) writeLatencies(w io.Writer) {
+	name := "synerex_handler_latency_seconds"
+	writeMetricHeader(w, name, "histogram", "Latency of unary API handlers.")
+	sm.lmu.Lock()
+	methods := make([]string, 0, len(sm.latencies))
+	hists := make(map[string]latencyHistogram, len(sm.latencies))
+	for method, lh := range sm.latencies {
+		methods = append(methods, method)
+		hists[method] = latencyHistogram{counts: append([]uint64(nil), lh.counts...), count: lh.count, sum: lh.sum}
+	}
+	sm.lmu.Unlock()
+	sort.Strings(methods)
+	for _, method := range methods {
+		lh := hists[method]
+		var cum uint64
+		for i, b := range latencyBuckets {
+			cum += lh.counts[i]
+			fmt.Fprintf(w, "%s_bucket{method=%q,le=\"%g\"} %d\n", name, method, b, cum)
+		}
+		fmt.Fprintf(w, "%s_bucket{method=%q,le=\"+Inf\"} %d\n", name, method, lh.count)
+		fmt.Fprintf(w, "%s_sum{method=%q} %g\n", name, method, lh.sum)
+		fmt.Fprintf(w, "%s_count{method=%q} %d\n", name, method, lh.count)
+	}
+}
+
+type channelNum struct {
+	key channelKey
+	num int
+}
+
+// foldChannels sorts numbers of channel types and folds channels over maxMetricChannels into "other"
+func foldChannels(kind string, nums map[uint32]int) []channelNum {
+	ctypes := make([]int, 0, len(nums))
+	for ctype := range nums {
+		ctypes = append(ctypes, int(ctype))
+	}
+	sort.Ints(ctypes)
+	var list []channelNum
+	for i, ctype := range ctypes {
+		if i < maxMetricChannels {
+			list = append(list, channelNum{channelKey{kind: kind, ctype: uint32(ctype)}, nums[uint32(ctype)]})
+		} else if i == maxMetricChannels {
+			list = append(list, channelNum{channelKey{kind: kind, other: true}, nums[uint32(ctype)]})
+		} else {
+			list[maxMetricChannels].num += nums[uint32(ctype)]
+		}
+	}
+	return list
+}
+
+// writeGauges writes current subscribers, mbus and gateways of the server
+func (s *synerexServerInfo) writeGauges(w io.Writer) {
+	name := "synerex_subscribers"
+	writeMetricHeader(w, name, "gauge", "Current subscribers of the channel type.")
+	for _, cs := range []struct {
+		kind  string
+		chans map[uint32][]*subscriber
+		mu    *sync.RWMutex
+	}{{kindDemand, s.demandChans, &s.dmu}, {kindSupply, s.supplyChans, &s.smu}} {
+		cs.mu.RLock()
+		subs := make(map[uint32]int, len(cs.chans))
+		for ctype, chs := range cs.chans {
+			subs[ctype] = len(chs)
+		}
+		cs.mu.RUnlock()
+		for _, sc := range foldChannels(cs.kind, subs) {
+			fmt.Fprintf(w, "%s{kind=%q,channel=%q} %d\n", name, cs.kind, sc.key.label(), sc.num)
+		}
+	}
+
+	s.mmu.RLock()
+	mbusNum, mbusSubs := len(s.mbusInfos), 0
+	for _, chs := range s.mbusChans {
+		mbusSubs += len(chs)
+	}
+	s.mmu.RUnlock()
+	writeMetricHeader(w, "synerex_mbus", "gauge", "Current message buses.")
+	fmt.Fprintf(w, "synerex_mbus %d\n", mbusNum)
+	writeMetricHeader(w, "synerex_mbus_subscribers", "gauge", "Current subscribers of message buses.")
+	fmt.Fprintf(w, "synerex_mbus_subscribers %d\n", mbusSubs)
+
+	s.gmu.RLock()
+	gwNum := len(s.gatewayMap)
+	s.gmu.RUnlock()
+	writeMetricHeader(w, "synerex_gateways", "gauge", "Current connected gateways.")
+	fmt.Fprintf(w, "synerex_gateways %d\n", gwNum)
+}
+
+func (s *synerexServerInfo) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, c := range []struct {
+		name, help string
+		value      int64
+	}{
+		{"synerex_messages_total", "Total messages.", totalMessages.Count()},
+		{"synerex_messages_receive_total", "Received messages.", receiveMessages.Count()},
+		{"synerex_messages_send_total", "Sent messages.", sendMessages.Count()},
+		{"synerex_messages_mbus_total", "Mbus messages.", mbusMessages.Count()},
+	} {
+		writeMetricHeader(w, c.name, "counter", c.help)
+		fmt.Fprintf(w, "%s %d\n", c.name, c.value)
+	}
+	promMetrics.writeChannels(w)
+	s.writeGauges(w)
+	promMetrics.writeLatencies(w)
+}
+
+// startMetricsServer serves /metrics at addr
+func (s *synerexServerInfo) startMetricsServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.metricsHandler)
+	logger.Infof("Start metrics endpoint at %s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logger.Errorf("Metrics endpoint error %v", err)
+	}
+}
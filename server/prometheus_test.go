@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestMetrics() *serverMetrics {
+	return &serverMetrics{
+		channels:  make(map[channelKey]*channelCount),
+		latencies: make(map[string]*latencyHistogram),
+	}
+}
+
+func TestMetricsChannels(t *testing.T) {
+	sm := newTestMetrics()
+	sm.published(kindSupply, 14)
+	sm.published(kindSupply, 14)
+	sm.delivered(kindSupply, 14)
+	sm.dropped(kindDemand, 3)
+	sm.limited(kindDemand, 3)
+	var buf bytes.Buffer
+	sm.writeChannels(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		`synerex_channel_published_total{kind="supply",channel="14"} 2`,
+		`synerex_channel_delivered_total{kind="supply",channel="14"} 1`,
+		`synerex_channel_dropped_total{kind="demand",channel="3"} 1`,
+		`synerex_channel_rate_limited_total{kind="demand",channel="3"} 1`,
+		`synerex_channel_published_total{kind="demand",channel="3"} 0`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("no %s in\n%s", line, out)
+		}
+	}
+	if strings.Index(out, `kind="demand",channel="3"`) > strings.Index(out, `kind="supply",channel="14"`) {
+		t.Error("channels are not sorted")
+	}
+}
+
+func TestMetricsChannelCardinality(t *testing.T) {
+	sm := newTestMetrics()
+	for i := 0; i < maxMetricChannels+10; i++ {
+		sm.published(kindSupply, uint32(i))
+	}
+	sm.published(kindSupply, 0)                 // known channel
+	sm.published(kindDemand, 100000)            // other kind is also folded
+	sm.published(kindSupply, maxMetricChannels) // folded channel
+	if len(sm.channels) != maxMetricChannels+2 {
+		t.Errorf("%d channel keys", len(sm.channels))
+	}
+	var buf bytes.Buffer
+	sm.writeChannels(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		`synerex_channel_published_total{kind="supply",channel="0"} 2`,
+		`synerex_channel_published_total{kind="supply",channel="other"} 11`,
+		`synerex_channel_published_total{kind="demand",channel="other"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("no %s", line)
+		}
+	}
+	if strings.Contains(out, `channel="100000"`) {
+		t.Error("folded channel has own label")
+	}
+}
+
+func TestFoldChannels(t *testing.T) {
+	nums := make(map[uint32]int)
+	for i := 0; i < maxMetricChannels+3; i++ {
+		nums[uint32(i)] = 2
+	}
+	list := foldChannels(kindDemand, nums)
+	if len(list) != maxMetricChannels+1 {
+		t.Fatalf("%d channels", len(list))
+	}
+	if list[0].key.label() != "0" || list[0].num != 2 {
+		t.Errorf("first %v", list[0])
+	}
+	if last := list[maxMetricChannels]; last.key.label() != "other" || last.num != 6 {
+		t.Errorf("other %v", last)
+	}
+	if list := foldChannels(kindDemand, map[uint32]int{5: 1, 2: 3}); len(list) != 2 || list[0].key.ctype != 2 {
+		t.Errorf("not sorted %v", list)
+	}
+}
+
+func TestMetricsLatency(t *testing.T) {
+	sm := newTestMetrics()
+	sm.observeLatency("NotifySupply", 2*time.Millisecond)
+	sm.observeLatency("NotifySupply", 20*time.Second)
+	var buf bytes.Buffer
+	sm.writeLatencies(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		`synerex_handler_latency_seconds_bucket{method="NotifySupply",le="0.001"} 0`,
+		`synerex_handler_latency_seconds_bucket{method="NotifySupply",le="0.0025"} 1`,
+		`synerex_handler_latency_seconds_bucket{method="NotifySupply",le="10"} 1`,
+		`synerex_handler_latency_seconds_bucket{method="NotifySupply",le="+Inf"} 2`,
+		`synerex_handler_latency_seconds_count{method="NotifySupply"} 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("no %s in\n%s", line, out)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	s := newServerInfo()
+	s.supplyChans[14] = []*subscriber{newSubscriber(1, 14, 1), newSubscriber(2, 14, 1)}
+	rec := httptest.NewRecorder()
+	s.metricsHandler(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, line := range []string{
+		`synerex_subscribers{kind="supply",channel="14"} 2`,
+		`synerex_mbus 0`,
+		`synerex_gateways 0`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("no %s in\n%s", line, out)
+		}
+	}
+}
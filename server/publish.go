@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Streaming publish (PublishDemand / PublishSupply) for high-throughput producers.
+// Each message is fanned out directly from the stream without unary RPC, and
+// PublishAck with accepted/dropped/denied counts is returned every -publishack messages or every second.
+// Messages denied by auth/ACL are counted as denied, messages rejected by rate limits are counted as dropped,
+// and the stream continues. Err of the ack is the reason of the last drop or denial in the ack window.
+// Chunks of large content take a rate limit token only for the first chunk, and the rest of
+// a message is dropped after a dropped chunk (receivers can't reassemble it).
+
+const publishAckInterval = time.Second
+
+// number of received messages buffered before fan out
+const publishRecvBuffer = 64
+
+type publishRecv struct {
+	msg proto.Message
+	err error
+}
+
+func (s *synerexServerInfo) PublishDemand(stream api.Synerex_PublishDemandServer) error {
+	return s.publishServerFunc(stream, func() proto.Message { return &api.Demand{} })
+}
+
+func (s *synerexServerInfo) PublishSupply(stream api.Synerex_PublishSupplyServer) error {
+	return s.publishServerFunc(stream, func() proto.Message { return &api.Supply{} })
+}
+
+// recvPublish receives messages of the stream until error
+func recvPublish(stream grpc.ServerStream, newMsg func() proto.Message, rch chan<- publishRecv, done <-chan struct{}) {
+	for {
+		msg := newMsg()
+		err := stream.RecvMsg(msg)
+		select {
+		case rch <- publishRecv{msg, err}:
+		case <-done:
+			return
+		}
+		// auth and ACL interceptors return PermissionDenied for each message
+		if err != nil && status.Code(err) != codes.PermissionDenied {
+			return
+		}
+	}
+}
+
+// publishMsg fans out the message to subscribers (and gateways)
+func (s *synerexServerInfo) publishMsg(method string, msg proto.Message) (bool, string) {
+	span := startPublishSpan(method, msg)
+	defer span.End()
+	_, ctype, id := aclRequest("", msg)
+	if ctype == 0 {
+		span.SetError(errChannelType)
+		return false, "ChannelType Error"
+	}
+	s.messageStore.AddMessage(path.Base(method), int(ctype), storeMsgID(publishMsgID(msg), publishCdata(msg)), id, 0, idToNode(id)+"->"+idToNode(0))
+	var okFlag bool
+	var okMsg string
+	switch m := msg.(type) {
+	case *api.Demand:
+		okFlag, okMsg = sendDemand(s, m, false)
+	case *api.Supply:
+		okFlag, okMsg = sendSupply(s, m, false)
+	}
+	sxutil.MsgCountUp()
+	return okFlag, okMsg
+}
+
+func publishMsgID(msg proto.Message) uint64 {
+	switch m := msg.(type) {
+	case *api.Demand:
+		return m.GetId()
+	case *api.Supply:
+		return m.GetId()
+	}
+	return 0
+}
+
+func publishCdata(msg proto.Message) *api.Content {
+	switch m := msg.(type) {
+	case *api.Demand:
+		return m.GetCdata()
+	case *api.Supply:
+		return m.GetCdata()
+	}
+	return nil
+}
+
+var errChannelType = status.Error(codes.InvalidArgument, "ChannelType Error")
+
+func (s *synerexServerInfo) publishServerFunc(stream grpc.ServerStream, newMsg func() proto.Message) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	method := path.Base(fullMethod)
+	if s.isDraining() {
+		return errDraining
+	}
+	rch := make(chan publishRecv, publishRecvBuffer)
+	done := make(chan struct{})
+	defer close(done)
+	go recvPublish(stream, newMsg, rch, done)
+
+	ticker := time.NewTicker(publishAckInterval)
+	defer ticker.Stop()
+	ack := &api.PublishAck{}
+	unacked := 0
+	chunks := make(map[uint64]bool) // chunked message id -> whether the chunks are accepted
+	sendAck := func() error {
+		unacked = 0
+		err := stream.SendMsg(ack)
+		ack.Err = "" // for each ack window
+		ack.RetryAfter = nil
+		return err
+	}
+	for {
+		select {
+		case r := <-rch:
+			if r.err == io.EOF {
+				return sendAck() // final ack
+			}
+			if r.err != nil && status.Code(r.err) != codes.PermissionDenied {
+				return r.err
+			}
+			if s.isDraining() { // client should reconnect to other server
+				sendAck()
+				return errDraining
+			}
+			ack.LastId = publishMsgID(r.msg)
+			unacked++
+			ck := publishCdata(r.msg).GetChunk()
+			rest := ck != nil && ck.GetIndex() > 0 // chunks after the first chunk
+			var resp *api.Response
+			if !rest && r.err == nil {
+				resp = s.limiter.check(method, r.msg)
+			}
+			var dropErr string
+			rejected := true
+			switch {
+			case r.err != nil: // denied
+				unaryLog.Warnf("method %s, %v", method, r.err)
+				ack.Err = r.err.Error()
+			case rest && !chunks[ck.GetMsgId()]:
+				dropErr = fmt.Sprintf("Drop chunk %d of message %d", ck.GetIndex(), ck.GetMsgId())
+			case resp != nil:
+				dropErr = resp.Err
+				ack.RetryAfter = resp.RetryAfter
+			default:
+				rejected = false
+				if ok, msg := s.publishMsg(fullMethod, r.msg); !ok {
+					dropErr = msg
+				}
+			}
+			if ck != nil {
+				if ck.GetIndex()+1 >= ck.GetTotal() {
+					delete(chunks, ck.GetMsgId())
+				} else {
+					chunks[ck.GetMsgId()] = !rejected
+				}
+			}
+			switch {
+			case r.err != nil:
+				ack.Denied++
+			case dropErr != "":
+				ack.Dropped++
+				ack.Err = dropErr
+			default:
+				ack.Accepted++
+			}
+			if resp != nil || unacked >= *publishAck { // tell back off soon for rate limits
+				if err := sendAck(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if unacked > 0 {
+				if err := sendAck(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
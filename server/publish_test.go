@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePublishStream returns recvs in order and keeps sent acks
+type fakePublishStream struct {
+	grpc.ServerStream
+	mu    sync.Mutex
+	recvs []publishRecv
+	acks  []*api.PublishAck
+}
+
+func (fs *fakePublishStream) Context() context.Context { return context.Background() }
+
+func (fs *fakePublishStream) RecvMsg(m interface{}) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if len(fs.recvs) == 0 {
+		return io.EOF
+	}
+	r := fs.recvs[0]
+	fs.recvs = fs.recvs[1:]
+	if r.msg != nil {
+		proto.Merge(m.(proto.Message), r.msg)
+	}
+	return r.err
+}
+
+func (fs *fakePublishStream) SendMsg(m interface{}) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.acks = append(fs.acks, proto.Clone(m.(*api.PublishAck)).(*api.PublishAck))
+	return nil
+}
+
+func publishSupply(id uint64) publishRecv {
+	return publishRecv{msg: &api.Supply{Id: id, SenderId: nodeClientID(1), ChannelType: 14}}
+}
+
+func publishChunk(id uint64, index, total uint32, err error) publishRecv {
+	return publishRecv{msg: &api.Supply{Id: id + uint64(index), SenderId: nodeClientID(1), ChannelType: 14,
+		Cdata: &api.Content{Chunk: &api.Chunk{MsgId: id, Index: index, Total: total}}}, err: err}
+}
+
+var errTestDenied = status.Error(codes.PermissionDenied, "denied")
+
+func runPublish(t *testing.T, recvs ...publishRecv) []*api.PublishAck {
+	orig := *publishAck
+	*publishAck = 2
+	defer func() { *publishAck = orig }()
+	fs := &fakePublishStream{recvs: recvs}
+	if err := newServerInfo().publishServerFunc(fs, func() proto.Message { return &api.Supply{} }); err != nil {
+		t.Fatal(err)
+	}
+	return fs.acks
+}
+
+func TestPublishAckWindow(t *testing.T) {
+	acks := runPublish(t,
+		publishSupply(1),
+		publishRecv{msg: &api.Supply{Id: 2, ChannelType: 14}, err: errTestDenied},
+		publishSupply(3),
+		publishSupply(4),
+		publishSupply(5),
+	)
+	if len(acks) != 3 {
+		t.Fatalf("%d acks", len(acks))
+	}
+	if a := acks[0]; a.Accepted != 1 || a.Denied != 1 || a.Dropped != 0 || a.Err == "" || a.LastId != 2 {
+		t.Errorf("first ack %v", a)
+	}
+	if a := acks[1]; a.Accepted != 3 || a.Denied != 1 || a.Err != "" {
+		t.Errorf("error is not reset: %v", a)
+	}
+	if a := acks[2]; a.Accepted != 4 || a.LastId != 5 {
+		t.Errorf("final ack %v", a)
+	}
+}
+
+func TestPublishChunks(t *testing.T) {
+	acks := runPublish(t,
+		publishChunk(100, 0, 2, errTestDenied),
+		publishChunk(100, 1, 2, nil), // rest of denied message
+		publishChunk(200, 1, 2, nil), // rest of unknown message
+		publishChunk(300, 0, 2, nil),
+		publishChunk(300, 1, 2, nil),
+	)
+	a := acks[len(acks)-1]
+	if a.Denied != 1 || a.Dropped != 2 || a.Accepted != 2 {
+		t.Errorf("final ack %v", a)
+	}
+}
+
+func TestPublishChannelType(t *testing.T) {
+	acks := runPublish(t, publishRecv{msg: &api.Supply{Id: 1, SenderId: nodeClientID(1)}})
+	if a := acks[len(acks)-1]; a.Dropped != 1 || a.Err == "" {
+		t.Errorf("final ack %v", a)
+	}
+}
+
+func TestPublishStreamError(t *testing.T) {
+	fs := &fakePublishStream{recvs: []publishRecv{publishSupply(1), {err: status.Error(codes.Canceled, "canceled")}}}
+	err := newServerInfo().publishServerFunc(fs, func() proto.Message { return &api.Supply{} })
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("error %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+)
+
+// Token bucket rate limits for publish RPCs (Notify/Propose).
+// Limits are given for each sender node and for each channel type, like
+// "*=100:200,12=1000" (messages per second and optional burst, key is node id or channel type).
+// When limited, Response.err is set and Response.retry_after tells when a token is available.
+
+// methods limited by rate limits
+var rateLimitMethods = map[string]bool{
+	"NotifyDemand": true, "NotifySupply": true, "ProposeDemand": true, "ProposeSupply": true,
+	"PublishDemand": true, "PublishSupply": true,
+}
+
+type rateConfig struct {
+	rate  float64 // tokens per second
+	burst float64 // bucket size
+}
+
+// rateLimits keeps config for each key (node id or channel type)
+type rateLimits struct {
+	def    *rateConfig // nil for no limit
+	keyMap map[uint32]*rateConfig
+}
+
+func (rl *rateLimits) get(key uint32) *rateConfig {
+	if rl == nil {
+		return nil
+	}
+	if rc, ok := rl.keyMap[key]; ok {
+		return rc
+	}
+	return rl.def
+}
+
+// parseRateLimits parses limit string like "*=100:200,12=1000"
+func parseRateLimits(str string) (*rateLimits, error) {
+	rl := &rateLimits{keyMap: make(map[uint32]*rateConfig)}
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rate limit item %q", item)
+		}
+		rv := strings.SplitN(kv[1], ":", 2)
+		rate, err := strconv.ParseFloat(rv[0], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate %q", rv[0])
+		}
+		rc := &rateConfig{rate: rate, burst: math.Max(rate, 1)}
+		if len(rv) == 2 {
+			burst, err := strconv.ParseFloat(rv[1], 64)
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst %q", rv[1])
+			}
+			rc.burst = burst
+		}
+		if kv[0] == "*" {
+			rl.def = rc
+		} else {
+			key, err := strconv.ParseUint(kv[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate limit key %q", kv[0])
+			}
+			rl.keyMap[uint32(key)] = rc
+		}
+	}
+	return rl, nil
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// fill adds tokens since last time and returns wait time for one token
+func (tb *tokenBucket) fill(rc *rateConfig, now time.Time) time.Duration {
+	tb.tokens = math.Min(rc.burst, tb.tokens+now.Sub(tb.last).Seconds()*rc.rate)
+	tb.last = now
+	if tb.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tb.tokens) / rc.rate * float64(time.Second))
+}
+
+type rateLimiter struct {
+	senderLimits  *rateLimits
+	channelLimits *rateLimits
+	senders       map[uint32]*tokenBucket // node id -> bucket
+	channels      map[uint32]*tokenBucket // channel type -> bucket
+	mu            sync.Mutex
+}
+
+func newRateLimiter(senderStr, channelStr string) (*rateLimiter, error) {
+	senderLimits, err := parseRateLimits(senderStr)
+	if err != nil {
+		return nil, err
+	}
+	channelLimits, err := parseRateLimits(channelStr)
+	if err != nil {
+		return nil, err
+	}
+	if senderLimits.def == nil && len(senderLimits.keyMap) == 0 &&
+		channelLimits.def == nil && len(channelLimits.keyMap) == 0 {
+		return nil, nil // no limit
+	}
+	return &rateLimiter{
+		senderLimits:  senderLimits,
+		channelLimits: channelLimits,
+		senders:       make(map[uint32]*tokenBucket),
+		channels:      make(map[uint32]*tokenBucket),
+	}, nil
+}
+
+func bucket(buckets map[uint32]*tokenBucket, key uint32, rc *rateConfig, now time.Time) *tokenBucket {
+	tb, ok := buckets[key]
+	if !ok {
+		tb = &tokenBucket{tokens: rc.burst, last: now}
+		buckets[key] = tb
+	}
+	return tb
+}
+
+// allow takes a token of the sender node and the channel type.
+// If not allowed, returns reason and wait time for retry.
+func (rl *rateLimiter) allow(nodeID int32, ctype uint32) (bool, string, time.Duration) {
+	now := time.Now()
+	src, crc := rl.senderLimits.get(uint32(nodeID)), rl.channelLimits.get(ctype)
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	var stb, ctb *tokenBucket
+	if src != nil {
+		stb = bucket(rl.senders, uint32(nodeID), src, now)
+		if wait := stb.fill(src, now); wait > 0 {
+			return false, fmt.Sprintf("node %d", nodeID), wait
+		}
+	}
+	if crc != nil {
+		ctb = bucket(rl.channels, ctype, crc, now)
+		if wait := ctb.fill(crc, now); wait > 0 {
+			return false, fmt.Sprintf("channel %d", ctype), wait
+		}
+	}
+	// take tokens only if both are available
+	if stb != nil {
+		stb.tokens--
+	}
+	if ctb != nil {
+		ctb.tokens--
+	}
+	return true, "", 0
+}
+
+// check returns rate limited response for the publish request (nil if allowed or no limiter)
+func (rl *rateLimiter) check(method string, req interface{}) *api.Response {
+	if rl == nil || !rateLimitMethods[method] {
+		return nil
+	}
+	_, ctype, id := aclRequest(method, req)
+	ok, reason, wait := rl.allow(idNodeID(id), ctype)
+	if ok {
+		return nil
+	}
+	kind := kindSupply
+	if _, isDemand := req.(*api.Demand); isDemand {
+		kind = kindDemand
+	}
+	promMetrics.limited(kind, ctype)
+	return &api.Response{
+		Ok:         false,
+		Err:        fmt.Sprintf("rate limit exceeded for %s, retry after %v", reason, wait),
+		RetryAfter: ptypes.DurationProto(wait),
+	}
+}
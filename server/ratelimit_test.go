@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	rl, err := parseRateLimits("*=100:200, 12=0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := rl.get(3); rc == nil || rc.rate != 100 || rc.burst != 200 {
+		t.Errorf("default = %+v", rc)
+	}
+	if rc := rl.get(12); rc == nil || rc.rate != 0.5 || rc.burst != 1 {
+		t.Errorf("key 12 = %+v, want burst 1 for rate < 1", rc)
+	}
+	for _, str := range []string{"12", "*=0", "*=abc", "*=10:0", "x=10"} {
+		if _, err := parseRateLimits(str); err == nil {
+			t.Errorf("%q: no error", str)
+		}
+	}
+	if rl, err := newRateLimiter("", ""); rl != nil || err != nil {
+		t.Errorf("empty limits = %v, %v", rl, err)
+	}
+}
+
+func TestTokenBucketFill(t *testing.T) {
+	rc := &rateConfig{rate: 10, burst: 2}
+	now := time.Now()
+	tb := &tokenBucket{tokens: 0, last: now}
+	if wait := tb.fill(rc, now); wait != 100*time.Millisecond {
+		t.Errorf("empty bucket wait = %v", wait)
+	}
+	if wait := tb.fill(rc, now.Add(50*time.Millisecond)); wait != 50*time.Millisecond {
+		t.Errorf("half token wait = %v", wait)
+	}
+	if wait := tb.fill(rc, now.Add(time.Second)); wait != 0 || tb.tokens != 2 {
+		t.Errorf("full bucket wait = %v, tokens %v (capped by burst)", wait, tb.tokens)
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl, err := newRateLimiter("*=1:3", "14=1:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if ok, reason, _ := rl.allow(5, 15); !ok {
+			t.Fatalf("message %d limited by %s within burst", i, reason)
+		}
+	}
+	ok, reason, wait := rl.allow(5, 15)
+	if ok || reason != "node 5" || wait <= 0 || wait > time.Second {
+		t.Errorf("over burst = %v %q %v", ok, reason, wait)
+	}
+
+	// channel 14 allows 2 messages of all nodes, node 6 keeps its token when limited by channel
+	for _, node := range []int32{6, 7} {
+		if ok, reason, _ := rl.allow(node, 14); !ok {
+			t.Fatalf("node %d limited by %s", node, reason)
+		}
+	}
+	if ok, reason, _ := rl.allow(6, 14); ok || reason != "channel 14" {
+		t.Errorf("over channel burst = %v %q", ok, reason)
+	}
+	if tokens := rl.senders[6].tokens; tokens < 2 {
+		t.Errorf("node 6 tokens = %v, token is taken when channel is limited", tokens)
+	}
+}
+
+func TestRateLimiterCheck(t *testing.T) {
+	var rl *rateLimiter
+	if resp := rl.check("NotifySupply", &api.Supply{}); resp != nil {
+		t.Errorf("nil limiter = %v", resp)
+	}
+	rl, err := newRateLimiter("*=1:1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := &api.Supply{SenderId: nodeClientID(5), ChannelType: 14}
+	if resp := rl.check("NotifySupply", sp); resp != nil {
+		t.Fatalf("first message = %v", resp)
+	}
+	if resp := rl.check("SubscribeSupply", &api.Channel{ClientId: nodeClientID(5), ChannelType: 14}); resp != nil {
+		t.Errorf("not limited method = %v", resp)
+	}
+	resp := rl.check("NotifySupply", sp)
+	if resp == nil || resp.Ok {
+		t.Fatalf("second message = %v", resp)
+	}
+	if d, err := ptypes.Duration(resp.RetryAfter); err != nil || d <= 0 {
+		t.Errorf("retry after = %v, %v", d, err)
+	}
+}
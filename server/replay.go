@@ -0,0 +1,133 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+)
+
+// Replay log for durable subscriptions.
+// Each demand/supply channel assigns sequence numbers to messages and keeps the last messages,
+// so that a subscriber can resume after the last received sequence number.
+// Sequence numbers are assigned under read lock of dmu/smu, and replay is done under write lock,
+// so no message is lost or duplicated between replay and new messages.
+// Assignment and push to subscribers are serialized for each channel (pushMutex),
+// so subscribers receive sequence numbers in order and resume without gaps.
+// The upper 32 bits of a sequence number are the server epoch (start time in seconds), so sequence numbers
+// keep increasing over server restarts. A resume from another epoch replays all kept messages of current epoch.
+
+type replayLog struct {
+	pushMutex sync.Mutex // held from sequence assignment until the message is pushed to subscribers
+	mutex     sync.Mutex
+	epoch uint64          // server epoch (upper 32 bits of sequence number)
+	seq   uint64          // last assigned sequence number
+	msgs  []proto.Message // ring buffer of messages
+	seqs  []uint64        // sequence number of each message
+	pt    int             // next index of ring buffer
+	num   int             // number of stored messages
+}
+
+const replayEpochShift = 32
+
+func replayEpoch(t time.Time) uint64 {
+	return uint64(uint32(t.Unix())) << replayEpochShift
+}
+
+func newReplayLog(size int, epoch uint64) *replayLog {
+	return &replayLog{
+		epoch: epoch,
+		seq:   epoch,
+		msgs:  make([]proto.Message, size),
+		seqs:  make([]uint64, size),
+	}
+}
+
+// add assigns new sequence number to msg and keeps it.
+func (rl *replayLog) add(msg proto.Message) uint64 {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	rl.seq++
+	if len(rl.msgs) == 0 {
+		return rl.seq
+	}
+	rl.msgs[rl.pt] = msg
+	rl.seqs[rl.pt] = rl.seq
+	rl.pt = (rl.pt + 1) % len(rl.msgs)
+	if rl.num < len(rl.msgs) {
+		rl.num++
+	}
+	return rl.seq
+}
+
+// after returns kept messages with sequence number larger than seq.
+func (rl *replayLog) after(seq uint64) []proto.Message {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if seq>>replayEpochShift != rl.epoch>>replayEpochShift { // resume over server restart
+		subLog.Warnf("Resume from other server epoch: sequence %d, current epoch %d", seq, rl.epoch>>replayEpochShift)
+		seq = rl.epoch
+	}
+	if seq >= rl.seq || rl.num == 0 { // nothing new
+		return nil
+	}
+	var msgs []proto.Message
+	start := (rl.pt - rl.num + len(rl.msgs)) % len(rl.msgs)
+	for i := 0; i < rl.num; i++ {
+		pos := (start + i) % len(rl.msgs)
+		if rl.seqs[pos] > seq {
+			if len(msgs) == 0 && rl.seqs[pos] > seq+1 {
+				subLog.Warnf("Replay gap: messages %d-%d are not kept", seq+1, rl.seqs[pos]-1)
+			}
+			msgs = append(msgs, rl.msgs[pos])
+		}
+	}
+	return msgs
+}
+
+// replayLogs keeps replayLog for each channel type (created lazily)
+type replayLogs struct {
+	mutex sync.Mutex
+	size  int
+	epoch uint64
+	logs  map[uint32]*replayLog
+}
+
+func newReplayLogs(size int) *replayLogs {
+	return &replayLogs{
+		size:  size,
+		epoch: replayEpoch(time.Now()),
+		logs:  make(map[uint32]*replayLog),
+	}
+}
+
+func (rls *replayLogs) get(ctype uint32) *replayLog {
+	rls.mutex.Lock()
+	defer rls.mutex.Unlock()
+	rl, ok := rls.logs[ctype]
+	if !ok {
+		rl = newReplayLog(rls.size, rls.epoch)
+		rls.logs[ctype] = rl
+	}
+	return rl
+}
+
+// newReplaySubscriber creates subscriber with messages after ch.ResumeAfter. (need to lock dmu/smu)
+func newReplaySubscriber(id sxutil.IDType, ctype uint32, ch *api.Channel, rls *replayLogs, filter filterExpr) *subscriber {
+	var replay []proto.Message
+	if ch.GetResumeAfter() > 0 {
+		replay = rls.get(ctype).after(ch.GetResumeAfter())
+		subLog.Infof("Resume client %d channel %d after %d: replay %d messages", id, ctype, ch.GetResumeAfter(), len(replay))
+	}
+	sb := newSubscriber(id, ctype, subscriberBufferSize(ch.GetBufferSize())+len(replay))
+	sb.filter = filter
+	for _, msg := range replay {
+		var ft *filterTarget
+		if sb.accepts(msg, &ft) {
+			sb.ch <- queuedMsg{newEncodedMsg(msg), time.Now()}
+		}
+	}
+	return sb
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+)
+
+const testEpoch = uint64(100) << replayEpochShift
+
+func addReplaySupplies(rl *replayLog, n int) []uint64 {
+	seqs := make([]uint64, n)
+	for i := range seqs {
+		sp := &api.Supply{Id: uint64(i + 1)}
+		sp.Seq = rl.add(sp)
+		seqs[i] = sp.Seq
+	}
+	return seqs
+}
+
+func replayIDs(msgs []proto.Message) []uint64 {
+	ids := make([]uint64, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.(*api.Supply).GetId()
+	}
+	return ids
+}
+
+func equalIDs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReplayLogAfter(t *testing.T) {
+	rl := newReplayLog(3, testEpoch)
+	if msgs := rl.after(testEpoch); msgs != nil {
+		t.Errorf("replay from empty log: %v", msgs)
+	}
+	seqs := addReplaySupplies(rl, 5) // ring keeps 3, 4, 5
+	for i, seq := range seqs {
+		if seq != testEpoch+uint64(i+1) {
+			t.Fatalf("sequence %d = %d", i, seq)
+		}
+	}
+	tests := []struct {
+		after uint64
+		want  []uint64
+	}{
+		{testEpoch, []uint64{3, 4, 5}},     // from start (gap)
+		{seqs[0], []uint64{3, 4, 5}},       // before oldest kept (gap)
+		{seqs[1], []uint64{3, 4, 5}},       // just before oldest kept
+		{seqs[2], []uint64{4, 5}},          // oldest kept
+		{seqs[3], []uint64{5}},             // middle
+		{seqs[4], nil},                     // last
+		{seqs[4] + 10, nil},                // future in same epoch
+		{testEpoch - 1, []uint64{3, 4, 5}}, // previous epoch
+		{testEpoch + uint64(1)<<replayEpochShift + 1, []uint64{3, 4, 5}}, // other epoch
+	}
+	for _, tt := range tests {
+		if got := replayIDs(rl.after(tt.after)); !equalIDs(got, tt.want) {
+			t.Errorf("after(%d) = %v, want %v", tt.after-testEpoch, got, tt.want)
+		}
+	}
+}
+
+func TestReplayLogWrap(t *testing.T) {
+	rl := newReplayLog(4, testEpoch)
+	seqs := addReplaySupplies(rl, 4)
+	if got := replayIDs(rl.after(seqs[0])); !equalIDs(got, []uint64{2, 3, 4}) {
+		t.Errorf("before wrap: %v", got)
+	}
+	addReplaySupplies(rl, 4) // ids 1-4 again, seqs 5-8
+	if got := replayIDs(rl.after(seqs[3])); !equalIDs(got, []uint64{1, 2, 3, 4}) {
+		t.Errorf("after wrap: %v", got)
+	}
+	if got := rl.after(seqs[3] + 4); got != nil {
+		t.Errorf("after last: %v", got)
+	}
+}
+
+func TestReplayLogNoBuffer(t *testing.T) {
+	rl := newReplayLog(0, testEpoch)
+	seqs := addReplaySupplies(rl, 2)
+	if seqs[1] != testEpoch+2 {
+		t.Errorf("sequence = %d", seqs[1])
+	}
+	if msgs := rl.after(seqs[0]); msgs != nil {
+		t.Errorf("replay without buffer: %v", msgs)
+	}
+}
+
+func TestReplayEpoch(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	if replayEpoch(t0) >= replayEpoch(t0.Add(time.Second)) {
+		t.Error("epoch is not increased")
+	}
+	// sequence numbers keep increasing over restart
+	old := newReplayLog(1, replayEpoch(t0))
+	last := addReplaySupplies(old, 10)[9]
+	rl := newReplayLog(1, replayEpoch(t0.Add(time.Second)))
+	if seq := addReplaySupplies(rl, 1)[0]; seq <= last {
+		t.Errorf("sequence %d after restart is not larger than %d", seq, last)
+	}
+	rls := newReplayLogs(1)
+	if rls.get(1) != rls.get(1) || rls.get(1) == rls.get(2) {
+		t.Error("replay log is not kept per channel")
+	}
+	if rls.get(1).epoch != rls.epoch {
+		t.Error("epoch is not set")
+	}
+}
+
+func TestNewReplaySubscriber(t *testing.T) {
+	rls := newReplayLogs(10)
+	rl := rls.get(1)
+	var seqs []uint64
+	for i := 0; i < 4; i++ {
+		seqs = append(seqs, rl.add(&api.Supply{Id: uint64(i + 1), ArgJson: `{"x": ` + string(rune('0'+i)) + `}`}))
+	}
+	fe, err := parseFilter("arg.x != 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := newReplaySubscriber(1, 1, &api.Channel{ChannelType: 1, ResumeAfter: seqs[0]}, rls, fe)
+	var got []uint64
+	for len(sb.ch) > 0 {
+		qm := <-sb.ch
+		got = append(got, qm.em.msg.(*api.Supply).GetId())
+	}
+	if !equalIDs(got, []uint64{2, 4}) {
+		t.Errorf("replayed %v", got)
+	}
+	if cap(sb.ch) < subscriberBufferSize(0)+3 {
+		t.Errorf("buffer size %d", cap(sb.ch))
+	}
+	sb = newReplaySubscriber(1, 1, &api.Channel{ChannelType: 1}, rls, nil)
+	if len(sb.ch) != 0 {
+		t.Errorf("replayed %d messages without resume", len(sb.ch))
+	}
+}
+
+func TestSendSupplySeqOrder(t *testing.T) {
+	s := newServerInfo()
+	const publishers, n = 8, 200
+	sb := newSubscriber(1, 14, publishers*n)
+	s.supplyChans[14] = []*subscriber{sb}
+	var wg sync.WaitGroup
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				sendSupply(s, &api.Supply{Id: uint64(p*n + i + 1), SenderId: 2, ChannelType: 14}, true)
+			}
+		}(p)
+	}
+	wg.Wait()
+	if len(sb.ch) != publishers*n {
+		t.Fatalf("received %d messages", len(sb.ch))
+	}
+	var last uint64
+	for len(sb.ch) > 0 {
+		qm := <-sb.ch
+		seq := qm.em.msg.(*api.Supply).GetSeq()
+		if seq != last+1 && last != 0 {
+			t.Fatalf("sequence %d after %d", seq, last)
+		}
+		last = seq
+	}
+}
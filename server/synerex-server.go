@@ -8,10 +8,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"net"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,41 +19,74 @@ import (
 
 	api "github.com/synerex/synerex_api"
 	nodeapi "github.com/synerex/synerex_nodeapi"
-	pbase "github.com/synerex/synerex_proto"
 	sxutil "github.com/synerex/synerex_sxutil"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/rcrowley/go-metrics"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const MessageChannelBufferSize = 100
 
+// timeout for pushing select message to the subscriber.
+const selectPushTimeout = 5 * time.Second
+
 var (
-	port      = flag.Int("port", getServerPort(), "The Synerex Server Listening Port")
-	servaddr  = flag.String("servaddr", getServerHostName(), "Server Address for Other Providers")
-	nodeport  = flag.Int("nodeport", getNodeservPort(), "The Node ID Server Listening Port")
-	nodeaddr  = flag.String("nodeaddr", getNodeservHostName(), "Node ID Server Address")
-	name      = flag.String("name", getServerName(), "Server Name for Other Providers")
-	isMetrics = flag.Bool("metrics", getIsMetrics(), "Expose Server Metrics")
-	//	log       = logrus.New() // for default logging
-	server_id uint64
-	sinfo     *synerexServerInfo
+	port        = flag.Int("port", getServerPort(), "The Synerex Server Listening Port")
+	servaddr    = flag.String("servaddr", getServerHostName(), "Server Address for Other Providers")
+	nodeport    = flag.Int("nodeport", getNodeservPort(), "The Node ID Server Listening Port")
+	nodeaddr    = flag.String("nodeaddr", getNodeservHostName(), "Node ID Server Address")
+	name        = flag.String("name", getServerName(), "Server Name for Other Providers")
+	isMetrics   = flag.Bool("metrics", getIsMetrics(), "Expose Server Metrics")
+	drainTime   = flag.Duration("draintimeout", getDrainTimeout(), "Max duration for graceful drain on SIGINT/SIGTERM (0 for immediate exit)")
+	adminAddr   = flag.String("adminaddr", getAdminAddr(), "Address of admin service like 127.0.0.1:10080 (empty for disabled)")
+	metricsAddr = flag.String("metricsaddr", getMetricsAddr(), "Address of Prometheus /metrics endpoint (e.g. \":9100\", empty for disabled)")
+	bpolicy     = flag.String("backpressure", getBackpressure(), "Backpressure policy for each channel type (e.g. \"*=drop-newest,11=drop-oldest,14=block:500ms,3=disconnect\")")
+	maxBuffer   = flag.Int("maxbuffer", getMaxBuffer(), "Max subscriber buffer size requested by Channel")
+	mbusTimeout = flag.Duration("mbustimeout", getMbusTimeout(), "Idle timeout for removing mbus without subscribers (0 for no removal)")
+	mbusBuffer  = flag.Int("mbusbuffer", getMbusBuffer(), "Max number of buffered messages for each mbus before the receiver subscribes")
+	maxHops     = flag.Int("maxhops", getMaxHops(), "Max number of gateway hops for relaying gateway messages")
+	storeType   = flag.String("store", getStoreType(), "Message store type (memory or bolt)")
+	storePath   = flag.String("storepath", getStorePath(), "File path of message store (only for bolt)")
+	storeLimit  = flag.Int("storelimit", getStoreLimit(), "Max number of messages in message store")
+	storeRetain = flag.Duration("storeretention", getStoreRetention(), "Retention time of messages in message store (0 for no limit)")
+	replaySize  = flag.Int("replaylog", getReplaySize(), "Number of messages kept for resuming subscription in each channel")
+	publishAck  = flag.Int("publishack", getPublishAck(), "Number of messages for each ack on Publish streams (acks are also sent every second)")
+	maxMsgSize  = flag.Int("maxmsgsize", getMaxMsgSize(), "Max size of gRPC messages received and sent (larger Cdata is split into chunks by sxutil)")
+	tlsOption   = sxutil.TLSFlags() // -tls_ca, -tls_cert, -tls_key, -tls_server_name, -tls_client, -tls_client_auth
+	authKey     = flag.String("authkey", getAuthKey(), "Key for verifying node tokens shared with node server (empty for no auth)")
+	aclFile     = flag.String("acl", getACLFile(), "Channel ACL policy file (json, reloaded when modified)")
+	aclReload   = flag.Duration("aclreload", getACLReload(), "Interval for checking modification of ACL policy file")
+	senderLimit = flag.String("senderlimit", getSenderLimit(), "Rate limits of publishes for each sender node id (e.g. \"*=100:200,12=1000\" as msgs/sec:burst)")
+	chanLimit   = flag.String("channellimit", getChannelLimit(), "Rate limits of publishes for each channel type (e.g. \"*=1000,14=100:200\" as msgs/sec:burst)")
+	traceExport = flag.String("trace", getTraceExport(), "Export target of trace spans (OTLP/JSON file path or collector endpoint like http://localhost:4318, empty for disabled)")
+	server_id   uint64
+	sinfo       *synerexServerInfo
 )
 
 //type sxutil.IDType uint64
 
 type synerexServerInfo struct {
-	demandChans             [pbase.ChannelTypeMax][]chan *api.Demand // create slices for each ChannelType(each slice contains channels)
-	supplyChans             [pbase.ChannelTypeMax][]chan *api.Supply
-	mbusChans               map[uint64][]chan *api.MbusMsg                           // Private Message bus for each provider
-	mbusMap                 map[sxutil.IDType]map[uint64]chan *api.MbusMsg           // map from sxutil.IDType to Mbus channel
-	demandMap               [pbase.ChannelTypeMax]map[sxutil.IDType]chan *api.Demand // map from sxutil.IDType to Demand channel
-	supplyMap               [pbase.ChannelTypeMax]map[sxutil.IDType]chan *api.Supply // map from sxutil.IDType to Supply channel
-	waitConfirms            [pbase.ChannelTypeMax]map[sxutil.IDType]chan *api.Target // confirm maps
-	gatewayMap              map[sxutil.IDType]chan *api.GatewayMsg                   // for gateway. (//TODO: should use channels)
+	demandChans             map[uint32][]*subscriber // create slices for each ChannelType(each slice contains subscribers)
+	supplyChans             map[uint32][]*subscriber
+	mbusChans               map[uint64][]chan *api.MbusMsg                 // Private Message bus for each provider
+	mbusMap                 map[sxutil.IDType]map[uint64]chan *api.MbusMsg // map from sxutil.IDType to Mbus channel
+	mbusInfos               map[uint64]*mbusInfo                           // owner and members of created mbus
+	demandMap               map[uint32]map[sxutil.IDType]*subscriber       // map from sxutil.IDType to Demand subscriber
+	supplyMap               map[uint32]map[sxutil.IDType]*subscriber       // map from sxutil.IDType to Supply subscriber
+	waitConfirms            map[uint32]map[sxutil.IDType]chan *api.Target  // confirm maps
+	gatewayMap              map[sxutil.IDType]*gateway                     // for gateway.
+	seenMsgs                *seenCache                                     // recently seen gateway message ids
 	dmu, smu, mmu, wmu, gmu sync.RWMutex
-	messageStore            *MessageStore // message store
+	messageStore            MessageStore // message store
+	policies                *bpPolicies  // backpressure policies for each channel type
+	demandLogs, supplyLogs  *replayLogs  // replay logs for resuming subscription
+	acl                     *aclStore    // channel ACL policy (nil for no ACL)
+	limiter                 *rateLimiter // rate limits of publishes (nil for no limit)
+	draining                int32        // 1 while draining (atomic)
 }
 
 // for metrics
@@ -111,6 +144,212 @@ func getServerName() string {
 	}
 }
 
+func getBackpressure() string {
+	env := os.Getenv("SX_SERVER_BACKPRESSURE")
+	if env != "" {
+		return env
+	} else {
+		return "*=drop-newest"
+	}
+}
+
+const defaultMaxBuffer = 10000
+
+func getMaxBuffer() int {
+	env := os.Getenv("SX_SERVER_MAX_BUFFER")
+	if env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil || n <= 0 {
+			logger.Warnf("Invalid SX_SERVER_MAX_BUFFER %q, use %d", env, defaultMaxBuffer)
+			return defaultMaxBuffer
+		}
+		return n
+	} else {
+		return defaultMaxBuffer
+	}
+}
+
+func getMbusTimeout() time.Duration {
+	env := os.Getenv("SX_SERVER_MBUS_TIMEOUT")
+	if env != "" {
+		d, _ := time.ParseDuration(env)
+		return d
+	} else {
+		return 10 * time.Minute
+	}
+}
+
+func getMbusBuffer() int {
+	env := os.Getenv("SX_SERVER_MBUS_BUFFER")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 100
+	}
+}
+
+func getMaxHops() int {
+	env := os.Getenv("SX_SERVER_MAX_HOPS")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 8
+	}
+}
+
+func getStoreType() string {
+	env := os.Getenv("SX_SERVER_STORE")
+	if env != "" {
+		return env
+	} else {
+		return "memory"
+	}
+}
+
+func getStorePath() string {
+	env := os.Getenv("SX_SERVER_STORE_PATH")
+	if env != "" {
+		return env
+	} else {
+		return "synerex-messages.db"
+	}
+}
+
+func getStoreLimit() int {
+	env := os.Getenv("SX_SERVER_STORE_LIMIT")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 100000
+	}
+}
+
+func getStoreRetention() time.Duration {
+	env := os.Getenv("SX_SERVER_STORE_RETENTION")
+	if env != "" {
+		d, _ := time.ParseDuration(env)
+		return d
+	} else {
+		return 10 * time.Minute
+	}
+}
+
+func getReplaySize() int {
+	env := os.Getenv("SX_SERVER_REPLAY_LOG")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 1000
+	}
+}
+
+func getPublishAck() int {
+	env := os.Getenv("SX_SERVER_PUBLISH_ACK")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 100
+	}
+}
+
+func getMaxMsgSize() int {
+	env := os.Getenv("SX_SERVER_MAX_MSG_SIZE")
+	if env != "" {
+		env, _ := strconv.Atoi(env)
+		return env
+	} else {
+		return 4 * 1024 * 1024
+	}
+}
+
+func getAuthKey() string {
+	env := os.Getenv("SX_AUTH_KEY")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
+func getACLFile() string {
+	env := os.Getenv("SX_SERVER_ACL")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
+func getACLReload() time.Duration {
+	env := os.Getenv("SX_SERVER_ACL_RELOAD")
+	if env != "" {
+		d, _ := time.ParseDuration(env)
+		return d
+	} else {
+		return 10 * time.Second
+	}
+}
+
+func getTraceExport() string {
+	env := os.Getenv("SX_SERVER_TRACE")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
+func getSenderLimit() string {
+	env := os.Getenv("SX_SERVER_SENDER_LIMIT")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
+func getChannelLimit() string {
+	env := os.Getenv("SX_SERVER_CHANNEL_LIMIT")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
+func getDrainTimeout() time.Duration {
+	env := os.Getenv("SX_SERVER_DRAIN_TIMEOUT")
+	if env != "" {
+		d, _ := time.ParseDuration(env)
+		return d
+	} else {
+		return 10 * time.Second
+	}
+}
+
+func getAdminAddr() string {
+	env := os.Getenv("SX_SERVER_ADMIN_ADDR")
+	if env != "" {
+		return env
+	} else {
+		return "" // disabled
+	}
+}
+
+func getMetricsAddr() string {
+	env := os.Getenv("SX_SERVER_METRICS_ADDR")
+	if env != "" {
+		return env
+	} else {
+		return ""
+	}
+}
+
 func getIsMetrics() bool {
 	env := os.Getenv("SX_SERVER_METRICS")
 	if env == "false" {
@@ -123,14 +362,10 @@ func getIsMetrics() bool {
 func init() {
 	//	sxutil.InitNodeNum(0)
 
-	// for Logrus initialization
-	//	log.Formatter = new(logprefix.TextFormatter)
-	//	log.Level = logrus.DebugLevel // TODO: Should we change this by flag?
-
 	//	log.Printf("Initialized!")
 
 	if *isMetrics {
-		log.Printf("Register Metrics")
+		logger.Debugf("Register Metrics")
 		// for metrics initialization
 		metrics.Register("messages.total", totalMessages)
 		metrics.Register("messages.receive", receiveMessages)
@@ -143,39 +378,55 @@ func init() {
 
 }
 
+// storeMsgID returns message id for the message store.
+// Chunks of large content are stored with the original id, which is selected by receivers.
+func storeMsgID(id uint64, cdata *api.Content) uint64 {
+	if ck := cdata.GetChunk(); ck != nil {
+		return ck.GetMsgId()
+	}
+	return id
+}
+
 func sendDemand(s *synerexServerInfo, dm *api.Demand, isGateway bool) (okFlag bool, okMsg string) {
 	okFlag = true
 	okMsg = ""
 	totalMessages.Inc(1)
 	receiveMessages.Inc(1)
+	bp := s.policies.get(dm.GetChannelType())
+	ck := dm.GetCdata().GetChunk() // chunks of large content are pushed all-or-nothing
+	// sequence and subscribers are taken together for resuming subscription,
+	// then pushed without dmu/smu lock (subscriber slices are copied on write)
+	rl := s.demandLogs.get(dm.GetChannelType())
+	rl.pushMutex.Lock() // push in order of sequence numbers
 	s.dmu.RLock()
+	dm.Seq = rl.add(dm)
 	chs := s.demandChans[dm.GetChannelType()]
+	s.dmu.RUnlock()
+	promMetrics.published(kindDemand, dm.GetChannelType())
+	em := newEncodedMsg(dm) // serialized once for all subscribers
+	var ft *filterTarget    // for server side filter
 	for i := range chs {
-		ch := chs[i]
-		if len(ch) < MessageChannelBufferSize { // performance trouble?
+		if !chs[i].accepts(dm, &ft) {
+			continue
+		}
+		if chs[i].pushMsg(em, bp, ck) {
 			totalMessages.Inc(1)
 			sendMessages.Inc(1)
-			ch <- dm
+			promMetrics.delivered(kindDemand, dm.GetChannelType())
 		} else {
+			promMetrics.dropped(kindDemand, dm.GetChannelType())
 			okFlag = false
 			okMsg = fmt.Sprintf("SendDemand MessageDrop %v", dm)
-			log.Printf(okMsg)
+			chs[i].log().Warnf("SendDemand MessageDrop %d (%s)", dm.Id, bp.policy)
 		}
 	}
-	s.dmu.RUnlock()
-	if len(s.gatewayMap) > 0 && !isGateway {
-		gm := &api.GatewayMsg{
+	rl.pushMutex.Unlock()
+	if !isGateway && s.hasGateway() {
+		s.sendGatewayMsg(&api.GatewayMsg{
 			SrcSynerexId: server_id,
 			MsgType:      api.MsgType_DEMAND,
 			MsgOneof:     &api.GatewayMsg_Demand{Demand: dm},
-		}
-		s.gmu.RLock()
-		for _, gch := range s.gatewayMap { // TODO: may performance check!
-			totalMessages.Inc(1)
-			sendMessages.Inc(1)
-			gch <- gm
-		}
-		s.gmu.RUnlock()
+		})
 	}
 
 	return okFlag, okMsg
@@ -192,36 +443,41 @@ func (s *synerexServerInfo) NotifyDemand(c context.Context, dm *api.Demand) (r *
 func sendSupply(s *synerexServerInfo, sp *api.Supply, isGateway bool) (okFlag bool, okMsg string) {
 	okFlag = true
 	okMsg = ""
-	s.smu.RLock()
+	bp := s.policies.get(sp.GetChannelType())
+	ck := sp.GetCdata().GetChunk() // chunks of large content are pushed all-or-nothing
 	totalMessages.Inc(1)
 	receiveMessages.Inc(1)
+	rl := s.supplyLogs.get(sp.GetChannelType())
+	rl.pushMutex.Lock() // push in order of sequence numbers
+	s.smu.RLock()
+	sp.Seq = rl.add(sp)
 	chs := s.supplyChans[sp.GetChannelType()]
+	s.smu.RUnlock()
+	promMetrics.published(kindSupply, sp.GetChannelType())
+	em := newEncodedMsg(sp) // serialized once for all subscribers
+	var ft *filterTarget    // for server side filter
 	for i := range chs {
-		ch := chs[i]
-		if len(ch) < MessageChannelBufferSize { // run under not blocking state.
+		if !chs[i].accepts(sp, &ft) {
+			continue
+		}
+		if chs[i].pushMsg(em, bp, ck) {
 			totalMessages.Inc(1)
 			sendMessages.Inc(1)
-			ch <- sp
+			promMetrics.delivered(kindSupply, sp.GetChannelType())
 		} else {
+			promMetrics.dropped(kindSupply, sp.GetChannelType())
 			okMsg = fmt.Sprintf("SendSupply MessageDrop %v", sp)
 			okFlag = false
-			log.Printf(okMsg)
+			chs[i].log().Warnf("SendSupply MessageDrop %d (%s)", sp.Id, bp.policy)
 		}
 	}
-	s.smu.RUnlock()
-	if len(s.gatewayMap) > 0 && !isGateway {
-		gm := &api.GatewayMsg{
+	rl.pushMutex.Unlock()
+	if !isGateway && s.hasGateway() {
+		s.sendGatewayMsg(&api.GatewayMsg{
 			SrcSynerexId: server_id,
 			MsgType:      api.MsgType_SUPPLY,
 			MsgOneof:     &api.GatewayMsg_Supply{Supply: sp},
-		}
-		s.gmu.RLock()
-		for _, gch := range s.gatewayMap { // TODO: may performance check!
-			totalMessages.Inc(1)
-			sendMessages.Inc(1)
-			gch <- gm
-		}
-		s.gmu.RUnlock()
+		})
 	}
 	return okFlag, okMsg
 
@@ -230,8 +486,8 @@ func sendSupply(s *synerexServerInfo, sp *api.Supply, isGateway bool) (okFlag bo
 func (s *synerexServerInfo) NotifySupply(c context.Context, sp *api.Supply) (r *api.Response, e error) {
 	//	fmt.Printf("Notify Supply!!!")
 	ctype := sp.GetChannelType()
-	if ctype == 0 || ctype >= pbase.ChannelTypeMax {
-		log.Printf("ChannelType Error! %d", ctype)
+	if ctype == 0 {
+		logger.Warnf("ChannelType Error! %d", ctype)
 		r = &api.Response{Ok: false, Err: "ChannelType Error"}
 		return r, errors.New("ChannelType Error")
 	}
@@ -242,8 +498,8 @@ func (s *synerexServerInfo) NotifySupply(c context.Context, sp *api.Supply) (r *
 
 func (s *synerexServerInfo) ProposeDemand(c context.Context, dm *api.Demand) (r *api.Response, e error) {
 	ctype := dm.GetChannelType()
-	if ctype == 0 || ctype >= pbase.ChannelTypeMax {
-		log.Printf("ChannelType Error! %d", ctype)
+	if ctype == 0 {
+		logger.Warnf("ChannelType Error! %d", ctype)
 		r = &api.Response{Ok: false, Err: "ChannelType Error"}
 		return r, errors.New("ChannelType Error")
 	}
@@ -254,8 +510,8 @@ func (s *synerexServerInfo) ProposeDemand(c context.Context, dm *api.Demand) (r
 }
 func (s *synerexServerInfo) ProposeSupply(c context.Context, sp *api.Supply) (r *api.Response, e error) {
 	ctype := sp.GetChannelType()
-	if ctype == 0 || ctype >= pbase.ChannelTypeMax {
-		log.Printf("ChannelType Error! %d", ctype)
+	if ctype == 0 {
+		logger.Warnf("ChannelType Error! %d", ctype)
 		r = &api.Response{Ok: false, Err: "ChannelType Error"}
 		return r, errors.New("ChannelType Error")
 	}
@@ -267,26 +523,23 @@ func (s *synerexServerInfo) ProposeSupply(c context.Context, sp *api.Supply) (r
 func (s *synerexServerInfo) SelectSupply(c context.Context, tg *api.Target) (r *api.ConfirmResponse, e error) {
 	targetSender := s.messageStore.getSrcId(tg.GetTargetId()) // find source from Id
 	ctype := tg.GetChannelType()
-	if ctype == 0 || ctype >= pbase.ChannelTypeMax {
-		log.Printf("ChannelType Error! %d", ctype)
+	if ctype == 0 {
+		logger.Warnf("ChannelType Error! %d", ctype)
 		r = &api.ConfirmResponse{Ok: false, Err: "ChannelType Error"}
 		return r, errors.New("ChannelType Error")
 	}
 	s.dmu.RLock()
 	// find subscribe demand with sender
-	ch, ok := s.demandMap[ctype][sxutil.IDType(targetSender)]
+	sb, ok := s.demandMap[ctype][sxutil.IDType(targetSender)]
 	s.dmu.RUnlock()
 	if !ok {
-		//TODO: there might be packet through gateway...
-		if len(s.gatewayMap) == 0 {
-			r = &api.ConfirmResponse{Ok: false, Err: "Can't find demand target from SelectSupply"}
-			log.Printf("Can't find SelectSupply target ID %d, src %d", tg.GetTargetId(), targetSender)
-			e = errors.New("Cant find channel in SelectSupply")
-			return
-		} else {
-			// TODO: implement select for gateway!
-			return
+		if s.hasGateway() { // proposer may be behind gateways
+			return s.selectViaGateway(c, api.TargetType_SELECT_SUPPLY, tg)
 		}
+		r = &api.ConfirmResponse{Ok: false, Err: "Can't find demand target from SelectSupply"}
+		logger.Warnf("Can't find SelectSupply target ID %d, src %d", tg.GetTargetId(), targetSender)
+		e = errors.New("Cant find channel in SelectSupply")
+		return
 	}
 	id := sxutil.GenerateIntID()
 	dm := &api.Demand{
@@ -295,55 +548,214 @@ func (s *synerexServerInfo) SelectSupply(c context.Context, tg *api.Target) (r *
 		TargetId:    tg.TargetId,
 		ChannelType: tg.ChannelType,
 		MbusId:      id, // mbus id is a message id for select.
+		TraceParent: sxutil.TraceParentFromContext(c),
 	}
 	//
 	//	args := idToNode(tg.SenderId) + "->" + idToNode(tg.TargetId)
 	//	go monitorapi.SendMessage("ServSelSupply", int(tg.Type), dm.Id, tg.SenderId, tg.TargetId, tg.TargetId, args)
 
-	tch := make(chan *api.Target)
-	s.wmu.Lock()
-	s.waitConfirms[tg.ChannelType][sxutil.IDType(id)] = tch
-	s.wmu.Unlock()
+	tch := make(chan *api.Target, 1)
+	s.addWaitConfirm(tg.ChannelType, id, tch)
+
+	// send select message (select should not be dropped easily)
+	if !sb.push(newEncodedMsg(dm), bpConfig{policy: BlockTimeout, timeout: selectPushTimeout}) {
+		s.removeWaitConfirm(tg.ChannelType, id)
+		r = &api.ConfirmResponse{Ok: false, Err: "Can't send select message"}
+		return r, errors.New("Can't send select message")
+	}
+
+	return s.waitConfirm(c, tg.ChannelType, id, tch)
+}
+
+// SelectDemand is the mirror of SelectSupply.
+// The supplier selects a proposed demand, then the select is sent to the supply stream of the demand owner.
+func (s *synerexServerInfo) SelectDemand(c context.Context, tg *api.Target) (r *api.ConfirmResponse, e error) {
+	targetSender := s.messageStore.getSrcId(tg.GetTargetId()) // find demand owner from Id
+	ctype := tg.GetChannelType()
+	if ctype == 0 {
+		logger.Warnf("ChannelType Error! %d", ctype)
+		r = &api.ConfirmResponse{Ok: false, Err: "ChannelType Error"}
+		return r, errors.New("ChannelType Error")
+	}
+	s.smu.RLock()
+	// find subscribe supply with demand owner
+	sb, ok := s.supplyMap[ctype][sxutil.IDType(targetSender)]
+	s.smu.RUnlock()
+	if !ok {
+		if s.hasGateway() { // proposer may be behind gateways
+			return s.selectViaGateway(c, api.TargetType_SELECT_DEMAND, tg)
+		}
+		r = &api.ConfirmResponse{Ok: false, Err: "Can't find supply target from SelectDemand"}
+		logger.Warnf("Can't find SelectDemand target ID %d, src %d", tg.GetTargetId(), targetSender)
+		return r, errors.New("Cant find channel in SelectDemand")
+	}
+	id := sxutil.GenerateIntID()
+	sp := &api.Supply{
+		Id:          id, // generate ID from synerex server
+		SenderId:    tg.SenderId,
+		TargetId:    tg.TargetId,
+		ChannelType: tg.ChannelType,
+		MbusId:      id, // mbus id is a message id for select.
+		TraceParent: sxutil.TraceParentFromContext(c),
+	}
+
+	tch := make(chan *api.Target, 1)
+	s.addWaitConfirm(tg.ChannelType, id, tch)
 
-	ch <- dm // send select message
+	// send select message (select should not be dropped easily)
+	if !sb.push(newEncodedMsg(sp), bpConfig{policy: BlockTimeout, timeout: selectPushTimeout}) {
+		s.removeWaitConfirm(tg.ChannelType, id)
+		r = &api.ConfirmResponse{Ok: false, Err: "Can't send select message"}
+		return r, errors.New("Can't send select message")
+	}
+
+	return s.waitConfirm(c, tg.ChannelType, id, tch)
+}
+
+func (s *synerexServerInfo) hasGateway() bool {
+	s.gmu.RLock()
+	defer s.gmu.RUnlock()
+	return len(s.gatewayMap) > 0
+}
+
+// sendGatewayTarget sends target message to all gateways
+func (s *synerexServerInfo) sendGatewayTarget(tt api.TargetType, tg *api.Target) {
+	s.sendGatewayMsg(&api.GatewayMsg{
+		SrcSynerexId: server_id,
+		MsgType:      api.MsgType_TARGET,
+		MsgOneof:     &api.GatewayMsg_Target{Target: tg},
+		TargetType:   tt,
+	})
+}
+
+// selectViaGateway forwards select to the servers behind gateways,
+// and waits the Confirm which comes back through gateways.
+func (s *synerexServerInfo) selectViaGateway(c context.Context, tt api.TargetType, tg *api.Target) (r *api.ConfirmResponse, e error) {
+	id := sxutil.GenerateIntID()
+	ftg := &api.Target{
+		Id:          id, // select id is used for correlation of Confirm
+		SenderId:    tg.SenderId,
+		TargetId:    tg.TargetId,
+		ChannelType: tg.ChannelType,
+		Wait:        tg.Wait,
+		MbusId:      id,
+		TraceParent: sxutil.TraceParentFromContext(c),
+	}
+	tch := make(chan *api.Target, 1)
+	s.addWaitConfirm(tg.ChannelType, id, tch)
+	gatewayLog.Infof("Forward %s %d to gateways (target %d)", tt, id, tg.TargetId)
+	s.sendGatewayTarget(tt, ftg)
+	return s.waitConfirm(c, tg.ChannelType, id, tch)
+}
+
+// selectFromGateway delivers the select from other server to the local proposer.
+// Confirm from the proposer is sent back through gateways.
+func (s *synerexServerInfo) selectFromGateway(tt api.TargetType, tg *api.Target) (okFlag bool, okMsg string) {
+	ctype := tg.GetChannelType()
+	targetSender := sxutil.IDType(s.messageStore.getSrcId(tg.GetTargetId()))
+	var sb *subscriber
+	var msg proto.Message
+	ok := false
+	if tt == api.TargetType_SELECT_SUPPLY {
+		s.dmu.RLock()
+		sb, ok = s.demandMap[ctype][targetSender]
+		s.dmu.RUnlock()
+		msg = &api.Demand{Id: tg.Id, SenderId: tg.SenderId, TargetId: tg.TargetId, ChannelType: ctype, MbusId: tg.MbusId, TraceParent: tg.TraceParent}
+	} else {
+		s.smu.RLock()
+		sb, ok = s.supplyMap[ctype][targetSender]
+		s.smu.RUnlock()
+		msg = &api.Supply{Id: tg.Id, SenderId: tg.SenderId, TargetId: tg.TargetId, ChannelType: ctype, MbusId: tg.MbusId, TraceParent: tg.TraceParent}
+	}
+	if !ok { // proposer is not here
+		return false, fmt.Sprintf("Can't find %s target %d", tt, tg.TargetId)
+	}
+
+	tch := make(chan *api.Target, 1)
+	s.addWaitConfirm(ctype, tg.Id, tch)
+	if !sb.push(newEncodedMsg(msg), bpConfig{policy: BlockTimeout, timeout: selectPushTimeout}) {
+		s.removeWaitConfirm(ctype, tg.Id)
+		return false, "Can't send select message"
+	}
+
+	go func() {
+		defer s.removeWaitConfirm(ctype, tg.Id)
+		select {
+		case tb := <-tch:
+			s.sendGatewayTarget(api.TargetType_CONFIRM, tb)
+		case <-time.After(30 * time.Second):
+			gatewayLog.Warnf("Gateway %s %d is not confirmed", tt, tg.Id)
+		}
+	}()
+	return true, ""
+}
 
-	// wait for confim...
+// confirmFromGateway passes the Confirm from other server to the waiting select.
+func (s *synerexServerInfo) confirmFromGateway(tg *api.Target) (okFlag bool, okMsg string) {
+	s.wmu.RLock()
+	ch, ok := s.waitConfirms[tg.ChannelType][sxutil.IDType(tg.TargetId)]
+	s.wmu.RUnlock()
+	if !ok { // not for this server
+		return false, fmt.Sprintf("Can't find targetID %d in channel %d", tg.TargetId, tg.ChannelType)
+	}
+	if tg.MbusId != 0 && tg.MbusId == tg.TargetId { // mbus of the select
+		s.openSelectMbus(tg.MbusId)
+	}
 	select {
+	case ch <- tg:
+	default: // already confirmed
+	}
+	return true, ""
+}
+
+// waitConfirm waits the Confirm for select message id, and returns mbus id.
+func (s *synerexServerInfo) waitConfirm(c context.Context, ctype uint32, id uint64, tch chan *api.Target) (r *api.ConfirmResponse, e error) {
+	defer s.removeWaitConfirm(ctype, id) // remove waitChannel
 
+	select {
 	case tb := <-tch: // got confirm!
-		s.wmu.Lock() // remove waitChannel
-		delete(s.waitConfirms[tg.ChannelType], sxutil.IDType(id))
-		s.wmu.Unlock()
 		//		args := idToNode(tg.SenderId) + "->" + idToNode(tg.TargetId)
 		//		go monitorapi.SendMessage("gotConfirm", int(tg.Type), dm.Id, tb.SenderId, tb.TargetId, tb.TargetId, args)
-
-		if tb.TargetId == id {
-			if tb.MbusId == id {
-				r = &api.ConfirmResponse{Ok: true, Err: "", MbusId: id}
-				return r, nil
-			} else {
-				r = &api.ConfirmResponse{Ok: true, Err: "no mbus id"}
-				return r, nil
-			}
+		if tb.MbusId == id {
+			r = &api.ConfirmResponse{Ok: true, Err: "", MbusId: id}
+		} else {
+			r = &api.ConfirmResponse{Ok: true, Err: "no mbus id"}
 		}
+		return r, nil
 
 	case <-time.After(30 * time.Second): // timeout! // todo: reconsider expiration time.
 		//		args := idToNode(tg.SenderId) + "->" + idToNode(tg.TargetId)
 		//		go monitorapi.SendMessage("notConfirm", int(tg.Type), dm.Id, tg.SenderId, tg.TargetId, tg.TargetId, args)
 		r = &api.ConfirmResponse{Ok: false, Err: "waitConfirm Timeout!"}
+		return r, errors.New("waitConfirm Timeout")
 
+	case <-c.Done(): // caller has gone
+		r = &api.ConfirmResponse{Ok: false, Err: "waitConfirm Canceled"}
+		return r, c.Err()
 	}
-
-	return r, errors.New("Should not happen")
-
 }
 
-func (s *synerexServerInfo) SelectDemand(c context.Context, tg *api.Target) (r *api.ConfirmResponse, e error) {
-	// select!
-	// TODO: not yet implemented...
+// waitConfirms for each channel type are created lazily
+func (s *synerexServerInfo) addWaitConfirm(ctype uint32, id uint64, tch chan *api.Target) {
+	s.wmu.Lock()
+	wm, ok := s.waitConfirms[ctype]
+	if !ok {
+		wm = make(map[sxutil.IDType]chan *api.Target)
+		s.waitConfirms[ctype] = wm
+	}
+	wm[sxutil.IDType(id)] = tch
+	s.wmu.Unlock()
+}
 
-	r = &api.ConfirmResponse{Ok: true, Err: ""}
-	return r, nil
+func (s *synerexServerInfo) removeWaitConfirm(ctype uint32, id uint64) {
+	s.wmu.Lock()
+	if wm, ok := s.waitConfirms[ctype]; ok {
+		delete(wm, sxutil.IDType(id))
+		if len(wm) == 0 {
+			delete(s.waitConfirms, ctype)
+		}
+	}
+	s.wmu.Unlock()
 }
 
 func (s *synerexServerInfo) Confirm(c context.Context, tg *api.Target) (r *api.Response, e error) {
@@ -353,55 +765,64 @@ func (s *synerexServerInfo) Confirm(c context.Context, tg *api.Target) (r *api.R
 	s.wmu.RUnlock()
 	//	go monitorapi.SendMessage("ServConfirm", int(tg.ChannelType), tg.Id, tg.SenderId, 0, tg.TargetId, "ConfirmTo")
 	if !ok {
-		ss := fmt.Sprintf("Can't find targetID %d in channel %d",tg.TargetId ,tg.ChannelType)
-		log.Print(ss)
+		ss := fmt.Sprintf("Can't find targetID %d in channel %d", tg.TargetId, tg.ChannelType)
+		logger.Warnf("%s", ss)
 		r = &api.Response{Ok: false, Err: ss}
 		return r, errors.New(ss)
 	}
-	ch <- tg // send OK
-	r = &api.Response{Ok: true, Err: ""}
-	return r, nil
-}
-
-// go routine which wait demand channel and sending demands to each providers.
-func demandServerFunc(ch chan *api.Demand, stream api.Synerex_SubscribeDemandServer, id sxutil.IDType, chnum uint32) error {
-	for dm := range ch { // block until receiving info
-		err := stream.Send(dm)
-		if err != nil {
-			log.Printf("Error in DemandServer Error %v", err)
-			return err
-		}
+	if tg.MbusId != 0 && tg.MbusId == tg.TargetId { // mbus of the select
+		s.openSelectMbus(tg.MbusId)
 	}
-	log.Printf("SubscribeDemand for Client node %v Channel %d is closed.", id, chnum)
-	return nil
-}
-
-// remove channel from slice
-
-func removeDemandChannelFromSlice(sl []chan *api.Demand, c chan *api.Demand) []chan *api.Demand {
-	for i, ch := range sl {
-		if ch == c {
-			return append(sl[:i], sl[i+1:]...)
-		}
+	select {
+	case ch <- tg: // send OK
+	default: // already confirmed
+		logger.Debugf("Duplicated Confirm for targetID %d in channel %d", tg.TargetId, tg.ChannelType)
 	}
-	log.Printf("Cant find channel %v in removeChannel", c)
-	return sl
+	r = &api.Response{Ok: true, Err: ""}
+	return r, nil
 }
 
-func removeSupplyChannelFromSlice(sl []chan *api.Supply, c chan *api.Supply) []chan *api.Supply {
-	for i, ch := range sl {
-		if ch == c {
-			return append(sl[:i], sl[i+1:]...)
+// go routine which wait subscriber queue and sending demands/supplies to each providers.
+// This function is created for each subscribed provider
+func subscriberServerFunc(sb *subscriber, stream grpc.ServerStream, kind string) error {
+	defer close(sb.stopped)
+	for {
+		select {
+		case qm := <-sb.ch: // block until receiving info
+			err := sb.deliver(stream, qm, kind)
+			if err != nil {
+				sb.log().Warnf("Error in %sServer Error %v", kind, err)
+				return err
+			}
+		case <-sb.done:
+			sb.log().Infof("Subscribe%s is closed.", kind)
+			if sb.err == nil { // graceful close, flush queued messages
+				for len(sb.ch) > 0 {
+					if err := sb.deliver(stream, <-sb.ch, kind); err != nil {
+						return err
+					}
+				}
+			}
+			return sb.err
+		case <-stream.Context().Done():
+			sb.log().Infof("Subscribe%s is canceled.", kind)
+			return stream.Context().Err()
 		}
 	}
-	log.Printf("Cant find channel %v in removeChannel", c)
-	return sl
 }
 
 // SubscribeDemand is called form client to subscribe channel
 func (s *synerexServerInfo) SubscribeDemand(ch *api.Channel, stream api.Synerex_SubscribeDemandServer) error {
 	// TODO: we can check the duplication of node id here! (especially 1024 snowflake node ID)
+	if s.isDraining() {
+		return errDraining
+	}
 	idt := sxutil.IDType(ch.GetClientId())
+	filter, ferr := parseChannelFilter(ch.GetArgJson())
+	if ferr != nil {
+		subLog.Warnf("Invalid filter for SubscribeDemand ClientID %d: %v", idt, ferr)
+		return status.Error(codes.InvalidArgument, ferr.Error())
+	}
 	s.dmu.Lock()
 	_, ok := s.demandMap[ch.ChannelType][idt]
 	if ok { // check the availability of duplicated client ID
@@ -409,50 +830,41 @@ func (s *synerexServerInfo) SubscribeDemand(ch *api.Channel, stream api.Synerex_
 		return fmt.Errorf("duplicated SubscribeDemand ClientID %d", idt)
 	}
 
-	log.Printf("Subscribe Demand Type:%d, From: %x %s", ch.ChannelType, ch.ClientId, ch.ArgJson)
+	subLog.Infof("Subscribe Demand Type:%d, From: %x %s", ch.ChannelType, ch.ClientId, ch.ArgJson)
 	// It is better to logging here.
 	//	monitorapi.SendMes(&monitorapi.Mes{Message:"Subscribe Demand", Args: fmt.Sprintf("Type:%d,From: %x  %s",ch.Type,ch.ClientId, ch.ArgJson )})
 	//	monitorapi.SendMessage("SubscribeDemand", int(ch.Type), 0, ch.ClientId, 0, 0, ch.ArgJson)
 
-	subCh := make(chan *api.Demand, MessageChannelBufferSize)
 	// We should think about thread safe coding.
 	tp := ch.GetChannelType()
-	s.demandChans[tp] = append(s.demandChans[tp], subCh)
-	s.demandMap[tp][idt] = subCh // mapping from clientID to channel
+	sb := newReplaySubscriber(idt, tp, ch, s.demandLogs, filter)
+	addSubscriber(s.demandChans, s.demandMap, sb) // mapping from clientID to subscriber
 	s.dmu.Unlock()
-	demandServerFunc(subCh, stream, idt, tp) // infinite go routine?
+	s.updateNodeChannels()
+	err := subscriberServerFunc(sb, stream, "Demand") // infinite go routine?
 	// if this returns, stream might be closed.
 	// we should remove channel
 
 	s.dmu.Lock()
-	_, ok = s.demandMap[tp][idt]
-	if ok {
-		delete(s.demandMap[tp], idt) // remove map from idt
-		s.demandChans[tp] = removeDemandChannelFromSlice(s.demandChans[tp], subCh)
-		log.Printf("Remove Demand Stream Channel %v", ch)
+	if removeSubscriber(s.demandChans, s.demandMap, sb) { // still exist? (may removed by others)
+		subLog.Infof("Remove Demand Stream Channel %v", ch)
 	}
 	s.dmu.Unlock()
-	return nil
-}
-
-// This function is created for each subscribed provider
-// This is not efficient if the number of providers increases.
-func supplyServerFunc(ch chan *api.Supply, stream api.Synerex_SubscribeSupplyServer, idt sxutil.IDType, chnum uint32) error {
-	for sp := range ch { // block until receiving info
-		err := stream.Send(sp)
-		if err != nil {
-			log.Printf("Error in SupplyServer Error %v", err)
-			log.Printf("SubscribeSupply for Client node %v Channel %d is closed.", idt, chnum)
-			return err
-		}
-	}
-	log.Printf("SubscribeSupply for Client node %v Channel %d is closed.", idt, chnum)
-	return nil
+	s.updateNodeChannels()
+	return err
 }
 
 func (s *synerexServerInfo) SubscribeSupply(ch *api.Channel, stream api.Synerex_SubscribeSupplyServer) error {
 	idt := sxutil.IDType(ch.GetClientId())
 	tp := ch.GetChannelType()
+	if s.isDraining() {
+		return errDraining
+	}
+	filter, ferr := parseChannelFilter(ch.GetArgJson())
+	if ferr != nil {
+		subLog.Warnf("Invalid filter for SubscribeSupply ClientID %d: %v", idt, ferr)
+		return status.Error(codes.InvalidArgument, ferr.Error())
+	}
 	s.smu.Lock()
 	_, ok := s.supplyMap[tp][idt]
 	if ok { // check the availability of duplicated client ID
@@ -460,26 +872,24 @@ func (s *synerexServerInfo) SubscribeSupply(ch *api.Channel, stream api.Synerex_
 		return errors.New(fmt.Sprintf("duplicated SubscribeSupply for ClientID %v", idt))
 	}
 
-	subCh := make(chan *api.Supply, MessageChannelBufferSize)
+	sb := newReplaySubscriber(idt, tp, ch, s.supplyLogs, filter)
 
-	log.Printf("Subscribe Supply Channel:%d, Node:%d Args: %s", ch.ChannelType, ch.ClientId, ch.ArgJson)
+	subLog.Infof("Subscribe Supply Channel:%d, Node:%d Args: %s", ch.ChannelType, ch.ClientId, ch.ArgJson)
 	//	monitorapi.SendMes(&monitorapi.Mes{Message:"Subscribe Supply", Args: fmt.Sprintf("Type:%d, From: %x %s",ch.Type,ch.ClientId,ch.ArgJson )})
 	//	monitorapi.SendMessage("SubscribeSupply", int(ch.Type), 0, ch.ClientId, 0, 0, ch.ArgJson)
 
-	s.supplyChans[tp] = append(s.supplyChans[tp], subCh)
-	s.supplyMap[tp][idt] = subCh // mapping from clientID to channel
+	addSubscriber(s.supplyChans, s.supplyMap, sb) // mapping from clientID to subscriber
 	s.smu.Unlock()
-	err := supplyServerFunc(subCh, stream, idt, tp)
+	s.updateNodeChannels()
+	err := subscriberServerFunc(sb, stream, "Supply")
 	// this supply stream may closed. so take care.
 
 	s.smu.Lock()
-	_, ok = s.supplyMap[tp][idt] // still exist? (may removed by others)
-	if ok {
-		delete(s.supplyMap[tp], idt) // remove map from idt
-		s.supplyChans[tp] = removeSupplyChannelFromSlice(s.supplyChans[tp], subCh)
-		log.Printf("Remove Supply Stream Channel %v", ch)
+	if removeSubscriber(s.supplyChans, s.supplyMap, sb) { // still exist? (may removed by others)
+		subLog.Infof("Remove Supply Stream Channel %v", ch)
 	}
 	s.smu.Unlock()
+	s.updateNodeChannels()
 
 	return err
 }
@@ -489,52 +899,77 @@ func (s *synerexServerInfo) CloseDemandChannel(ctx context.Context, ch *api.Chan
 	idt := sxutil.IDType(ch.GetClientId())
 	tp := ch.GetChannelType()
 	err = nil
-	s.smu.Lock()
-	subCh, ok := s.demandMap[tp][idt]
+	s.dmu.Lock()
+	sb, ok := s.demandMap[tp][idt]
 	if ok {
-		delete(s.demandMap[tp], idt) // remove map from idt
-		s.demandChans[tp] = removeDemandChannelFromSlice(s.demandChans[tp], subCh)
-		log.Printf("Remove Demand Channel %v", ch)
-		close(subCh) // close subchannel!
+		removeSubscriber(s.demandChans, s.demandMap, sb) // remove map from idt
+		subLog.Infof("Remove Demand Channel %v", ch)
+		sb.finish(nil) // close subscriber!
 		resp = &api.Response{
 			Ok: true,
 		}
 	} else {
-		log.Printf("Cannot find Demand Channel %v", ch)
+		subLog.Warnf("Cannot find Demand Channel %v", ch)
 		resp = &api.Response{
 			Ok:  false,
 			Err: fmt.Sprintf("Cannot find Demand Channel %v", ch),
 		}
 	}
-	s.smu.Unlock()
+	s.dmu.Unlock()
+	s.updateNodeChannels()
 	return resp, nil
 }
 
-//
 func (s *synerexServerInfo) CloseSupplyChannel(ctx context.Context, ch *api.Channel) (resp *api.Response, err error) {
 	idt := sxutil.IDType(ch.GetClientId())
 	tp := ch.GetChannelType()
 	s.smu.Lock()
-	subCh, ok := s.supplyMap[tp][idt]
+	sb, ok := s.supplyMap[tp][idt]
 	if ok {
-		delete(s.supplyMap[tp], idt) // remove map from idt
-		s.supplyChans[tp] = removeSupplyChannelFromSlice(s.supplyChans[tp], subCh)
-		log.Printf("Remove Supply Channel %v", ch)
-		close(subCh) // close subchannel!
+		removeSubscriber(s.supplyChans, s.supplyMap, sb) // remove map from idt
+		subLog.Infof("Remove Supply Channel %v", ch)
+		sb.finish(nil) // close subscriber!
 		resp = &api.Response{
 			Ok: true,
 		}
 	} else {
-		log.Printf("Cannot find Supply Channel %v", ch)
+		subLog.Warnf("Cannot find Supply Channel %v", ch)
 		resp = &api.Response{
 			Ok:  false,
 			Err: fmt.Sprintf("Cannot find Supply Channel %v", ch),
 		}
 	}
 	s.smu.Unlock()
+	s.updateNodeChannels()
 	return resp, nil
 }
 
+// usedChannelTypes returns channel types which have subscribers.
+func (s *synerexServerInfo) usedChannelTypes() []uint32 {
+	tps := make(map[uint32]bool)
+	s.dmu.RLock()
+	for tp := range s.demandMap {
+		tps[tp] = true
+	}
+	s.dmu.RUnlock()
+	s.smu.RLock()
+	for tp := range s.supplyMap {
+		tps[tp] = true
+	}
+	s.smu.RUnlock()
+	channels := make([]uint32, 0, len(tps))
+	for tp := range tps {
+		channels = append(channels, tp)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i] < channels[j] })
+	return channels
+}
+
+// updateNodeChannels reports channel types in use to node server
+func (s *synerexServerInfo) updateNodeChannels() {
+	sxutil.SetNodeChannelTypes(s.usedChannelTypes())
+}
+
 func showAllSubscribers() {
 	supp := make([]string, 0)
 	for tp, chans := range sinfo.supplyMap {
@@ -554,7 +989,7 @@ func showAllSubscribers() {
 		}
 	}
 
-	log.Printf("ShowAll: %v", supp)
+	logger.Debugf("ShowAll: %v", supp)
 }
 
 func closeAllChannels(node_id int32) {
@@ -562,26 +997,27 @@ func closeAllChannels(node_id int32) {
 	sinfo.smu.Lock()
 	// starting from supplyMap
 	for tp, chans := range sinfo.supplyMap {
-		subCh, ok := chans[idt]
+		sb, ok := chans[idt]
 		if ok {
-			delete(chans, idt) // remove map from idt
 			// log.Printf("Length of supplyChans %d", len(sinfo.supplyChans[tp]))
-			sinfo.supplyChans[tp] = removeSupplyChannelFromSlice(sinfo.supplyChans[tp], subCh)
-			log.Printf("Remove Supply Channel node_id %v, chan %v", idt, tp)
-			close(subCh) // close subchannel!
+			removeSubscriber(sinfo.supplyChans, sinfo.supplyMap, sb) // remove map from idt
+			subLog.Infof("Remove Supply Channel node_id %v, chan %v", idt, tp)
+			sb.finish(nil) // close subscriber!
 		}
 	}
+	sinfo.smu.Unlock()
+	sinfo.dmu.Lock()
 	for tp, chans := range sinfo.demandMap {
-		subCh, ok := chans[idt]
+		sb, ok := chans[idt]
 		if ok {
-			delete(chans, idt) // remove map from idt
 			// log.Printf("Length of demandChans %d", len(sinfo.demandChans[tp]))
-			sinfo.demandChans[tp] = removeDemandChannelFromSlice(sinfo.demandChans[tp], subCh)
-			log.Printf("Remove Demand Channel node_id %v, chan %v", idt, tp)
-			close(subCh) // close subchannel!
+			removeSubscriber(sinfo.demandChans, sinfo.demandMap, sb) // remove map from idt
+			subLog.Infof("Remove Demand Channel node_id %v, chan %v", idt, tp)
+			sb.finish(nil) // close subscriber!
 		}
 	}
-	sinfo.smu.Unlock()
+	sinfo.dmu.Unlock()
+	sinfo.updateNodeChannels()
 }
 
 // Closing all channels related to provider ID.
@@ -606,7 +1042,7 @@ func mbusServerFunc(ch chan *api.MbusMsg, stream api.Synerex_SubscribeMbusServer
 			if sxutil.IDType(msg.GetSenderId()) != id { // do not send msg from myself
 				tgt := sxutil.IDType(msg.GetTargetId())
 				if tgt == 0 || tgt == id { // =0 broadcast , = tgt unicast
-					err := stream.Send(msg)
+					err := deliverMbusMsg(stream, msg, id)
 					if err != nil {
 						//				log.Printf("Error mBus Error %v", err)
 						return err
@@ -615,6 +1051,8 @@ func mbusServerFunc(ch chan *api.MbusMsg, stream api.Synerex_SubscribeMbusServer
 					mbusMessages.Inc(1)  // update mbus counter
 				}
 			}
+		case <-stream.Context().Done(): // client disconnected
+			return stream.Context().Err()
 		}
 	}
 }
@@ -625,126 +1063,218 @@ func removeMbusChannelFromSlice(sl []chan *api.MbusMsg, c chan *api.MbusMsg) []c
 			return append(sl[:i], sl[i+1:]...)
 		}
 	}
-	log.Printf("Cant find channel %v in removeMbusChannel", c)
+	mbusLog.Warnf("Cant find channel %v in removeMbusChannel", c)
 	return sl
 }
 func (s *synerexServerInfo) SubscribeMbus(mb *api.Mbus, stream api.Synerex_SubscribeMbusServer) error {
+	if s.isDraining() {
+		return errDraining
+	}
 
-	mbusCh := make(chan *api.MbusMsg, MessageChannelBufferSize) // make channel for each mbus
 	id := sxutil.IDType(mb.GetClientId())
 	mbid := mb.MbusId
 	s.mmu.Lock()
-	chans, cok := s.mbusChans[mbid] 
+	if err := s.checkMbusMember(mbid, id); err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject SubscribeMbus: %v", err)
+		return err
+	}
+	mi := s.mbusInfos[mbid]
+	if mi.status == api.MbusState_CLOSED {
+		s.mmu.Unlock()
+		return errMbusClosed(mbid)
+	}
+	// messages sent before subscription are delivered first
+	pending := mi.takePending(id)
+	mbusCh := make(chan *api.MbusMsg, MessageChannelBufferSize+len(pending)) // make channel for each mbus
+	for _, msg := range pending {
+		mbusCh <- msg
+	}
+	mi.addSubscriber(id)
+	chans, cok := s.mbusChans[mbid]
 	if cok == false {
-		log.Printf("new MbusChan for MbusID %d", mbid)
-	}else{
-		log.Printf("next MbusChan for MbusID %d, len(%d)", mbid, len(chans))
+		mbusLog.Debugf("new MbusChan for MbusID %d", mbid)
+	} else {
+		mbusLog.Debugf("next MbusChan for MbusID %d, len(%d)", mbid, len(chans))
 	}
 	s.mbusChans[mbid] = append(chans, mbusCh)
 	mm, ok := s.mbusMap[id]
-	if ok {
-		//		mm[mbid] = mbusCh
-	} else {
+	if !ok {
 		mm = make(map[uint64]chan *api.MbusMsg)
-		mm[mbid] = mbusCh
 		s.mbusMap[id] = mm
 	}
+	mm[mbid] = mbusCh
+	var jgm *api.GatewayMsg
+	if mi.subscribers[id] == 1 && !mi.remotes[id] && !mi.private { // private mbus is not relayed
+		jgm = mbusGatewayMsg(mi, id)
+	}
 	s.mmu.Unlock()
+	if jgm != nil && s.hasGateway() { // tell other servers
+		s.sendGatewayMsg(jgm)
+	}
 
 	err := mbusServerFunc(mbusCh, stream, id) // loop until close for each subscriber.
 
 	s.mmu.Lock()
 	s.mbusChans[mbid] = removeMbusChannelFromSlice(s.mbusChans[mbid], mbusCh)
-	delete(s.mbusMap, id)
+	if mm[mbid] == mbusCh {
+		delete(mm, mbid)
+	}
+	if len(mm) == 0 {
+		delete(s.mbusMap, id)
+	}
+	mi.removeSubscriber(id)
+	left := mi.subscribers[id] == 0 && mi.status != api.MbusState_CLOSED
 	//	log.Printf("Remove Mbus Stream Channel %v", ch)
 	s.mmu.Unlock()
+	if left && s.hasGateway() {
+		s.sendGatewayMsg(mbusEventGatewayMsg(mbid, api.MbusEvent_LEFT, id))
+	}
 
 	return err
 }
 
 // update name from synerex_api v0.4.1
 func (s *synerexServerInfo) SendMbusMsg(c context.Context, msg *api.MbusMsg) (r *api.Response, err error) {
-	// FIXME: wait until all subscriber is comming
-	count := 0 // loop counter.
-	for {
-		chans, ok := s.mbusChans[msg.GetMbusId()]
-		if ok && len(chans) >= 2 {
-			log.Printf("##### All subscriber comming!! [MbusID: %d]\n", msg.GetMbusId())
-			break
-		}
-		count++
-		if count > 10 {
-			log.Printf("##### Mbus Subscription timeout [MbusId: %d]\n", msg.GetMbusId())
-			break
-		}
-		log.Printf("##### Another Subscriber wating... [MbusId: %d, len(chans): %d]\n", msg.GetMbusId(), len(chans))
-		time.Sleep(1 * time.Second)
-	}
-	okFlag := true
-	okMsg := ""
 	s.mmu.RLock()
-	chs := s.mbusChans[msg.GetMbusId()] // get channel slice from mbus_id
-	for i := range chs {
-		ch := chs[i]
-		if len(ch) < MessageChannelBufferSize { // run under not blocking state.
-			ch <- msg
-		} else {
-			okMsg = fmt.Sprintf("MBus MessageDrop %v", msg)
-			okFlag = false
-			log.Printf(okMsg) // TODO: thisi is a critical log (message drop)
+	err = s.checkMbusMember(msg.GetMbusId(), sxutil.IDType(msg.GetSenderId()))
+	if err == nil {
+		if mi, ok := s.mbusInfos[msg.GetMbusId()]; ok && mi.status == api.MbusState_CLOSED {
+			err = errMbusClosed(msg.GetMbusId())
 		}
 	}
 	s.mmu.RUnlock()
+	if err != nil {
+		mbusLog.Warnf("Reject SendMbusMsg: %v", err)
+		return &api.Response{Ok: false, Err: err.Error()}, err
+	}
+	okFlag, okMsg := sendMbusMsg(s, msg, false)
 	r = &api.Response{Ok: okFlag, Err: okMsg}
 	return r, nil
 }
 
 func (s *synerexServerInfo) CloseMbus(c context.Context, mb *api.Mbus) (r *api.Response, err error) {
-	okFlag := true
-	okMsg := ""
-	s.mmu.RLock()
-	chs := s.mbusChans[mb.GetMbusId()] // get channel slice from mbus_id
-	cmsg := &api.MbusMsg{              // this is close message
-		MsgId: 0,
+	s.mmu.Lock()
+	if err = s.checkMbusMember(mb.GetMbusId(), sxutil.IDType(mb.GetClientId())); err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject CloseMbus: %v", err)
+		return &api.Response{Ok: false, Err: err.Error()}, err
 	}
-	for i := range chs {
-		ch := chs[i]
-		if len(ch) < MessageChannelBufferSize { // run under not blocking state.
-			ch <- cmsg
-		} else {
-			okMsg = fmt.Sprintf("MBusClose MessageDrop %v", cmsg)
-			okFlag = false
-			log.Printf(okMsg)
-		}
+	mi := s.mbusInfos[mb.GetMbusId()]
+	mi.close()
+	okFlag, okMsg := s.closeMbusChans(mb.GetMbusId())
+	s.mmu.Unlock()
+	if s.hasGateway() {
+		s.sendGatewayMsg(mbusEventGatewayMsg(mb.GetMbusId(), api.MbusEvent_CLOSED, sxutil.IDType(mb.GetClientId())))
 	}
-	s.mmu.RUnlock()
 	r = &api.Response{Ok: okFlag, Err: okMsg}
 	return r, nil
 }
 
 // from synerex_api v0.4.0
 func (s *synerexServerInfo) CreateMbus(c context.Context, mbo *api.MbusOpt) (mb *api.Mbus, err error) {
-	// just generate new unique ID
-	// TODO: private mbus is not implemented yet!
-	if mbo.MbusType == api.MbusOpt_PRIVATE {
-		log.Printf("Private MBUS is not yet implemented!")
+	// private mbus is owned by the creator
+	if mbo.MbusType == api.MbusOpt_PRIVATE && mbo.ClientId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "private mbus requires client_id of the owner")
 	}
 	mb = &api.Mbus{}
 	mb.ClientId = 0                    // client must set their own ID.
 	mb.MbusId = sxutil.GenerateIntID() // generate unique ID for new Mbus.
+	mi := newMbusInfo(mb.MbusId, mbo)
+	s.mmu.Lock()
+	s.mbusInfos[mb.MbusId] = mi
+	s.mmu.Unlock()
+	if mi.private {
+		mbusLog.Infof("Create private mbus %d owner %d members %d", mb.MbusId, mi.owner, len(mi.members))
+	}
 	return mb, nil
 }
 
+// AddMbusMembers allows clients to join the private mbus (only for owner)
+func (s *synerexServerInfo) AddMbusMembers(c context.Context, mm *api.MbusMembers) (r *api.Response, err error) {
+	s.mmu.Lock()
+	mi, err := s.checkMbusOwner(mm.GetMbusId(), sxutil.IDType(mm.GetClientId()))
+	if err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject AddMbusMembers: %v", err)
+		return &api.Response{Ok: false, Err: err.Error()}, err
+	}
+	for _, cid := range mm.GetMembers() {
+		mi.members[sxutil.IDType(cid)] = true
+	}
+	s.mmu.Unlock() // private mbus is not relayed to other servers
+	return &api.Response{Ok: true}, nil
+}
+
+// RemoveMbusMembers removes clients from the private mbus (only for owner)
+// Removed members which subscribe the mbus are closed.
+func (s *synerexServerInfo) RemoveMbusMembers(c context.Context, mm *api.MbusMembers) (r *api.Response, err error) {
+	s.mmu.Lock()
+	mi, err := s.checkMbusOwner(mm.GetMbusId(), sxutil.IDType(mm.GetClientId()))
+	if err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject RemoveMbusMembers: %v", err)
+		return &api.Response{Ok: false, Err: err.Error()}, err
+	}
+	for _, cid := range mm.GetMembers() {
+		id := sxutil.IDType(cid)
+		if id == mi.owner { // owner can't be removed
+			continue
+		}
+		delete(mi.members, id)
+		s.closeMbusSubscriber(mi.id, id)
+	}
+	s.mmu.Unlock() // private mbus is not relayed to other servers
+	return &api.Response{Ok: true}, nil
+}
+
 // from synerex_api v0.4.0
 func (s *synerexServerInfo) GetMbusState(c context.Context, mb *api.Mbus) (mbs *api.MbusState, err error) {
 	// return the status of Mbus.
-	// TODO: this method is not fully implemented yet!
-	mbs = &api.MbusState{
-		MbusId:      mb.MbusId,
-		Status:      api.MbusState_INVALID,
-		Subscribers: []uint64{},
+	return s.mbusState(mb.GetMbusId(), sxutil.IDType(mb.GetClientId())), nil
+}
+
+// WatchMbus streams member joined/left events of the mbus.
+// The first event is the current STATE, and CLOSED is the last event.
+func (s *synerexServerInfo) WatchMbus(mb *api.Mbus, stream api.Synerex_WatchMbusServer) error {
+	id := sxutil.IDType(mb.GetClientId())
+	mbid := mb.GetMbusId()
+	wch := make(chan *api.MbusEvent, mbusWatcherBufferSize)
+	s.mmu.Lock()
+	if err := s.checkMbusMember(mbid, id); err != nil {
+		s.mmu.Unlock()
+		mbusLog.Warnf("Reject WatchMbus: %v", err)
+		return err
+	}
+	mi := s.mbusInfos[mbid]
+	if mi.status == api.MbusState_CLOSED {
+		ev := mi.event(api.MbusEvent_CLOSED, 0)
+		s.mmu.Unlock()
+		return stream.Send(ev)
+	}
+	wch <- mi.event(api.MbusEvent_STATE, 0)
+	mi.watchers[wch] = true
+	s.mmu.Unlock()
+
+	defer func() {
+		s.mmu.Lock()
+		delete(mi.watchers, wch)
+		mi.lastActive = time.Now()
+		s.mmu.Unlock()
+	}()
+	for {
+		select {
+		case ev, ok := <-wch:
+			if !ok { // mbus is closed
+				return nil
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
 	}
-	return mbs, nil
 }
 
 func gatewayServerFunc(ch chan *api.GatewayMsg, ssgs api.Synerex_SubscribeGatewayServer) error {
@@ -755,38 +1285,44 @@ func gatewayServerFunc(ch chan *api.GatewayMsg, ssgs api.Synerex_SubscribeGatewa
 			if err != nil {
 				return err
 			}
+		case <-ssgs.Context().Done():
+			return ssgs.Context().Err()
 		}
 	}
 }
 
 // for Gateway subscribe
 func (s *synerexServerInfo) SubscribeGateway(gi *api.GatewayInfo, ssgs api.Synerex_SubscribeGatewayServer) error {
-	log.Printf("Subscribe Gateway %v\n", gi)
+	gatewayLog.Infof("Subscribe Gateway %v", gi)
 	idt := sxutil.IDType(gi.GetClientId())
-	//	tp := gi.GetChannels() // not using channels:
-	s.gmu.RLock()
-	_, ok := s.gatewayMap[idt]
-	s.gmu.RUnlock()
-	if ok { // check the availability of duplicated gateway client ID
+	gw := newGateway(gi) // only subscribed channels are sent
+	s.gmu.Lock()
+	if _, ok := s.gatewayMap[idt]; ok { // check the availability of duplicated gateway client ID
+		s.gmu.Unlock()
 		return errors.New(fmt.Sprintf("duplicated SubscribeGateway for ClientID %v", idt))
 	}
-
-	subCh := make(chan *api.GatewayMsg, MessageChannelBufferSize)
-
-	s.gmu.Lock()
-	s.gatewayMap[idt] = subCh // mapping from clientID to channel
+	s.gatewayMap[idt] = gw // mapping from clientID to gateway
 	s.gmu.Unlock()
-	err := gatewayServerFunc(subCh, ssgs)
+	err := gatewayServerFunc(gw.ch, ssgs)
 	// this supply stream may closed. so take care.
 	s.gmu.Lock()
 	delete(s.gatewayMap, idt) // remove map from idt
-	log.Printf("Remove Gateway Client %v", idt)
+	gatewayLog.Infof("Remove Gateway Client %v", idt)
 	s.gmu.Unlock()
 	return err
 }
 
 // for Gateway Forward
 func (s *synerexServerInfo) ForwardToGateway(ctx context.Context, gm *api.GatewayMsg) (*api.Response, error) {
+	if err := s.checkGatewayWriter(ctx, gm); err != nil {
+		gatewayLog.Warnf("Reject %v", err)
+		return &api.Response{Ok: false, Err: err.Error()}, err
+	}
+	// drop messages which already passed this server
+	if reason := s.checkGatewayLoop(gm); reason != "" {
+		gatewayLog.Debugf("Drop %s", reason)
+		return &api.Response{Ok: false, Err: reason}, nil
+	}
 	// need to extract each message and then send them..
 	// send demand for desired channels
 	okFlag := true
@@ -799,18 +1335,23 @@ func (s *synerexServerInfo) ForwardToGateway(ctx context.Context, gm *api.Gatewa
 	case api.MsgType_SUPPLY:
 		sp := gm.GetSupply()
 		okFlag, okMsg = sendSupply(s, sp, true)
-		/*
-			case api.MsgType_TARGET:
-				tg := gm.GetTarget()
-				okFlag, okMsg = sendTarget(s, tg)
-			case api.MsgType_MBUS:
-				mb := gm.GetMbus()
-				okFlag, okMsg = sendMbus(s,mb)
-			case api.MsgType_MBUSMSG:
-				mbm := gm.GetMbusMsg()
-				okFlag, okMsg = sendMbusMsg(s,mbm)
-
-		*/
+	case api.MsgType_TARGET:
+		tg := gm.GetTarget()
+		if gm.GetTargetType() == api.TargetType_CONFIRM {
+			okFlag, okMsg = s.confirmFromGateway(tg)
+		} else {
+			okFlag, okMsg = s.selectFromGateway(gm.GetTargetType(), tg)
+		}
+	case api.MsgType_MBUS:
+		okFlag, okMsg = s.mbusFromGateway(gm.GetMbus())
+	case api.MsgType_MBUSMSG:
+		okFlag, okMsg = s.mbusMsgFromGateway(gm.GetMbusMsg())
+	case api.MsgType_MBUSEVENT:
+		okFlag, okMsg = s.mbusEventFromGateway(gm.GetMbusEvent())
+	}
+	// relay to other servers (target is relayed only if it is not for this server)
+	if msgType != api.MsgType_TARGET || !okFlag {
+		s.relayGatewayMsg(gm)
 	}
 	r := &api.Response{Ok: okFlag, Err: okMsg}
 	return r, nil
@@ -819,15 +1360,20 @@ func (s *synerexServerInfo) ForwardToGateway(ctx context.Context, gm *api.Gatewa
 func newServerInfo() *synerexServerInfo {
 	var ms synerexServerInfo
 	s := &ms
-	for i := 0; i < pbase.ChannelTypeMax; i++ {
-		s.demandMap[i] = make(map[sxutil.IDType]chan *api.Demand)
-		s.supplyMap[i] = make(map[sxutil.IDType]chan *api.Supply)
-		s.waitConfirms[i] = make(map[sxutil.IDType]chan *api.Target)
-	}
+	// maps for each channel type are created lazily
+	s.demandChans = make(map[uint32][]*subscriber)
+	s.supplyChans = make(map[uint32][]*subscriber)
+	s.demandMap = make(map[uint32]map[sxutil.IDType]*subscriber)
+	s.supplyMap = make(map[uint32]map[sxutil.IDType]*subscriber)
+	s.waitConfirms = make(map[uint32]map[sxutil.IDType]chan *api.Target)
 	s.mbusChans = make(map[uint64][]chan *api.MbusMsg)
 	s.mbusMap = make(map[sxutil.IDType]map[uint64]chan *api.MbusMsg)
-	s.messageStore = CreateLocalMessageStore()
-	s.gatewayMap = make(map[sxutil.IDType]chan *api.GatewayMsg)
+	s.mbusInfos = make(map[uint64]*mbusInfo)
+	s.messageStore = CreateLocalMessageStore(1000, 0) // replaced by the store from flags
+	s.gatewayMap = make(map[sxutil.IDType]*gateway)
+	s.seenMsgs = newSeenCache(seenCacheSize)
+	s.demandLogs = newReplayLogs(*replaySize)
+	s.supplyLogs = newReplayLogs(*replaySize)
 
 	return s
 }
@@ -855,8 +1401,39 @@ func idToNode(id uint64) string {
 	return rs2 + ":" + strconv.Itoa(nodeNum)
 }
 
-func unaryServerInterceptor(logger *log.Logger, s *synerexServerInfo) grpc.UnaryServerInterceptor {
+func unaryServerInterceptor(s *synerexServerInfo) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, info, req)
+		defer span.End()
+		if *authKey != "" {
+			nodeID, aerr := authNodeID(ctx)
+			if aerr == nil {
+				aerr = checkRequestOwner(nodeID, req)
+			}
+			if aerr != nil {
+				unaryLog.Warnf("method %s, auth failed %v", path.Base(info.FullMethod), aerr)
+				span.SetError(aerr)
+				return nil, aerr
+			}
+			ctx = context.WithValue(ctx, authNodeKey{}, nodeID)
+		}
+		if s.isDraining() && drainRejectMethods[path.Base(info.FullMethod)] {
+			span.SetError(errDraining)
+			return nil, errDraining
+		}
+		if s.acl != nil {
+			if aerr := s.acl.check(ctx, path.Base(info.FullMethod), req); aerr != nil {
+				unaryLog.Warnf("method %s, %v", path.Base(info.FullMethod), aerr)
+				span.SetError(aerr)
+				return nil, aerr
+			}
+		}
+		if s.limiter != nil {
+			if resp := s.limiter.check(path.Base(info.FullMethod), req); resp != nil {
+				span.SetAttr("synerex.rate_limited", true)
+				return resp, nil
+			}
+		}
 		var err error
 		var args string
 		var msgType int
@@ -869,7 +1446,7 @@ func unaryServerInterceptor(logger *log.Logger, s *synerexServerInfo) grpc.Unary
 			msgType = int(dm.ChannelType)
 			srcId = dm.SenderId
 			tgtId = dm.TargetId
-			mid = dm.Id
+			mid = storeMsgID(dm.Id, dm.Cdata)
 			//			args = "Type:" + strconv.Itoa(int(dm.Type)) + ":" + strconv.FormatUint(dm.Id, 16) + ":" + idToNode(dm.SenderId) + "->" + strconv.FormatUint(dm.TargetId, 16)
 			args = idToNode(dm.SenderId) + "->" + idToNode(dm.TargetId)
 			// Supply
@@ -878,7 +1455,7 @@ func unaryServerInterceptor(logger *log.Logger, s *synerexServerInfo) grpc.Unary
 			msgType = int(sp.ChannelType)
 			srcId = sp.SenderId
 			tgtId = sp.TargetId
-			mid = sp.Id
+			mid = storeMsgID(sp.Id, sp.Cdata)
 			//			args = "Type:" + strconv.Itoa(int(sp.Type)) + ":" + strconv.FormatUint(sp.Id, 16) + ":" + idToNode(sp.SenderId) + "->" + strconv.FormatUint(sp.TargetId, 16)
 			args = idToNode(sp.SenderId) + "->" + idToNode(sp.TargetId)
 			// Target
@@ -918,21 +1495,13 @@ func unaryServerInterceptor(logger *log.Logger, s *synerexServerInfo) grpc.Unary
 			// Obtain method name from info
 			method := path.Base(info.FullMethod)
 			took := time.Since(begin)
+			promMetrics.observeLatency(method, took)
+			span.SetError(err)
 			if err != nil {
-				logger.Printf("method %s, took %#v, err %v", method, took, err)
+				unaryLog.Warnf("method %s, took %#v, err %v", method, took, err)
+			} else {
+				unaryLog.Debugf("method %s, took %v", method, took)
 			}
-			/*
-				fields := logrus.Fields{
-					"method": method,
-					"took":   took,
-				}
-				if err != nil {
-					fields["error"] = err
-					logger.WithFields(fields).Error("Failed")
-				} else {
-					//				logger.WithFields(fields).Info("Succeeded")
-				}
-			*/
 		}(time.Now())
 
 		// handler = RPC method
@@ -948,11 +1517,11 @@ func unaryServerInterceptor(logger *log.Logger, s *synerexServerInfo) grpc.Unary
 }
 
 // Stream Interceptor
-func streamServerInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+func streamServerInterceptor() grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		var err error
 		//		var args string
-		log.Printf("streamserver intercept...")
+		logger.Debugf("streamserver intercept...")
 		method := path.Base(info.FullMethod)
 		switch method {
 		case "SubscribeDemand":
@@ -965,20 +1534,9 @@ func streamServerInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
 			method := path.Base(info.FullMethod)
 			took := time.Since(begin)
 			if err != nil {
-				logger.Printf("method %s, took %#v, err %v", method, took, err)
+				logger.Warnf("method %s, took %#v, err %v", method, took, err)
 			}
 			//	logger.Printf("method %s, took %#v",method, took)
-			/*			fields := logrus.Fields{
-							"method": method,
-							"took":   took,
-						}
-						if err != nil {
-							fields["error"] = err
-							logger.WithFields(fields).Error("Failed")
-						} else {
-							logger.WithFields(fields).Info("Succeeded")
-						}
-			*/
 
 		}(time.Now())
 
@@ -986,12 +1544,13 @@ func streamServerInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
 		if hErr := handler(srv, stream); err != nil {
 			err = hErr
 		}
-		log.Printf("streamserver intercept..end .")
+		logger.Debugf("streamserver intercept..end .")
 		return err
 	}
 }
 
 func prepareGrpcServer(ssi *synerexServerInfo, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.CustomCodec(fanoutCodec{})}, opts...) // encode-once fan-out
 	gcServer := grpc.NewServer(opts...)
 	api.RegisterSynerexServer(gcServer, ssi)
 	return gcServer
@@ -1000,18 +1559,18 @@ func prepareGrpcServer(ssi *synerexServerInfo, opts ...grpc.ServerOption) *grpc.
 func keepAliveFunc(cmd nodeapi.KeepAliveCommand, str string) {
 	//	log.Printf("KeepAlive func %v %v ", cmd, str)
 	if cmd == nodeapi.KeepAliveCommand_PROVIDER_DISCONNECT { // we need to purge
-		log.Printf("Clear Channel command from NodeServ %s", str)
+		logger.Infof("Clear Channel command from NodeServ %s", str)
 
 		var killNodes []int32
 		err := json.Unmarshal([]byte(str), &killNodes)
 		if err == nil {
 			showAllSubscribers()
 			for i := range killNodes {
-				log.Printf("Closing node %d", killNodes[i])
+				logger.Infof("Closing node %d", killNodes[i])
 				closeAllChannels(killNodes[i])
 			}
 		} else {
-			log.Printf("Unmarshal Err %#v", err)
+			logger.Errorf("Unmarshal Err %#v", err)
 		}
 
 	}
@@ -1020,9 +1579,16 @@ func keepAliveFunc(cmd nodeapi.KeepAliveCommand, str string) {
 
 func main() {
 	flag.Parse()
-	log.Printf("SynerexServer(%s) built %s sha1 %s", sxutil.GitVer, sxutil.BuildTime, sxutil.Sha1Ver)
-	go sxutil.HandleSigInt()
+	setupLogging()
+	logger.Infof("SynerexServer(%s) built %s sha1 %s", sxutil.GitVer, sxutil.BuildTime, sxutil.Sha1Ver)
+	sxutil.SetTLSOption(tlsOption) // for nodeserv connection
+	go handleDrainSignal(*drainTime)
 	sxutil.RegisterDeferFunction(sxutil.UnRegisterNode)
+	if *traceExport != "" { // before RegisterNode (SX_TRACE is for providers)
+		if err := sxutil.StartTracing(*name, *traceExport); err != nil {
+			logger.Fatalf("failed to start tracing: %v", err)
+		}
+	}
 
 	srvaddr := fmt.Sprintf("%s:%d", *servaddr, *port)
 	//	fmt.Printf("ServerInfo %s\n", srvaddr)
@@ -1033,7 +1599,7 @@ func main() {
 		AreaId:     "Default",
 	}
 
-	channels := []uint32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11} // current basic types+alpha
+	channels := []uint32{} // channel types in use are updated by keepalive
 
 	//	_, rerr := sxutil.RegisterNodeWithCmd(*nodesrv, *name, channels, sxo, keepAliveFunc)
 	//	//	monitorapi.InitMonitor(*monitor)
@@ -1043,10 +1609,10 @@ func main() {
 	for {
 		_, rerr := sxutil.RegisterNodeWithCmd(fmt.Sprintf("%s:%d", *nodeaddr, *nodeport), *name, channels, sxo, keepAliveFunc)
 		if rerr != nil {
-			log.Println("Can't register synerex server, reconnect now...")
+			logger.Warnf("Can't register synerex server, reconnect now...")
 			time.Sleep(1 * time.Second)
 		} else {
-			log.Println("Register synerex server")
+			logger.Infof("Register synerex server")
 			break
 		}
 	}
@@ -1055,21 +1621,71 @@ func main() {
 	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", *port))
 
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		logger.Fatalf("failed to listen: %v", err)
 	}
 
-	var opts []grpc.ServerOption
+	opts, err := tlsOption.ServerOptions()
+	if err != nil {
+		logger.Fatalf("failed to load TLS certificates: %v", err)
+	}
 
 	s := newServerInfo()
 	sinfo = s
-	opts = append(opts, grpc.UnaryInterceptor(unaryServerInterceptor(log.New(os.Stdout, "[Unary]", log.LstdFlags|log.LUTC), s)))
+	s.policies, err = parseBackpressure(*bpolicy)
+	if err != nil {
+		logger.Fatalf("failed to parse backpressure policy: %v", err)
+	}
+	s.messageStore, err = NewMessageStore(*storeType, *storePath, *storeLimit, *storeRetain)
+	if err != nil {
+		logger.Fatalf("failed to open message store: %v", err)
+	}
+	sxutil.RegisterDeferFunction(func() { s.messageStore.Close() })
+	if *mbusTimeout > 0 {
+		go s.mbusGCLoop(*mbusTimeout)
+	}
+	if *metricsAddr != "" {
+		go s.startMetricsServer(*metricsAddr)
+	}
+
+	if *adminAddr != "" {
+		go s.startAdminServer(*adminAddr, tlsOption.ClientAuth, opts...) // only with TLS options
+	}
+	if *aclFile != "" {
+		if *authKey == "" { // client ids in requests can be spoofed without node tokens
+			logger.Fatalf("ACL policy %s requires -authkey for node authentication", *aclFile)
+		}
+		s.acl, err = newACLStore(*aclFile)
+		if err != nil {
+			logger.Fatalf("failed to load ACL policy: %v", err)
+		}
+		if *aclReload > 0 {
+			go s.acl.reloadLoop(*aclReload)
+		}
+	}
+	s.limiter, err = newRateLimiter(*senderLimit, *chanLimit)
+	if err != nil {
+		logger.Fatalf("failed to parse rate limits: %v", err)
+	}
+	opts = append(opts, grpc.MaxRecvMsgSize(*maxMsgSize), grpc.MaxSendMsgSize(*maxMsgSize))
+	opts = append(opts, grpc.UnaryInterceptor(unaryServerInterceptor(s)))
 
 	// for more precise monitoring , we do not use StreamIntercepter.
-	//	opts = append(opts, grpc.StreamInterceptor(streamServerInterceptor(logger)))
+	//	opts = append(opts, grpc.StreamInterceptor(streamServerInterceptor()))
+	var streamInterceptors []grpc.StreamServerInterceptor // only for checking requests
+	if *authKey != "" {
+		streamInterceptors = append(streamInterceptors, authStreamInterceptor())
+		logger.Infof("Node token authentication enabled")
+	}
+	if s.acl != nil {
+		streamInterceptors = append(streamInterceptors, aclStreamInterceptor(s.acl))
+	}
+	if len(streamInterceptors) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors...))
+	}
 
 	grpcServer := prepareGrpcServer(s, opts...)
-	log.Printf("Start Synerex Server, connection waiting at port :%d ...", *port)
+	logger.Infof("Start Synerex Server, connection waiting at port :%d ...", *port)
 	serr := grpcServer.Serve(lis)
-	log.Printf("Should not arrive here.. server closed. %v", serr)
+	logger.Errorf("Should not arrive here.. server closed. %v", serr)
 
 }
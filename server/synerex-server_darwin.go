@@ -1,7 +1,6 @@
 package main
 
 import (
-	"log"
 	"time"
 
 	metrics "github.com/rcrowley/go-metrics"
@@ -30,7 +29,7 @@ func periodicLog(r metrics.Registry, freq time.Duration) {
 				}
 			}
 		})
-		log.Printf("metric total: %7d :send: %7d :recv: %7d", total, send, receive)
+		logger.Component("metrics").Infof("metric total: %7d :send: %7d :recv: %7d", total, send, receive)
 	}
 }
 
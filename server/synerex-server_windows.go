@@ -2,7 +2,6 @@ package main
 
 import (
 	"time"
-	"log"
 	"github.com/rcrowley/go-metrics"
 )
 
@@ -24,7 +23,7 @@ func periodicLog(r metrics.Registry, freq time.Duration) {
 				}
 			}
 		})
-		log.Printf("metric total: %7d :send: %7d :recv: %7d", total, send, receive)
+		logger.Component("metrics").Infof("metric total: %7d :send: %7d :recv: %7d", total, send, receive)
 	}
 }
 
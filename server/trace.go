@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+	sxutil "github.com/synerex/synerex_sxutil"
+	"google.golang.org/grpc"
+)
+
+// Distributed tracing of the server.
+// Each unary RPC has a server span (parent is traceparent in gRPC metadata), and the span is set to
+// trace_parent of the messages, so that queueing and delivery spans of each subscriber,
+// gateways and receivers can continue the trace.
+
+// queuedMsg is a message in subscriber queue with enqueued time
+type queuedMsg struct {
+	em *encodedMsg
+	ts time.Time
+}
+
+// setTraceParent sets trace_parent of the message (inner message for GatewayMsg)
+func setTraceParent(msg interface{}, tp string) {
+	switch m := msg.(type) {
+	case *api.Demand:
+		m.TraceParent = tp
+	case *api.Supply:
+		m.TraceParent = tp
+	case *api.Target:
+		m.TraceParent = tp
+	case *api.MbusMsg:
+		m.TraceParent = tp
+	case *api.GatewayMsg:
+		switch gm := m.GetMsgOneof().(type) {
+		case *api.GatewayMsg_Demand:
+			setTraceParent(gm.Demand, tp)
+		case *api.GatewayMsg_Supply:
+			setTraceParent(gm.Supply, tp)
+		case *api.GatewayMsg_Target:
+			setTraceParent(gm.Target, tp)
+		case *api.GatewayMsg_MbusMsg:
+			setTraceParent(gm.MbusMsg, tp)
+		}
+	}
+}
+
+// getTraceParent returns trace_parent of the message (inner message for GatewayMsg)
+func getTraceParent(msg proto.Message) string {
+	switch m := msg.(type) {
+	case *api.Demand:
+		return m.GetTraceParent()
+	case *api.Supply:
+		return m.GetTraceParent()
+	case *api.Target:
+		return m.GetTraceParent()
+	case *api.MbusMsg:
+		return m.GetTraceParent()
+	case *api.GatewayMsg:
+		switch gm := m.GetMsgOneof().(type) {
+		case *api.GatewayMsg_Demand:
+			return getTraceParent(gm.Demand)
+		case *api.GatewayMsg_Supply:
+			return getTraceParent(gm.Supply)
+		case *api.GatewayMsg_Target:
+			return getTraceParent(gm.Target)
+		case *api.GatewayMsg_MbusMsg:
+			return getTraceParent(gm.MbusMsg)
+		}
+	}
+	return ""
+}
+
+// startServerSpan starts span of unary RPC and sets it to the request message
+func startServerSpan(ctx context.Context, info *grpc.UnaryServerInfo, req interface{}) (context.Context, *sxutil.Span) {
+	if !sxutil.TracingEnabled() {
+		return ctx, nil
+	}
+	ctx, span := sxutil.StartSpan(sxutil.TraceParentFromIncoming(ctx), info.FullMethod, sxutil.SpanKindServer)
+	span.SetAttr("rpc.system", "grpc")
+	span.SetAttr("rpc.method", info.FullMethod)
+	if _, ctype, id := aclRequest("", req); id != 0 {
+		span.SetAttr("synerex.sender_id", id)
+		span.SetAttr("synerex.node_id", idNodeID(id))
+		if ctype != 0 {
+			span.SetAttr("synerex.channel_type", ctype)
+		}
+	}
+	setTraceParent(req, span.TraceParent())
+	return ctx, span
+}
+
+// startPublishSpan starts span of the message received on Publish stream.
+// The parent is trace_parent of the message, since the stream has no metadata for each message.
+func startPublishSpan(method string, msg proto.Message) *sxutil.Span {
+	if !sxutil.TracingEnabled() {
+		return nil
+	}
+	_, span := sxutil.StartSpan(sxutil.ContextWithTraceParent(context.Background(), getTraceParent(msg)), method, sxutil.SpanKindServer)
+	span.SetAttr("rpc.system", "grpc")
+	span.SetAttr("rpc.method", method)
+	if _, ctype, id := aclRequest("", msg); id != 0 {
+		span.SetAttr("synerex.sender_id", id)
+		span.SetAttr("synerex.node_id", idNodeID(id))
+		span.SetAttr("synerex.channel_type", ctype)
+	}
+	setTraceParent(msg, span.TraceParent())
+	return span
+}
+
+// deliver sends queued message with queueing and delivery spans
+func (sb *subscriber) deliver(stream grpc.ServerStream, qm queuedMsg, kind string) error {
+	tp := getTraceParent(qm.em.msg)
+	if tp == "" || !sxutil.TracingEnabled() {
+		return stream.SendMsg(qm.em)
+	}
+	ctx := sxutil.ContextWithTraceParent(context.Background(), tp)
+	_, qspan := sxutil.StartSpanAt(ctx, "synerex.queue", sxutil.SpanKindInternal, qm.ts)
+	qspan.SetAttr("synerex.kind", kind)
+	qspan.SetAttr("synerex.client_id", uint64(sb.clientID))
+	qspan.SetAttr("synerex.queue_len", len(sb.ch))
+	qspan.End()
+	_, dspan := sxutil.StartSpan(ctx, "synerex.deliver", sxutil.SpanKindProducer)
+	dspan.SetAttr("synerex.kind", kind)
+	dspan.SetAttr("synerex.client_id", uint64(sb.clientID))
+	dspan.SetAttr("synerex.channel_type", sb.chType)
+	err := stream.SendMsg(qm.em)
+	dspan.SetError(err)
+	dspan.End()
+	return err
+}
+
+// deliverMbusMsg sends mbus message with delivery span
+func deliverMbusMsg(stream api.Synerex_SubscribeMbusServer, msg *api.MbusMsg, id sxutil.IDType) error {
+	if msg.GetTraceParent() == "" || !sxutil.TracingEnabled() {
+		return stream.Send(msg)
+	}
+	_, dspan := sxutil.StartSpan(sxutil.ContextWithTraceParent(context.Background(), msg.GetTraceParent()), "synerex.deliver", sxutil.SpanKindProducer)
+	dspan.SetAttr("synerex.kind", "mbus")
+	dspan.SetAttr("synerex.client_id", uint64(id))
+	dspan.SetAttr("synerex.mbus_id", msg.GetMbusId())
+	err := stream.Send(msg)
+	dspan.SetError(err)
+	dspan.End()
+	return err
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/synerex/synerex_api"
+)
+
+func TestTraceParent(t *testing.T) {
+	const tp = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	for _, msg := range []proto.Message{
+		&api.Demand{},
+		&api.Supply{},
+		&api.Target{},
+		&api.MbusMsg{},
+		&api.GatewayMsg{MsgOneof: &api.GatewayMsg_Demand{Demand: &api.Demand{}}},
+		&api.GatewayMsg{MsgOneof: &api.GatewayMsg_Supply{Supply: &api.Supply{}}},
+		&api.GatewayMsg{MsgOneof: &api.GatewayMsg_Target{Target: &api.Target{}}},
+		&api.GatewayMsg{MsgOneof: &api.GatewayMsg_MbusMsg{MbusMsg: &api.MbusMsg{}}},
+	} {
+		if got := getTraceParent(msg); got != "" {
+			t.Errorf("%v: initial trace parent %q", msg, got)
+		}
+		setTraceParent(msg, tp)
+		if got := getTraceParent(msg); got != tp {
+			t.Errorf("%T %v: trace parent %q", msg, msg, got)
+		}
+	}
+	if got := getTraceParent(&api.Channel{}); got != "" {
+		t.Errorf("trace parent of channel %q", got)
+	}
+	setTraceParent(&api.GatewayMsg{}, tp) // no inner message
+}
@@ -0,0 +1,42 @@
+package sxutil
+
+import (
+	"context"
+
+	nodeapi "github.com/synerex/synerex_nodeapi"
+)
+
+// Node token obtained from node server is attached to each Synerex API call as gRPC metadata.
+
+func (ni *NodeServInfo) setToken(token string) {
+	if token == "" { // auth is disabled or not refreshed
+		return
+	}
+	ni.tkmu.Lock()
+	ni.token = token
+	ni.tkmu.Unlock()
+}
+
+// GetNodeToken returns current node token (empty if auth is disabled)
+func (ni *NodeServInfo) GetNodeToken() string {
+	ni.tkmu.RLock()
+	defer ni.tkmu.RUnlock()
+	return ni.token
+}
+
+// nodeTokenCreds implements credentials.PerRPCCredentials for node token
+type nodeTokenCreds struct {
+	ni *NodeServInfo
+}
+
+func (tc *nodeTokenCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token := tc.ni.GetNodeToken()
+	if token == "" {
+		return nil, nil
+	}
+	return map[string]string{nodeapi.TokenMetadataKey: token}, nil
+}
+
+func (tc *nodeTokenCreds) RequireTransportSecurity() bool {
+	return false // token can be used without TLS (but TLS is recommended)
+}
@@ -0,0 +1,203 @@
+package sxutil
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+)
+
+// Chunked transfer of large Content.
+// Cdata larger than ChunkSize is split into numbered chunks by NotifyDemand/NotifySupply,
+// ProposeDemand/ProposeSupply, SendMbusMsg (and Publisher).
+// Each chunk is a message with its own id and the same name/arg_json, so that server side filters
+// treat all chunks alike. The server relays chunks of a sender in order, and the receiver reassembles
+// them before the callback (a missing chunk discards the message, a duplicated chunk is ignored).
+// Subscriptions resume after the last complete message, and chunks received before are kept.
+
+// MaxMsgSize is the max size of gRPC messages sent and received by the client (SX_MAX_MSG_SIZE)
+var MaxMsgSize = envInt("SX_MAX_MSG_SIZE", 4*1024*1024)
+
+// ChunkSize is the max size of entity in a message, larger Cdata is split (SX_CHUNK_SIZE, 0 for no split)
+var ChunkSize = envInt("SX_CHUNK_SIZE", 1024*1024)
+
+// ChunkTimeout is the max time for receiving all chunks of a message
+var ChunkTimeout = 30 * time.Second
+
+// chunkHeaderSize is the room for fields of a chunk message other than the entity (name, arg_json, ids...)
+const chunkHeaderSize = 64 * 1024
+
+func init() {
+	if err := checkChunkSize(ChunkSize, MaxMsgSize); err != nil && ChunkSize > 0 {
+		logger.Errorf("%v, use %d", err, MaxMsgSize-chunkHeaderSize)
+		ChunkSize = MaxMsgSize - chunkHeaderSize
+	}
+}
+
+// checkChunkSize returns error if chunks don't fit in messages of max size
+func checkChunkSize(chunkSize, maxMsgSize int) error {
+	if chunkSize+chunkHeaderSize > maxMsgSize {
+		return fmt.Errorf("SX_CHUNK_SIZE %d (+ header %d) exceeds SX_MAX_MSG_SIZE %d", chunkSize, chunkHeaderSize, maxMsgSize)
+	}
+	return nil
+}
+
+func envInt(key string, def int) int {
+	if env := os.Getenv(key); env != "" {
+		if n, err := strconv.Atoi(env); err == nil {
+			return n
+		}
+		logger.Warnf("Invalid %s: %q", key, env)
+	}
+	return def
+}
+
+// forEachChunk calls send for each chunk of cdata (or once with cdata if not split).
+// Chunks have new ids, and the original id is in the chunk info.
+func forEachChunk(id uint64, cdata *api.Content, send func(mid uint64, cdata *api.Content) error) error {
+	size := len(cdata.GetEntity())
+	if ChunkSize <= 0 || size <= ChunkSize {
+		return send(id, cdata)
+	}
+	total := (size + ChunkSize - 1) / ChunkSize
+	for i := 0; i < total; i++ {
+		end := (i + 1) * ChunkSize
+		if end > size {
+			end = size
+		}
+		part := &api.Content{
+			Entity: cdata.Entity[i*ChunkSize : end],
+			Chunk: &api.Chunk{
+				MsgId: id,
+				Index: uint32(i),
+				Total: uint32(total),
+				Size:  uint64(size),
+			},
+		}
+		if err := send(GenerateIntID(), part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type chunkBuf struct {
+	entity []byte
+	next   uint32 // next chunk index
+	start  time.Time
+}
+
+// chunkAssembler reassembles chunks received by the client
+type chunkAssembler struct {
+	bufs map[uint64]*chunkBuf // original message id -> received chunks
+	mu   sync.Mutex
+}
+
+// add adds the chunk, and returns reassembled content if all chunks are received
+func (ca *chunkAssembler) add(cdata *api.Content) *api.Content {
+	ck := cdata.GetChunk()
+	now := time.Now()
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if ca.bufs == nil {
+		ca.bufs = make(map[uint64]*chunkBuf)
+	}
+	for id, buf := range ca.bufs { // remove expired messages
+		if now.Sub(buf.start) > ChunkTimeout {
+			logger.Warnf("Chunks of message %d are expired (%d chunks received)", id, buf.next)
+			delete(ca.bufs, id)
+		}
+	}
+	buf, ok := ca.bufs[ck.GetMsgId()]
+	if !ok {
+		if ck.GetIndex() != 0 { // the first chunk is missing
+			logger.Warnf("Discard chunk %d of message %d", ck.GetIndex(), ck.GetMsgId())
+			return nil
+		}
+		buf = &chunkBuf{entity: make([]byte, 0, len(cdata.GetEntity())), start: now}
+		ca.bufs[ck.GetMsgId()] = buf
+	}
+	if ck.GetIndex() < buf.next { // replayed on resuming subscription
+		logger.Debugf("Ignore duplicated chunk %d of message %d", ck.GetIndex(), ck.GetMsgId())
+		return nil
+	}
+	if ck.GetIndex() != buf.next { // chunks are relayed in order, so a chunk is missing
+		logger.Warnf("Discard message %d: chunk %d received, but %d expected", ck.GetMsgId(), ck.GetIndex(), buf.next)
+		delete(ca.bufs, ck.GetMsgId())
+		return nil
+	}
+	buf.entity = append(buf.entity, cdata.GetEntity()...)
+	buf.next++
+	if buf.next < ck.GetTotal() {
+		return nil
+	}
+	delete(ca.bufs, ck.GetMsgId())
+	if uint64(len(buf.entity)) != ck.GetSize() {
+		logger.Warnf("Discard message %d: size %d, but %d expected", ck.GetMsgId(), len(buf.entity), ck.GetSize())
+		return nil
+	}
+	return &api.Content{Entity: buf.entity}
+}
+
+// assembleSupply returns reassembled supply (nil if waiting other chunks)
+func (clt *SXServiceClient) assembleSupply(sp *api.Supply) *api.Supply {
+	if sp.GetCdata().GetChunk() == nil {
+		return sp
+	}
+	id := sp.Cdata.Chunk.MsgId
+	if sp.Cdata = clt.chunks.add(sp.Cdata); sp.Cdata == nil {
+		return nil
+	}
+	sp.Id = id
+	return sp
+}
+
+// assembleMbusMsg returns reassembled mbus message (nil if waiting other chunks)
+func (clt *SXServiceClient) assembleMbusMsg(msg *api.MbusMsg) *api.MbusMsg {
+	if msg.GetCdata().GetChunk() == nil {
+		return msg
+	}
+	id := msg.Cdata.Chunk.MsgId
+	if msg.Cdata = clt.chunks.add(msg.Cdata); msg.Cdata == nil {
+		return nil
+	}
+	msg.MsgId = id
+	return msg
+}
+
+// assembleDemand returns reassembled demand (nil if waiting other chunks)
+func (clt *SXServiceClient) assembleDemand(dm *api.Demand) *api.Demand {
+	if dm.GetCdata().GetChunk() == nil {
+		return dm
+	}
+	id := dm.Cdata.Chunk.MsgId
+	if dm.Cdata = clt.chunks.add(dm.Cdata); dm.Cdata == nil {
+		return nil
+	}
+	dm.Id = id
+	return dm
+}
+
+// receiveSupply reassembles sp, and advances the resume sequence only after a complete supply
+// (nil if waiting other chunks)
+func (clt *SXServiceClient) receiveSupply(sp *api.Supply) *api.Supply {
+	seq := sp.Seq
+	if sp = clt.assembleSupply(sp); sp != nil && seq != 0 { // select message has no sequence number
+		atomic.StoreUint64(&clt.supplySeq, seq)
+	}
+	return sp
+}
+
+// receiveDemand reassembles dm, and advances the resume sequence only after a complete demand
+// (nil if waiting other chunks)
+func (clt *SXServiceClient) receiveDemand(dm *api.Demand) *api.Demand {
+	seq := dm.Seq
+	if dm = clt.assembleDemand(dm); dm != nil && seq != 0 { // select message has no sequence number
+		atomic.StoreUint64(&clt.demandSeq, seq)
+	}
+	return dm
+}
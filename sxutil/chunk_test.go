@@ -0,0 +1,221 @@
+package sxutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+)
+
+func init() {
+	InitNodeNum(1) // for GenerateIntID
+}
+
+// splitEntity splits entity with chunk size n
+func splitEntity(t *testing.T, id uint64, entity []byte, n int) []*api.Content {
+	t.Helper()
+	orig := ChunkSize
+	ChunkSize = n
+	defer func() { ChunkSize = orig }()
+	var chunks []*api.Content
+	err := forEachChunk(id, &api.Content{Entity: entity}, func(mid uint64, cdata *api.Content) error {
+		if cdata.GetChunk() != nil && mid == id {
+			t.Errorf("chunk %d has the original id", cdata.GetChunk().GetIndex())
+		}
+		chunks = append(chunks, cdata)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chunks
+}
+
+func TestForEachChunk(t *testing.T) {
+	entity := []byte("0123456789abcdefghijklmno") // 25 bytes
+	chunks := splitEntity(t, 100, entity, 10)
+	if len(chunks) != 3 {
+		t.Fatalf("%d chunks", len(chunks))
+	}
+	var joined []byte
+	for i, c := range chunks {
+		ck := c.GetChunk()
+		if ck.GetMsgId() != 100 || ck.GetIndex() != uint32(i) || ck.GetTotal() != 3 || ck.GetSize() != 25 {
+			t.Errorf("chunk %d: %v", i, ck)
+		}
+		joined = append(joined, c.GetEntity()...)
+	}
+	if !bytes.Equal(joined, entity) {
+		t.Errorf("joined %q", joined)
+	}
+	// not split
+	if chunks := splitEntity(t, 100, entity, 25); len(chunks) != 1 || chunks[0].GetChunk() != nil {
+		t.Errorf("entity of chunk size is split: %v", chunks)
+	}
+	if chunks := splitEntity(t, 100, entity, 0); len(chunks) != 1 || chunks[0].GetChunk() != nil {
+		t.Errorf("entity is split without chunk size: %v", chunks)
+	}
+	// error stops sending
+	n := 0
+	orig := ChunkSize
+	ChunkSize = 10
+	defer func() { ChunkSize = orig }()
+	err := forEachChunk(1, &api.Content{Entity: entity}, func(uint64, *api.Content) error {
+		n++
+		return errors.New("send error")
+	})
+	if err == nil || n != 1 {
+		t.Errorf("error %v after %d chunks", err, n)
+	}
+}
+
+func TestChunkAssemblerOrder(t *testing.T) {
+	entity := []byte("0123456789abcdefghijklmno")
+	var ca chunkAssembler
+	a := splitEntity(t, 1, entity, 10)
+	b := splitEntity(t, 2, []byte("ABCDEFGHIJKL"), 10)
+	// chunks of messages from different senders are interleaved
+	for _, c := range []*api.Content{a[0], b[0], a[1]} {
+		if ca.add(c) != nil {
+			t.Fatal("incomplete message is returned")
+		}
+	}
+	if got := ca.add(b[1]); got == nil || string(got.GetEntity()) != "ABCDEFGHIJKL" || got.GetChunk() != nil {
+		t.Errorf("message 2: %v", got)
+	}
+	if got := ca.add(a[2]); got == nil || !bytes.Equal(got.GetEntity(), entity) {
+		t.Errorf("message 1: %v", got)
+	}
+	if len(ca.bufs) != 0 {
+		t.Errorf("%d buffers are left", len(ca.bufs))
+	}
+}
+
+func TestChunkAssemblerMissing(t *testing.T) {
+	chunks := splitEntity(t, 1, []byte("0123456789abcdefghijklmno"), 10)
+	var ca chunkAssembler
+	// missing middle chunk
+	ca.add(chunks[0])
+	if ca.add(chunks[2]) != nil {
+		t.Error("message without chunk 1 is returned")
+	}
+	if len(ca.bufs) != 0 {
+		t.Error("incomplete message is kept")
+	}
+	// missing first chunk
+	if ca.add(chunks[1]) != nil || ca.add(chunks[2]) != nil || len(ca.bufs) != 0 {
+		t.Error("message without chunk 0 is kept")
+	}
+	// duplicated chunks (replayed on resume) are ignored
+	ca.add(chunks[0])
+	if ca.add(chunks[0]) != nil || len(ca.bufs) != 1 {
+		t.Error("duplicated chunk discards the message")
+	}
+	ca.add(chunks[1])
+	ca.add(chunks[0])
+	if got := ca.add(chunks[2]); got == nil || string(got.GetEntity()) != "0123456789abcdefghijklmno" {
+		t.Errorf("message with duplicated chunks: %v", got)
+	}
+}
+
+func TestChunkAssemblerExpire(t *testing.T) {
+	chunks := splitEntity(t, 1, []byte("0123456789abcdefghijklmno"), 10)
+	orig := ChunkTimeout
+	ChunkTimeout = 10 * time.Millisecond
+	defer func() { ChunkTimeout = orig }()
+	var ca chunkAssembler
+	ca.add(chunks[0])
+	ca.add(chunks[1])
+	time.Sleep(20 * time.Millisecond)
+	if ca.add(chunks[2]) != nil {
+		t.Error("expired message is returned")
+	}
+	if len(ca.bufs) != 0 {
+		t.Error("expired message is kept")
+	}
+}
+
+func TestChunkAssemblerSizeMismatch(t *testing.T) {
+	chunks := splitEntity(t, 1, []byte("0123456789abcdefghijklmno"), 10)
+	for _, c := range chunks {
+		c.Chunk.Size = 30
+	}
+	var ca chunkAssembler
+	for _, c := range chunks {
+		if ca.add(c) != nil {
+			t.Error("message of wrong size is returned")
+		}
+	}
+	if len(ca.bufs) != 0 {
+		t.Error("message of wrong size is kept")
+	}
+}
+
+func TestAssembleMessages(t *testing.T) {
+	clt := &SXServiceClient{}
+	entity := []byte("0123456789abcdefghijklmno")
+	var sp *api.Supply
+	for i, c := range splitEntity(t, 10, entity, 10) {
+		sp = clt.assembleSupply(&api.Supply{Id: uint64(100 + i), SupplyName: "geo", Cdata: c})
+	}
+	if sp == nil || sp.GetId() != 10 || !bytes.Equal(sp.GetCdata().GetEntity(), entity) {
+		t.Errorf("supply %v", sp)
+	}
+	var msg *api.MbusMsg
+	for i, c := range splitEntity(t, 20, entity, 10) {
+		msg = clt.assembleMbusMsg(&api.MbusMsg{MsgId: uint64(200 + i), Cdata: c})
+	}
+	if msg == nil || msg.GetMsgId() != 20 || !bytes.Equal(msg.GetCdata().GetEntity(), entity) {
+		t.Errorf("mbus message %v", msg)
+	}
+	// not chunked
+	dm := &api.Demand{Id: 30, Cdata: &api.Content{Entity: entity}}
+	if clt.assembleDemand(dm) != dm {
+		t.Error("demand without chunk")
+	}
+}
+
+func TestReceiveSupplyResumeSeq(t *testing.T) {
+	clt := &SXServiceClient{}
+	a := splitEntity(t, 1, []byte("0123456789abcdefghijklmno"), 10)
+	b := splitEntity(t, 2, []byte("ABCDEFGHIJKL"), 10)
+	recv := func(seq uint64, c *api.Content) *api.Supply {
+		return clt.receiveSupply(&api.Supply{Id: GenerateIntID(), Seq: seq, Cdata: c})
+	}
+	recv(10, a[0])
+	recv(11, a[1])
+	if clt.supplySeq != 0 {
+		t.Fatalf("resume sequence %d is advanced by incomplete message", clt.supplySeq)
+	}
+	// stream is broken, and resumed from the start of message 1 with chunks of message 2
+	recv(10, a[0])
+	recv(11, a[1])
+	recv(12, b[0])
+	if sp := recv(13, a[2]); sp == nil || sp.GetId() != 1 || clt.supplySeq != 13 {
+		t.Fatalf("message 1: %v, resume sequence %d", sp, clt.supplySeq)
+	}
+	// resumed after message 1, chunk of message 2 is kept
+	if sp := recv(14, b[1]); sp == nil || sp.GetId() != 2 || clt.supplySeq != 14 {
+		t.Fatalf("message 2: %v, resume sequence %d", sp, clt.supplySeq)
+	}
+	if sp := recv(0, &api.Content{Entity: []byte("select")}); sp == nil || clt.supplySeq != 14 {
+		t.Errorf("select supply: %v, resume sequence %d", sp, clt.supplySeq)
+	}
+	if dm := clt.receiveDemand(&api.Demand{Id: 3, Seq: 20}); dm == nil || clt.demandSeq != 20 || clt.supplySeq != 14 {
+		t.Errorf("demand: %v, resume sequence %d/%d", dm, clt.demandSeq, clt.supplySeq)
+	}
+}
+
+func TestCheckChunkSize(t *testing.T) {
+	if err := checkChunkSize(1024*1024, 4*1024*1024); err != nil {
+		t.Error(err)
+	}
+	if err := checkChunkSize(4*1024*1024, 4*1024*1024); err == nil {
+		t.Error("chunk of max message size")
+	}
+	if err := checkChunkSize(4*1024*1024-chunkHeaderSize, 4*1024*1024); err != nil {
+		t.Error(err)
+	}
+}
@@ -17,3 +17,7 @@ require (
 	google.golang.org/genproto v0.0.0-20200925023002-c2d885f95484 // indirect
 	google.golang.org/grpc v1.32.0
 )
+
+replace github.com/synerex/synerex_api => ../api
+
+replace github.com/synerex/synerex_nodeapi => ../nodeapi
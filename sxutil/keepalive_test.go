@@ -0,0 +1,69 @@
+package sxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nodeapi "github.com/synerex/synerex_nodeapi"
+	"google.golang.org/grpc"
+)
+
+// blockingNodeClient blocks KeepAlive until release
+type blockingNodeClient struct {
+	nodeapi.NodeClient
+	called  chan *nodeapi.NodeUpdate
+	release chan struct{}
+}
+
+func (nc *blockingNodeClient) KeepAlive(ctx context.Context, nupd *nodeapi.NodeUpdate, opts ...grpc.CallOption) (*nodeapi.Response, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		nc.called <- nil
+	} else {
+		nc.called <- nupd
+	}
+	<-nc.release
+	return &nodeapi.Response{Ok: true, Token: "token"}, nil
+}
+
+func TestSendKeepAliveWithoutLock(t *testing.T) {
+	nc := &blockingNodeClient{called: make(chan *nodeapi.NodeUpdate, 1), release: make(chan struct{})}
+	ni := NewNodeServInfo()
+	ni.clt = nc
+	ni.nupd = &nodeapi.NodeUpdate{NodeId: 3, NodeStatus: 1}
+	done := make(chan error, 1)
+	go func() { done <- ni.SendKeepAlive() }()
+	nupd := <-nc.called
+	if nupd == nil {
+		t.Fatal("KeepAlive without deadline")
+	}
+	if nupd.UpdateCount != 1 || nupd.NodeId != 3 || nupd.NodeStatus != 1 {
+		t.Errorf("sent %v", nupd)
+	}
+	updated := make(chan struct{})
+	go func() {
+		ni.SetNodeStatus(2, "busy") // not blocked by KeepAlive RPC
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("SetNodeStatus is blocked during KeepAlive")
+	}
+	if nupd.NodeStatus != 1 {
+		t.Error("node update is changed during KeepAlive")
+	}
+	close(nc.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if ni.GetNodeToken() != "token" {
+		t.Errorf("token is not updated: %q", ni.GetNodeToken())
+	}
+}
+
+func TestSendKeepAliveNotRegistered(t *testing.T) {
+	if err := NewNodeServInfo().SendKeepAlive(); err == nil {
+		t.Error("KeepAlive is sent before registration")
+	}
+}
@@ -0,0 +1,31 @@
+package sxutil
+
+import (
+	"testing"
+
+	api "github.com/synerex/synerex_api"
+)
+
+func TestNodeStateProposals(t *testing.T) {
+	ns := NewNodeState()
+	sp := &api.Supply{Id: 1, SupplyName: "sp"}
+	ns.proposeSupply(sp)
+	ns.proposeSupply(&api.Supply{Id: 2})
+	ns.proposeDemand(&api.Demand{Id: 3, DemandName: "dm"})
+	sp.SupplyName = "changed" // proposals are kept as copies
+	if len(ns.ProposedSupply) != 2 || ns.ProposedSupply[0].GetSupplyName() != "sp" || ns.ProposedDemand[0].GetDemandName() != "dm" {
+		t.Fatalf("proposals %v %v", ns.ProposedSupply, ns.ProposedDemand)
+	}
+	if ns.isSafeState() {
+		t.Error("safe state with proposals")
+	}
+	if !ns.selectSupply(1) || ns.selectSupply(1) || !ns.selectSupply(2) {
+		t.Error("select supply")
+	}
+	if ns.selectDemand(4) || !ns.selectDemand(3) {
+		t.Error("select demand")
+	}
+	if !ns.isSafeState() {
+		t.Errorf("not safe state %v %v", ns.ProposedSupply, ns.ProposedDemand)
+	}
+}
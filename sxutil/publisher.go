@@ -0,0 +1,222 @@
+package sxutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+	"google.golang.org/grpc"
+)
+
+// Publisher sends demands or supplies on a Publish stream (PublishDemand / PublishSupply)
+// without waiting response of each message, for high-throughput producers.
+// The server returns PublishAck with accepted, dropped and denied (by auth or acl) counts periodically.
+// Rate limited messages are dropped by the server (not retried), and the publisher backs off with retry_after of the ack.
+type Publisher struct {
+	sent   uint64 // number of sent messages (first for atomic alignment)
+	clt    *SXServiceClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	stream grpc.ClientStream
+	method string
+	smu    sync.Mutex // for SendMsg
+	ack    *api.PublishAck
+	err    error // error of the stream
+	mu     sync.RWMutex
+	done   chan struct{} // closed when ack stream is finished
+}
+
+// ErrPublisherClosed is returned when sending on closed publisher
+var ErrPublisherClosed = errors.New("publisher is closed")
+
+// NewSupplyPublisher opens PublishSupply stream for the channel of the client
+func (clt *SXServiceClient) NewSupplyPublisher(ctx context.Context) (*Publisher, error) {
+	return clt.newPublisher(ctx, "PublishSupply", func(pctx context.Context) (grpc.ClientStream, error) {
+		return clt.SXClient.Client.PublishSupply(pctx)
+	})
+}
+
+// NewDemandPublisher opens PublishDemand stream for the channel of the client
+func (clt *SXServiceClient) NewDemandPublisher(ctx context.Context) (*Publisher, error) {
+	return clt.newPublisher(ctx, "PublishDemand", func(pctx context.Context) (grpc.ClientStream, error) {
+		return clt.SXClient.Client.PublishDemand(pctx)
+	})
+}
+
+func (clt *SXServiceClient) newPublisher(ctx context.Context, method string, open func(context.Context) (grpc.ClientStream, error)) (*Publisher, error) {
+	pctx, cancel := context.WithCancel(ctx)
+	stream, err := open(pctx)
+	if err != nil {
+		cancel()
+		logger.Warnf("%v %s Error %v", clt, method, err)
+		return nil, err
+	}
+	p := &Publisher{
+		clt:    clt,
+		ctx:    pctx,
+		cancel: cancel,
+		stream: stream,
+		method: method,
+		ack:    &api.PublishAck{},
+		done:   make(chan struct{}),
+	}
+	go p.recvAcks()
+	return p, nil
+}
+
+// recvAcks receives acks until the stream is finished
+func (p *Publisher) recvAcks() {
+	defer close(p.done)
+	for {
+		ack := &api.PublishAck{}
+		err := p.stream.RecvMsg(ack)
+		if err != nil {
+			if err != io.EOF {
+				logger.Warnf("%v %s ack error %v", p.clt, p.method, err)
+				p.mu.Lock()
+				p.err = err
+				p.mu.Unlock()
+			}
+			return
+		}
+		if ack.GetRetryAfter() != nil {
+			if d, derr := ptypes.Duration(ack.GetRetryAfter()); derr == nil {
+				p.clt.setBackoff(d)
+			}
+		}
+		if ack.GetDenied() > p.Denied() {
+			logger.Warnf("%s denied %d: %s", p.method, ack.GetDenied(), ack.GetErr())
+		} else if ack.GetErr() != "" {
+			logger.Debugf("%s dropped %d: %s", p.method, ack.GetDropped(), ack.GetErr())
+		}
+		p.mu.Lock()
+		p.ack = ack
+		p.mu.Unlock()
+	}
+}
+
+// send sends the message after back off of rate limits
+func (p *Publisher) send(msg interface{}) error {
+	select {
+	case <-p.done:
+		if err := p.Err(); err != nil {
+			return err
+		}
+		return ErrPublisherClosed
+	default:
+	}
+	if err := p.clt.waitBackoff(p.ctx); err != nil {
+		return err
+	}
+	p.smu.Lock()
+	err := p.stream.SendMsg(msg)
+	p.smu.Unlock()
+	if err == io.EOF { // stream is broken, the error is returned by RecvMsg
+		<-p.done
+		if err = p.Err(); err == nil {
+			err = ErrPublisherClosed
+		}
+	}
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&p.sent, 1)
+	return nil
+}
+
+// NotifySupply sends Typed Supply on the stream and returns its id (without waiting for ack).
+// Large Cdata is sent in chunks (acks count each chunk).
+func (p *Publisher) NotifySupply(smo *SupplyOpts) (uint64, error) {
+	id := GenerateIntID()
+	ts := ptypes.TimestampNow()
+	_, span := StartSpan(p.ctx, p.method, SpanKindProducer)
+	err := forEachChunk(id, smo.Cdata, func(mid uint64, cdata *api.Content) error {
+		return p.send(&api.Supply{
+			Id:          mid,
+			SenderId:    uint64(p.clt.ClientID),
+			ChannelType: p.clt.ChannelType,
+			SupplyName:  smo.Name,
+			Ts:          ts,
+			ArgJson:     smo.JSON,
+			Cdata:       cdata,
+			TraceParent: span.TraceParent(),
+		})
+	})
+	span.SetError(err)
+	span.End()
+	if err != nil {
+		return 0, err
+	}
+	smo.ID = id // assign ID
+	return id, nil
+}
+
+// NotifyDemand sends Typed Demand on the stream and returns its id (without waiting for ack).
+// Large Cdata is sent in chunks (acks count each chunk).
+func (p *Publisher) NotifyDemand(dmo *DemandOpts) (uint64, error) {
+	id := GenerateIntID()
+	ts := ptypes.TimestampNow()
+	_, span := StartSpan(p.ctx, p.method, SpanKindProducer)
+	err := forEachChunk(id, dmo.Cdata, func(mid uint64, cdata *api.Content) error {
+		return p.send(&api.Demand{
+			Id:          mid,
+			SenderId:    uint64(p.clt.ClientID),
+			ChannelType: p.clt.ChannelType,
+			DemandName:  dmo.Name,
+			Ts:          ts,
+			ArgJson:     dmo.JSON,
+			Cdata:       cdata,
+			TraceParent: span.TraceParent(),
+		})
+	})
+	span.SetError(err)
+	span.End()
+	if err != nil {
+		return 0, err
+	}
+	dmo.ID = id // assign ID
+	return id, nil
+}
+
+// Stats returns number of sent messages, and accepted/dropped messages of the last ack
+func (p *Publisher) Stats() (sent, accepted, dropped uint64) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return atomic.LoadUint64(&p.sent), p.ack.GetAccepted(), p.ack.GetDropped()
+}
+
+// Denied returns number of messages denied by auth or acl of the last ack
+func (p *Publisher) Denied() uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.ack.GetDenied()
+}
+
+// Err returns error of the stream (nil if not broken)
+func (p *Publisher) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.err
+}
+
+// Close finishes sending and waits for the final ack
+func (p *Publisher) Close() error {
+	p.smu.Lock()
+	err := p.stream.CloseSend()
+	p.smu.Unlock()
+	if err == nil {
+		select {
+		case <-p.done:
+		case <-p.ctx.Done():
+		}
+	}
+	p.cancel()
+	if err != nil {
+		return err
+	}
+	return p.Err()
+}
@@ -0,0 +1,66 @@
+package sxutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/synerex/synerex_api"
+)
+
+// Back off for rate limits of synerex server.
+// When a publish is rate limited, the server returns Response with retry_after.
+// The client waits until then before the next publish and retries the message.
+
+// RateLimitRetry is the max number of retries for a rate limited message
+var RateLimitRetry = 3
+
+// ErrRateLimited is returned when the message is still rate limited after retries
+var ErrRateLimited = errors.New("rate limited by synerex server")
+
+func (clt *SXServiceClient) setBackoff(d time.Duration) {
+	atomic.StoreInt64(&clt.backoffUntil, time.Now().Add(d).UnixNano())
+}
+
+// waitBackoff waits until the back off time of the client
+func (clt *SXServiceClient) waitBackoff(ctx context.Context) error {
+	wait := time.Until(time.Unix(0, atomic.LoadInt64(&clt.backoffUntil)))
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// publish calls publish RPC with back off and retry for rate limits
+func (clt *SXServiceClient) publish(pctx context.Context, call func(ctx context.Context) (*api.Response, error)) error {
+	for i := 0; ; i++ {
+		ctx, cancel := context.WithTimeout(pctx, MSG_TIME_OUT*time.Second)
+		err := clt.waitBackoff(ctx)
+		var resp *api.Response
+		if err == nil {
+			resp, err = call(ctx)
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+		if resp.GetOk() || resp.GetRetryAfter() == nil { // not rate limited
+			return nil
+		}
+		d, err := ptypes.Duration(resp.GetRetryAfter())
+		if err != nil {
+			return err
+		}
+		clt.setBackoff(d)
+		if i >= RateLimitRetry {
+			return ErrRateLimited
+		}
+	}
+}
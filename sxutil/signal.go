@@ -1,7 +1,6 @@
 package sxutil
 
 import (
-	"log"
 	"os"
 	"os/signal"
 )
@@ -23,7 +22,7 @@ func RegisterDeferFunction(f func()) {
 
 func CallDeferFunctions() {
 	for _, f := range funcSlice {
-		log.Printf("Calling %v", f)
+		logger.Debugf("Calling %p", f)
 		f()
 	}
 }
@@ -33,13 +32,13 @@ func HandleSigInt() {
 	signal.Notify(c, os.Interrupt, os.Kill)
 
 	for range c {
-		log.Println("Signal Interrupt!")
+		logger.Infof("Signal Interrupt!")
 		close(c)
 	}
 
 	CallDeferFunctions()
 
-	log.Println("End at HandleSigInt in sxutil/signal.go")
+	logger.Infof("End at HandleSigInt in sxutil/signal.go")
 	os.Exit(1)
 }
 
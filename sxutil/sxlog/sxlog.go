@@ -0,0 +1,275 @@
+// Package sxlog is a levelled, structured logger shared by synerex server, node server and sxutil.
+//
+// Each logger has a component name and fields (node id, channel type, client id...).
+// Output is text (like standard log) or JSON (one object per line).
+// Levels are set for each component at runtime (by SetLogLevel of synerex server admin service and node server).
+// Configuration is also read from environment variables:
+//
+//	SX_LOG_LEVEL  "info" or "info,mbus=debug,nodeserv.keepalive=warn"
+//	SX_LOG_FORMAT "text" or "json"
+package sxlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int32
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (lv Level) String() string {
+	if lv < DebugLevel || lv > ErrorLevel {
+		return "unknown"
+	}
+	return levelNames[lv]
+}
+
+// ParseLevel parses level name (debug, info, warn, error)
+func ParseLevel(str string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(str, name) {
+			return Level(i), nil
+		}
+	}
+	if strings.EqualFold(str, "warning") {
+		return WarnLevel, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", str)
+}
+
+// Field is a key value pair of log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger writes entries of a component with fields
+type Logger struct {
+	component string
+	fields    []Field
+}
+
+type config struct {
+	def    Level
+	levels map[string]Level // component -> level
+	json   bool
+	out    io.Writer
+	mu     sync.RWMutex
+}
+
+var conf = &config{
+	def:    InfoLevel,
+	levels: make(map[string]Level),
+	out:    os.Stderr,
+}
+
+func init() {
+	if env := os.Getenv("SX_LOG_LEVEL"); env != "" {
+		if err := SetLevels(env); err != nil {
+			log.Printf("Invalid SX_LOG_LEVEL: %v", err)
+		}
+	}
+	if env := os.Getenv("SX_LOG_FORMAT"); env != "" {
+		if err := SetFormat(env); err != nil {
+			log.Printf("Invalid SX_LOG_FORMAT: %v", err)
+		}
+	}
+}
+
+// New returns logger of the component
+func New(component string) *Logger {
+	return &Logger{component: component}
+}
+
+// With returns logger with additional field
+func (l *Logger) With(key string, value interface{}) *Logger {
+	fields := make([]Field, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	return &Logger{component: l.component, fields: append(fields, Field{key, value})}
+}
+
+// Component returns child logger for sub component (e.g. "server.mbus")
+func (l *Logger) Component(sub string) *Logger {
+	return &Logger{component: l.component + "." + sub, fields: l.fields}
+}
+
+// SetLevel sets level of the component (empty for default level)
+func SetLevel(component string, lv Level) {
+	conf.mu.Lock()
+	if component == "" {
+		conf.def = lv
+	} else {
+		conf.levels[component] = lv
+	}
+	conf.mu.Unlock()
+}
+
+// SetLevels sets levels with spec like "info,mbus=debug,nodeserv.keepalive=warn"
+func SetLevels(spec string) error {
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		component, name := "", item
+		if kv := strings.SplitN(item, "=", 2); len(kv) == 2 {
+			component, name = kv[0], kv[1]
+		}
+		lv, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		SetLevel(component, lv)
+	}
+	return nil
+}
+
+// Levels returns default level ("" key) and levels of components
+func Levels() map[string]Level {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	levels := map[string]Level{"": conf.def}
+	for c, lv := range conf.levels {
+		levels[c] = lv
+	}
+	return levels
+}
+
+// SetFormat sets output format ("text" or "json")
+func SetFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "text":
+		conf.mu.Lock()
+		conf.json = false
+		conf.mu.Unlock()
+	case "json":
+		conf.mu.Lock()
+		conf.json = true
+		conf.mu.Unlock()
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	return nil
+}
+
+// SetOutput sets output writer (default os.Stderr)
+func SetOutput(w io.Writer) {
+	conf.mu.Lock()
+	conf.out = w
+	conf.mu.Unlock()
+}
+
+// level returns level of the component.
+// The level of the nearest parent component ("server" for "server.mbus") is used if not set.
+func (c *config) level(component string) Level {
+	for {
+		if lv, ok := c.levels[component]; ok {
+			return lv
+		}
+		pos := strings.LastIndex(component, ".")
+		if pos < 0 {
+			return c.def
+		}
+		component = component[:pos]
+	}
+}
+
+// Enabled returns true if the level is written for the logger
+func (l *Logger) Enabled(lv Level) bool {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	return lv >= conf.level(l.component)
+}
+
+func (l *Logger) output(lv Level, msg string) {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	if lv < conf.level(l.component) {
+		return
+	}
+	now := time.Now()
+	msg = strings.TrimSuffix(msg, "\n")
+	var line []byte
+	if conf.json {
+		entry := map[string]interface{}{
+			"time":      now.Format(time.RFC3339Nano),
+			"level":     lv.String(),
+			"component": l.component,
+			"msg":       msg,
+		}
+		for _, f := range l.fields {
+			entry[f.Key] = f.Value
+		}
+		line, _ = json.Marshal(entry)
+	} else {
+		var sb strings.Builder
+		sb.WriteString(now.Format("2006/01/02 15:04:05 "))
+		sb.WriteString(strings.ToUpper(lv.String()))
+		sb.WriteString(" [" + l.component + "] ")
+		sb.WriteString(msg)
+		for _, f := range l.fields {
+			fmt.Fprintf(&sb, " %s=%v", f.Key, f.Value)
+		}
+		line = []byte(sb.String())
+	}
+	conf.out.Write(append(line, '\n'))
+}
+
+func (l *Logger) logf(lv Level, format string, args ...interface{}) {
+	if l.Enabled(lv) {
+		l.output(lv, fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(DebugLevel, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(InfoLevel, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(WarnLevel, format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(ErrorLevel, format, args...)
+}
+
+// Fatalf writes error entry and exits
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.output(ErrorLevel, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// stdWriter writes standard log entries as info of the logger
+type stdWriter struct {
+	l *Logger
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	w.l.output(InfoLevel, string(p))
+	return len(p), nil
+}
+
+// RedirectStdLog writes entries of standard log package through the logger
+func RedirectStdLog(l *Logger) {
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdWriter{l})
+}
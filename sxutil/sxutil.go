@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"io"
-	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/snowflake"
@@ -15,6 +15,7 @@ import (
 	api "github.com/synerex/synerex_api"
 	nodeapi "github.com/synerex/synerex_nodeapi"
 	pbase "github.com/synerex/synerex_proto"
+	"github.com/synerex/synerex_sxutil/sxlog"
 	"google.golang.org/grpc"
 )
 
@@ -32,6 +33,9 @@ const MSG_TIME_OUT = 20 // from v0.6.1 10sec -> 20sec
 
 const RECONNECT_WAIT = 5 // from v0.6.1
 
+// timeout of KeepAlive to node server (seconds)
+const KEEPALIVE_TIME_OUT = 10
+
 // for git versions
 var (
 	Sha1Ver   string // sha1 version used to build the program
@@ -52,6 +56,8 @@ type NodeServInfo struct { // we keep this for each nodeserver.
 	clt          nodeapi.NodeClient
 	msgCount     uint64
 	nodeState    *NodeState
+	token        string // node token for Synerex API (from node server)
+	tkmu         sync.RWMutex
 }
 
 type DemandHandler interface {
@@ -61,10 +67,16 @@ type DemandHandler interface {
 }
 
 type SupplyHandler interface {
+	OnNotifySupply(*SXServiceClient, *api.Supply) *DemandOpts // if propose return proposedID
+	OnSelectDemand(*SXServiceClient, *api.Supply) bool        // if confirm return true
+	OnConfirmResponse(*SXServiceClient, IDType, error)        // result of confirm
 }
 
 var defaultNI *NodeServInfo
 
+// logger of sxutil (levels are given by SX_LOG_LEVEL, e.g. "info,sxutil=debug")
+var logger = sxlog.New("sxutil")
+
 // DemandOpts is sender options for Demand
 type DemandOpts struct {
 	ID     uint64
@@ -115,10 +127,22 @@ func (ns *NodeState) isSafeState() bool {
 	return len(ns.ProposedSupply) == 0 && len(ns.ProposedDemand) == 0
 }
 
-func (ns *NodeState) proposeSupply(supply api.Supply) {
-	log.Printf("NodeState#proposeSupply[%d] is called", supply.Id)
-	ns.ProposedSupply = append(ns.ProposedSupply, supply)
-	log.Printf("proposeSupply len %d", len(ns.ProposedSupply))
+func (ns *NodeState) proposeSupply(supply *api.Supply) {
+	logger.Debugf("NodeState#proposeSupply[%d] is called", supply.Id)
+	ns.ProposedSupply = append(ns.ProposedSupply, api.Supply{ // copy fields (proto message can't be copied)
+		Id:          supply.Id,
+		SenderId:    supply.SenderId,
+		TargetId:    supply.TargetId,
+		ChannelType: supply.ChannelType,
+		SupplyName:  supply.SupplyName,
+		Ts:          supply.Ts,
+		ArgJson:     supply.ArgJson,
+		MbusId:      supply.MbusId,
+		Cdata:       supply.Cdata,
+		Seq:         supply.Seq,
+		TraceParent: supply.TraceParent,
+	})
+	logger.Debugf("proposeSupply len %d", len(ns.ProposedSupply))
 
 }
 
@@ -142,31 +166,46 @@ func (ns *NodeState) selectSupply(id uint64) bool {
 		ns.removeProposedSupplyIndex(pos)
 		return true
 	} else {
-		log.Printf("not found supply[%d]\n", id)
+		logger.Warnf("not found supply[%d]", id)
 		return false
 	}
 }
 
-func (ns *NodeState) proposeDemand(demand api.Demand) {
-	log.Printf("NodeState#proposeDemand[%d] is called\n", demand.Id)
-	ns.ProposedDemand = append(ns.ProposedDemand, demand)
-}
-
-func (ns *NodeState) selectDemand(id uint64) bool {
-	log.Printf("NodeState#selectDemand[%d] is called\n", id)
-
-	pos := -1
+func (ns *NodeState) proposeDemand(demand *api.Demand) {
+	logger.Debugf("NodeState#proposeDemand[%d] is called", demand.Id)
+	ns.ProposedDemand = append(ns.ProposedDemand, api.Demand{ // copy fields (proto message can't be copied)
+		Id:          demand.Id,
+		SenderId:    demand.SenderId,
+		TargetId:    demand.TargetId,
+		ChannelType: demand.ChannelType,
+		DemandName:  demand.DemandName,
+		Ts:          demand.Ts,
+		ArgJson:     demand.ArgJson,
+		MbusId:      demand.MbusId,
+		Cdata:       demand.Cdata,
+		Seq:         demand.Seq,
+		TraceParent: demand.TraceParent,
+	})
+}
+
+func (ns *NodeState) proposedDemandIndex(id uint64) int {
 	for i := 0; i < len(ns.ProposedDemand); i++ {
 		if ns.ProposedDemand[i].Id == id {
-			pos = i
+			return i
 		}
 	}
+	return -1
+}
+
+func (ns *NodeState) selectDemand(id uint64) bool {
+	logger.Debugf("NodeState#selectDemand[%d] is called", id)
 
+	pos := ns.proposedDemandIndex(id)
 	if pos >= 0 {
 		ns.ProposedDemand = append(ns.ProposedDemand[:pos], ns.ProposedDemand[pos+1:]...)
 		return true
 	} else {
-		log.Printf("not found supply[%d]\n", id)
+		logger.Warnf("not found supply[%d]", id)
 
 		return false
 	}
@@ -194,9 +233,9 @@ func InitNodeNum(n int) {
 	var err error
 	defaultNI.node, err = snowflake.NewNode(int64(n))
 	if err != nil {
-		log.Println("Error in initializing snowflake:", err)
+		logger.Errorf("Error in initializing snowflake: %v", err)
 	} else {
-		log.Println("Successfully Initialize node ", n)
+		logger.Infof("Successfully Initialize node %d", n)
 	}
 }
 
@@ -204,17 +243,39 @@ func InitNodeNum(n int) {
 func (ni *NodeServInfo) GetNodeName(n int) string {
 	nid, err := ni.clt.QueryNode(context.Background(), &nodeapi.NodeID{NodeId: int32(n)})
 	if err != nil {
-		log.Printf("Error on QueryNode %v", err)
+		logger.Warnf("Error on QueryNode %v", err)
 		return "Unknown"
 	}
 	return nid.NodeName
 }
 
+// GetNodeName returns node name from node_id with default node server
+func GetNodeName(n int) string {
+	return defaultNI.GetNodeName(n)
+}
+
+// GetNodeType returns node type (provider/server/gateway) from node_id
+func (ni *NodeServInfo) GetNodeType(n int) (nodeapi.NodeType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	nid, err := ni.clt.QueryNode(ctx, &nodeapi.NodeID{NodeId: int32(n)})
+	if err != nil {
+		logger.Warnf("Error on QueryNode %v", err)
+		return nodeapi.NodeType_PROVIDER, err
+	}
+	return nid.NodeType, nil
+}
+
+// GetNodeType returns node type from node_id with default node server
+func GetNodeType(n int) (nodeapi.NodeType, error) {
+	return defaultNI.GetNodeType(n)
+}
+
 /*
 func GetNodeName(n int) string {
 	ni, err := defaultNI.clt.QueryNode(context.Background(), &nodeapi.NodeID{NodeId: int32(n)})
 	if err != nil {
-		log.Printf("Error on QueryNode %v", err)
+		logger.Warnf("Error on QueryNode %v", err)
 		return "Unknown"
 	}
 	return ni.NodeName
@@ -234,38 +295,105 @@ func SetNodeStatus(status int32, arg string) {
 	defaultNI.SetNodeStatus(status, arg)
 }
 
+// keepAlive sends KeepAlive with a copy of current node update (the lock is not held during the RPC)
+func (ni *NodeServInfo) keepAlive(status *nodeapi.ServerStatus) (*nodeapi.Response, error) {
+	ni.numu.Lock()
+	if ni.nupd == nil {
+		ni.numu.Unlock()
+		return nil, errors.New("node is not registered")
+	}
+	if status != nil {
+		ni.nupd.Status = status
+	}
+	ni.nupd.UpdateCount++
+	nupd := &nodeapi.NodeUpdate{
+		NodeId:       ni.nupd.NodeId,
+		Secret:       ni.nupd.Secret,
+		UpdateCount:  ni.nupd.UpdateCount,
+		NodeStatus:   ni.nupd.NodeStatus,
+		NodeArg:      ni.nupd.NodeArg,
+		Status:       ni.nupd.Status,
+		ChannelTypes: ni.nupd.ChannelTypes,
+	}
+	clt := ni.clt
+	ni.numu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), KEEPALIVE_TIME_OUT*time.Second)
+	defer cancel()
+	return clt.KeepAlive(ctx, nupd)
+}
+
+// SendKeepAlive sends KeepAlive to NodeServer immediately (e.g. for notifying status change)
+func (ni *NodeServInfo) SendKeepAlive() error {
+	resp, err := ni.keepAlive(nil)
+	if err != nil {
+		return err
+	}
+	ni.setToken(resp.Token)
+	return nil
+}
+
+// SendKeepAlive sends KeepAlive to NodeServer immediately
+func SendKeepAlive() error {
+	return defaultNI.SendKeepAlive()
+}
+
+// SetNodeChannelTypes updates channel types in use, which are sent by KeepAlive
+func (ni *NodeServInfo) SetNodeChannelTypes(channels []uint32) {
+	ni.numu.Lock()
+	if ni.nupd != nil {
+		ni.nupd.ChannelTypes = channels
+	}
+	ni.numu.Unlock()
+}
+
+// SetNodeChannelTypes updates channel types in use, which are sent by KeepAlive
+func SetNodeChannelTypes(channels []uint32) {
+	defaultNI.SetNodeChannelTypes(channels)
+}
+
 func (ni *NodeServInfo) reconnectNodeServ() error { // re_send connection info to server.
+	var channels []uint32
+	ni.numu.RLock()
+	if ni.nupd != nil {
+		channels = ni.nupd.ChannelTypes
+	}
+	ni.numu.RUnlock()
 	nif := nodeapi.NodeInfo{
 		NodeName:         ni.myNodeName,
 		NodeType:         ni.myNodeType,
 		ServerInfo:       ni.myServerInfo,          // TODO: this is not correctly initialized
 		NodePbaseVersion: pbase.ChannelTypeVersion, // this is defined at compile time
 		WithNodeId:       ni.nid.NodeId,
+		ChannelTypes:     channels,
 		BinVersion:       GitVer, // git bin tag version
 	}
 	var ee error
 	ni.nid, ee = ni.clt.RegisterNode(context.Background(), &nif)
 	if ee != nil { // has error!
-		log.Println("Error on get NodeID", ee)
+		logger.Warnf("Error on get NodeID: %v", ee)
 		return ee
 	} else {
 		var nderr error
 		ni.node, nderr = snowflake.NewNode(int64(ni.nid.NodeId))
 		if nderr != nil {
-			log.Println("Error in initializing snowflake:", nderr)
+			logger.Errorf("Error in initializing snowflake: %v", nderr)
 			return nderr
 		} else {
-			log.Println("Successfully ReInitialize node ", ni.nid.NodeId)
+			logger.Infof("Successfully ReInitialize node %d", ni.nid.NodeId)
 		}
 	}
+	ni.setToken(ni.nid.Token)
 
+	ni.numu.Lock()
 	ni.nupd = &nodeapi.NodeUpdate{
-		NodeId:      ni.nid.NodeId,
-		Secret:      ni.nid.Secret,
-		UpdateCount: 0,
-		NodeStatus:  0,
-		NodeArg:     "",
+		NodeId:       ni.nid.NodeId,
+		Secret:       ni.nid.Secret,
+		UpdateCount:  0,
+		NodeStatus:   0,
+		NodeArg:      "",
+		ChannelTypes: channels,
 	}
+	ni.numu.Unlock()
 	//	fmt.Println("KeepAlive started!")
 	return nil
 }
@@ -284,31 +412,28 @@ func (ni *NodeServInfo) startKeepAliveWithCmd(cmd_func func(nodeapi.KeepAliveCom
 			break
 		}
 
+		var status *nodeapi.ServerStatus
 		if ni.myNodeType == nodeapi.NodeType_SERVER {
 			c, _ := cpu.Percent(5, false)
 			v, _ := mem.VirtualMemory()
-			var status nodeapi.ServerStatus
-			status = nodeapi.ServerStatus{
+			status = &nodeapi.ServerStatus{
 				Cpu:      c[0],
 				Memory:   v.UsedPercent,
 				MsgCount: ni.msgCount,
 			}
-			ni.nupd.Status = &status
 		}
 
-		ni.numu.RLock()
-		ni.nupd.UpdateCount++
-		resp, err := ni.clt.KeepAlive(context.Background(), ni.nupd)
-		ni.numu.RUnlock()
+		resp, err := ni.keepAlive(status)
 		if err != nil {
-			log.Printf("Error in response, may nodeserv failure %v:%v", resp, err)
+			logger.Warnf("Error in response, may nodeserv failure %v:%v", resp, err)
 		}
 		if resp != nil { // there might be some errors in response
+			ni.setToken(resp.Token)
 			switch resp.Command {
 			case nodeapi.KeepAliveCommand_RECONNECT: // order is reconnect to node.
 				ni.reconnectNodeServ()
 			case nodeapi.KeepAliveCommand_SERVER_CHANGE:
-				log.Printf("receive SERVER_CHANGE\n")
+				logger.Infof("receive SERVER_CHANGE")
 
 				if ni.nodeState.isSafeState() {
 					ni.UnRegisterNode()
@@ -334,9 +459,9 @@ func (ni *NodeServInfo) startKeepAliveWithCmd(cmd_func func(nodeapi.KeepAliveCom
 					}
 				}
 			case nodeapi.KeepAliveCommand_PROVIDER_DISCONNECT:
-				log.Printf("receive PROV_DISCONN %s\n", resp.Err)
+				logger.Infof("receive PROV_DISCONN %s", resp.Err)
 				if ni.myNodeType != nodeapi.NodeType_SERVER {
-					log.Printf("NodeType shoud be SERVER! %d %s %#v", ni.myNodeType, ni.myNodeName, resp)
+					logger.Warnf("NodeType shoud be SERVER! %d %s %#v", ni.myNodeType, ni.myNodeName, resp)
 				} else if cmd_func != nil {
 					// work provider disconnect
 					cmd_func(resp.Command, resp.Err)
@@ -366,12 +491,17 @@ func RegisterNodeWithCmd(nodesrv string, nm string, channels []uint32, serv *SxS
 
 // RegisterNodeWithCmd is a function to register Node with node server address and KeepAlive Command Callback
 func (ni *NodeServInfo) RegisterNodeWithCmd(nodesrv string, nm string, channels []uint32, serv *SxServerOpt, cmd_func func(nodeapi.KeepAliveCommand, string)) (string, error) { // register ID to server
+	startTracingFromEnv(nm)
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure()) // insecure
-	var err error
+	dopt, err := tlsOpt.DialOption() // insecure if TLS is not specified
+	if err != nil {
+		logger.Warnf("fail to load TLS certificates: %v", err)
+		return "", err
+	}
+	opts = append(opts, dopt)
 	ni.conn, err = grpc.Dial(nodesrv, opts...)
 	if err != nil {
-		log.Printf("fail to dial: %v", err)
+		logger.Warnf("fail to dial: %v", err)
 		return "", err
 	}
 	//	defer conn.Close()
@@ -420,25 +550,29 @@ func (ni *NodeServInfo) RegisterNodeWithCmd(nodesrv string, nm string, channels
 	var ee error
 	ni.nid, ee = ni.clt.RegisterNode(context.Background(), &nif)
 	if ee != nil { // has error!
-		log.Println("Error on get NodeID", ee)
+		logger.Warnf("Error on get NodeID: %v", ee)
 		return "", ee
 	} else {
 		var nderr error
 		ni.node, nderr = snowflake.NewNode(int64(ni.nid.NodeId))
 		if nderr != nil {
-			log.Println("Error in initializing snowflake:", err)
+			logger.Errorf("Error in initializing snowflake: %v", err)
 			return "", nderr
 		} else {
-			log.Println("Successfully ReInitialize node ", ni.nid.NodeId)
+			logger.Infof("Successfully ReInitialize node %d", ni.nid.NodeId)
 		}
 	}
+	ni.setToken(ni.nid.Token)
+	ni.numu.Lock()
 	ni.nupd = &nodeapi.NodeUpdate{
-		NodeId:      ni.nid.NodeId,
-		Secret:      ni.nid.Secret,
-		UpdateCount: 0,
-		NodeStatus:  0,
-		NodeArg:     "",
+		NodeId:       ni.nid.NodeId,
+		Secret:       ni.nid.Secret,
+		UpdateCount:  0,
+		NodeStatus:   0,
+		NodeArg:      "",
+		ChannelTypes: channels,
 	}
+	ni.numu.Unlock()
 	// start keepalive goroutine
 	go ni.startKeepAliveWithCmd(cmd_func)
 	//	fmt.Println("KeepAlive started!")
@@ -452,11 +586,11 @@ func UnRegisterNode() {
 
 // UnRegisterNode de-registrate node id
 func (ni *NodeServInfo) UnRegisterNode() {
-	log.Println("UnRegister Node ", ni.nid)
+	logger.Infof("UnRegister Node %v", ni.nid)
 	resp, err := ni.clt.UnRegisterNode(context.Background(), ni.nid)
 	ni.nid.Secret = 0
 	if err != nil || !resp.Ok {
-		log.Print("Can't unregister", err, resp)
+		logger.Warnf("Can't unregister %v %v", err, resp)
 	}
 }
 
@@ -468,22 +602,35 @@ type SXSynerexClient struct {
 
 // SXServiceClient Wrappter Structure for synerex client
 type SXServiceClient struct {
-	ClientID    IDType
-	ChannelType uint32
-	SXClient    *SXSynerexClient
-	ArgJson     string
-	MbusIDs     []IDType
-	mbusMutex   sync.RWMutex
-	NI          *NodeServInfo
+	demandSeq    uint64 // sequence number of the last complete demand (for resuming subscription, first for atomic alignment)
+	supplySeq    uint64 // sequence number of the last complete supply
+	backoffUntil int64  // unix nano time until publishes wait for rate limits
+	ClientID     IDType
+	ChannelType  uint32
+	SXClient     *SXSynerexClient
+	ArgJson      string
+	BufferSize   uint32 // requested subscriber buffer size on server (0 for default)
+	MbusIDs      []IDType
+	mbusMutex    sync.RWMutex
+	NI           *NodeServInfo
+	chunks       chunkAssembler // received chunks of large Cdata
 }
 
 // GrpcConnectServer is a utility function for conneting gRPC server
 func GrpcConnectServer(serverAddress string) *SXSynerexClient { // TODO: we may add connection option
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure()) // currently we do not use sercure connection //TODO: we need to udpate SSL
+	dopt, err := tlsOpt.DialOption() // insecure if TLS is not specified
+	if err != nil {
+		logger.Warnf("fail to load TLS certificates: %v", err)
+		return nil
+	}
+	opts = append(opts, dopt)
+	opts = append(opts, grpc.WithPerRPCCredentials(&nodeTokenCreds{defaultNI})) // node token for auth
+	opts = append(opts, grpc.WithUnaryInterceptor(traceUnaryClientInterceptor), grpc.WithStreamInterceptor(traceStreamClientInterceptor))
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxMsgSize), grpc.MaxCallSendMsgSize(MaxMsgSize)))
 	conn, err := grpc.Dial(serverAddress, opts...)
 	if err != nil {
-		log.Printf("fail to connect server %s: %v", serverAddress, err)
+		logger.Warnf("fail to connect server %s: %v", serverAddress, err)
 		return nil
 	}
 	// from v0.5.0 , we support Connection in sxutil.
@@ -521,7 +668,7 @@ func (ni *NodeServInfo) GenerateIntID() uint64 {
 }
 
 func (clt SXServiceClient) getChannel() *api.Channel {
-	return &api.Channel{ClientId: uint64(clt.ClientID), ChannelType: clt.ChannelType, ArgJson: clt.ArgJson}
+	return &api.Channel{ClientId: uint64(clt.ClientID), ChannelType: clt.ChannelType, ArgJson: clt.ArgJson, BufferSize: clt.BufferSize}
 }
 
 // IsSupplyTarget is a helper function to check target
@@ -546,7 +693,7 @@ func (clt *SXServiceClient) IsDemandTarget(dm *api.Demand, idlist []uint64) bool
 	return false
 }
 
-// ProposeSupply send proposal Supply message to server
+// ProposeSupply send proposal Supply message to server (large Cdata is sent in chunks)
 func (clt *SXServiceClient) ProposeSupply(spo *SupplyOpts) uint64 {
 	pid := GenerateIntID()
 	sp := &api.Supply{
@@ -564,21 +711,33 @@ func (clt *SXServiceClient) ProposeSupply(spo *SupplyOpts) uint64 {
 	//Todo: We need to make if for each channel type
 	//	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
-	defer cancel()
-	_, err := clt.SXClient.Client.ProposeSupply(ctx, sp)
+	err := forEachChunk(pid, spo.Cdata, func(mid uint64, cdata *api.Content) error {
+		csp := &api.Supply{
+			Id:          mid,
+			SenderId:    sp.SenderId,
+			TargetId:    sp.TargetId,
+			ChannelType: sp.ChannelType,
+			SupplyName:  sp.SupplyName,
+			Ts:          sp.Ts,
+			ArgJson:     sp.ArgJson,
+			Cdata:       cdata,
+		}
+		return clt.publish(receivedTraces.context(context.Background(), spo.Target), func(ctx context.Context) (*api.Response, error) {
+			return clt.SXClient.Client.ProposeSupply(ctx, csp)
+		})
+	})
 	if err != nil {
-		log.Printf("%v.ProposeSupply err %v, [%v]", clt, err, sp)
+		logger.Warnf("%v.ProposeSupply err %v, [%v]", clt, err, sp)
 		return 0 // should check...
 	}
 	//	log.Println("ProposeSupply Response:", resp, ":PID ",pid)
 
-	clt.NI.nodeState.proposeSupply(*sp)
+	clt.NI.nodeState.proposeSupply(sp)
 
 	return pid
 }
 
-// ProposeDemand send proposal Demand message to server
+// ProposeDemand send proposal Demand message to server (large Cdata is sent in chunks)
 func (clt *SXServiceClient) ProposeDemand(dmo *DemandOpts) uint64 {
 	pid := GenerateIntID()
 	dm := &api.Demand{
@@ -592,14 +751,26 @@ func (clt *SXServiceClient) ProposeDemand(dmo *DemandOpts) uint64 {
 		Cdata:       dmo.Cdata,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
-	defer cancel()
-	_, err := clt.SXClient.Client.ProposeDemand(ctx, dm)
+	err := forEachChunk(pid, dmo.Cdata, func(mid uint64, cdata *api.Content) error {
+		cdm := &api.Demand{
+			Id:          mid,
+			SenderId:    dm.SenderId,
+			TargetId:    dm.TargetId,
+			ChannelType: dm.ChannelType,
+			DemandName:  dm.DemandName,
+			Ts:          dm.Ts,
+			ArgJson:     dm.ArgJson,
+			Cdata:       cdata,
+		}
+		return clt.publish(receivedTraces.context(context.Background(), dmo.Target), func(ctx context.Context) (*api.Response, error) {
+			return clt.SXClient.Client.ProposeDemand(ctx, cdm)
+		})
+	})
 	if err != nil {
-		log.Printf("ProposeDemand %  \nerr %v, [%v]", clt, err, dm)
+		logger.Warnf("%v.ProposeDemand err %v, [%v]", clt, err, dm)
 		return 0 // should check...
 	}
-	clt.NI.nodeState.proposeDemand(*dm)
+	clt.NI.nodeState.proposeDemand(dm)
 	return pid
 }
 
@@ -611,13 +782,14 @@ func (clt *SXServiceClient) SelectSupply(sp *api.Supply) (uint64, error) {
 		TargetId:    sp.Id, /// Message Id of Supply (not SenderId),
 		ChannelType: sp.ChannelType,
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
+	ctx, cancel := context.WithTimeout(receivedTraces.context(context.Background(), sp.Id), MSG_TIME_OUT*time.Second)
 	defer cancel()
 	resp, err := clt.SXClient.Client.SelectSupply(ctx, tgt)
 	if err != nil {
-		log.Printf("%v.SelectSupply err %v %v", clt, err, resp)
+		logger.Warnf("%v.SelectSupply err %v %v", clt, err, resp)
 		return 0, err
 	}
+	receivedTraces.add(resp.MbusId, TraceParentFromContext(ctx)) // for mbus messages
 	//	log.Println("SelectSupply Response:", resp)
 	// if mbus is OK, start mbus!
 	//	clt.MbusID = IDType(resp.MbusId)
@@ -642,16 +814,19 @@ func (clt *SXServiceClient) SelectDemand(dm *api.Demand) (uint64, error) {
 		TargetId:    dm.Id,
 		ChannelType: dm.ChannelType,
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
+	ctx, cancel := context.WithTimeout(receivedTraces.context(context.Background(), dm.Id), MSG_TIME_OUT*time.Second)
 	defer cancel()
 	resp, err := clt.SXClient.Client.SelectDemand(ctx, tgt)
 	if err != nil {
-		log.Printf("%v.SelectDemand err %v %v", clt, err, resp)
+		logger.Warnf("%v.SelectDemand err %v %v", clt, err, resp)
 		return 0, err
 	}
+	receivedTraces.add(resp.MbusId, TraceParentFromContext(ctx)) // for mbus messages
 	//	log.Println("SelectDemand Response:", resp)
 	//	clt.MbusID = IDType(resp.MbusId)
+	clt.mbusMutex.Lock()
 	clt.MbusIDs = append(clt.MbusIDs, IDType(resp.MbusId))
+	clt.mbusMutex.Unlock()
 	//	if clt.MbusID != 0 {
 	//TODO:  We need to implement Mbus systems
 	//		clt.SubscribeMbus()
@@ -661,11 +836,13 @@ func (clt *SXServiceClient) SelectDemand(dm *api.Demand) (uint64, error) {
 }
 
 // SubscribeSupply  Wrapper function for SXServiceClient
+// Subscription is resumed after the last complete supply (if server keeps it).
 func (clt *SXServiceClient) SubscribeSupply(ctx context.Context, spcb func(*SXServiceClient, *api.Supply)) error {
 	ch := clt.getChannel()
+	ch.ResumeAfter = atomic.LoadUint64(&clt.supplySeq)
 	smc, err := clt.SXClient.Client.SubscribeSupply(ctx, ch)
 	if err != nil {
-		log.Printf("%v SubscribeSupply Error %v", clt, err)
+		logger.Warnf("%v SubscribeSupply Error %v", clt, err)
 		return err
 	}
 	for {
@@ -673,29 +850,36 @@ func (clt *SXServiceClient) SubscribeSupply(ctx context.Context, spcb func(*SXSe
 		sp, err = smc.Recv() // receive Demand
 		if err != nil {
 			if err == io.EOF {
-				log.Print("End Supply subscribe OK")
+				logger.Infof("End Supply subscribe OK")
 			} else {
-				log.Printf("%v SXServiceClient SubscribeSupply error [%v]", clt, err)
+				logger.Warnf("%v SXServiceClient SubscribeSupply error [%v]", clt, err)
 			}
 			break
 		}
 		//		log.Println("Receive SS:", *sp)
+		if sp = clt.receiveSupply(sp); sp == nil { // waiting other chunks
+			continue
+		}
 
 		if !clt.NI.nodeState.Locked {
+			tsp := traceReceived("SubscribeSupply", sp.Id, sp.TraceParent)
 			spcb(clt, sp)
+			tsp.End()
 		} else {
-			log.Println("Provider is locked!")
+			logger.Debugf("Provider is locked!")
 		}
 	}
 	return err
 }
 
 // SubscribeDemand  Wrapper function for SXServiceClient
+// Subscription is resumed after the last complete demand (if server keeps it).
 func (clt *SXServiceClient) SubscribeDemand(ctx context.Context, dmcb func(*SXServiceClient, *api.Demand)) error {
 	ch := clt.getChannel()
+	ch.ResumeAfter = atomic.LoadUint64(&clt.demandSeq)
 	dmc, err := clt.SXClient.Client.SubscribeDemand(ctx, ch)
 	if err != nil {
-		log.Printf("%v SubscribeDemand Error %v", clt, err)
+		logger.Warnf("%v SubscribeDemand Error %v", clt, err)
 		return err // sender should handle error...
 	}
 	for {
@@ -703,19 +887,24 @@ func (clt *SXServiceClient) SubscribeDemand(ctx context.Context, dmcb func(*SXSe
 		dm, err = dmc.Recv() // receive Demand
 		if err != nil {
 			if err == io.EOF {
-				log.Print("End Demand subscribe OK")
+				logger.Infof("End Demand subscribe OK")
 			} else {
-				log.Printf("%v SXServiceClient SubscribeDemand error [%v]", clt, err)
+				logger.Warnf("%v SXServiceClient SubscribeDemand error [%v]", clt, err)
 			}
 			break
 		}
 		//	log.Println("Receive SD:",*dm)
+		if dm = clt.receiveDemand(dm); dm == nil { // waiting other chunks
+			continue
+		}
 
 		// call Callback!
 		if !clt.NI.nodeState.Locked {
+			tsp := traceReceived("SubscribeDemand", dm.Id, dm.TraceParent)
 			dmcb(clt, dm)
+			tsp.End()
 		} else {
-			log.Println("Provider is locked!")
+			logger.Debugf("Provider is locked!")
 		}
 	}
 	return err
@@ -733,7 +922,7 @@ func (clt *SXServiceClient) SubscribeMbus(ctx context.Context, mbusId uint64, mb
 
 	smc, err := clt.SXClient.Client.SubscribeMbus(ctx, mb)
 	if err != nil {
-		log.Printf("%v Synerex_SubscribeMbusClient Error %v", clt, err)
+		logger.Warnf("%v Synerex_SubscribeMbusClient Error %v", clt, err)
 		return err // sender should handle error...
 	}
 	for {
@@ -741,19 +930,91 @@ func (clt *SXServiceClient) SubscribeMbus(ctx context.Context, mbusId uint64, mb
 		mes, err = smc.Recv() // receive Demand
 		if err != nil {
 			if err == io.EOF {
-				log.Print("End Mbus subscribe OK")
+				logger.Infof("End Mbus subscribe OK")
 			} else {
-				log.Printf("%v SXServiceClient SubscribeMbus error %v", clt, err)
+				logger.Warnf("%v SXServiceClient SubscribeMbus error %v", clt, err)
 			}
 			break
 		}
 		//		log.Printf("Receive Mbus Message %v", *mes)
+		if mes = clt.assembleMbusMsg(mes); mes == nil { // waiting other chunks
+			continue
+		}
 		// call Callback!
+		tsp := traceReceived("SubscribeMbus", mes.MsgId, mes.TraceParent)
 		mbcb(clt, mes)
+		tsp.End()
 	}
 	return err
 }
 
+// WatchMbus calls evcb for each member event of the mbus until CLOSED or ctx is done.
+func (clt *SXServiceClient) WatchMbus(ctx context.Context, mbusId uint64, evcb func(*SXServiceClient, *api.MbusEvent)) error {
+	mb := &api.Mbus{
+		ClientId: uint64(clt.ClientID),
+		MbusId:   mbusId,
+	}
+	wmc, err := clt.SXClient.Client.WatchMbus(ctx, mb)
+	if err != nil {
+		logger.Warnf("%v Synerex_WatchMbusClient Error %v", clt, err)
+		return err
+	}
+	for {
+		var ev *api.MbusEvent
+		ev, err = wmc.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			} else {
+				logger.Warnf("%v SXServiceClient WatchMbus error %v", clt, err)
+			}
+			return err
+		}
+		evcb(clt, ev)
+	}
+}
+
+// WaitMbusMembers blocks until all members subscribe the mbus.
+func (clt *SXServiceClient) WaitMbusMembers(ctx context.Context, mbusId uint64, members []uint64) error {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mb := &api.Mbus{
+		ClientId: uint64(clt.ClientID),
+		MbusId:   mbusId,
+	}
+	wmc, err := clt.SXClient.Client.WatchMbus(wctx, mb)
+	if err != nil {
+		logger.Warnf("%v Synerex_WatchMbusClient Error %v", clt, err)
+		return err
+	}
+	for {
+		ev, err := wmc.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return errors.New("Mbus watch closed")
+			}
+			return err
+		}
+		if ev.EventType == api.MbusEvent_CLOSED {
+			return errors.New("Mbus closed")
+		}
+		joined := make(map[uint64]bool)
+		for _, id := range ev.Subscribers {
+			joined[id] = true
+		}
+		ok := true
+		for _, id := range members {
+			if !joined[id] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return nil
+		}
+	}
+}
+
 // v0.4.1 name change
 func (clt *SXServiceClient) SendMbusMsg(ctx context.Context, mbusId uint64, msg *api.MbusMsg) (uint64, error) { // return from mbus_msgID(sxutil v0.5.3)
 	if len(clt.MbusIDs) == 0 {
@@ -762,22 +1023,68 @@ func (clt *SXServiceClient) SendMbusMsg(ctx context.Context, mbusId uint64, msg
 	msg.MsgId = GenerateIntID()
 	msg.SenderId = uint64(clt.ClientID)
 	msg.MbusId = mbusId // uint64(clt.MbusID) // now we can use multiple mbus from v0.6.0
-	//TODO: need to check response
-	resp, err := clt.SXClient.Client.SendMbusMsg(ctx, msg)
-	if err == nil && resp.Ok == false {
-		err = errors.New(resp.Err)
-	}
+	// large Cdata is sent in chunks
+	err := forEachChunk(msg.MsgId, msg.Cdata, func(mid uint64, cdata *api.Content) error {
+		cmsg := &api.MbusMsg{
+			MsgId:    mid,
+			SenderId: msg.SenderId,
+			TargetId: msg.TargetId,
+			MbusId:   msg.MbusId,
+			MsgType:  msg.MsgType,
+			MsgInfo:  msg.MsgInfo,
+			ArgJson:  msg.ArgJson,
+			Cdata:    cdata,
+		}
+		resp, err := clt.SXClient.Client.SendMbusMsg(receivedTraces.context(ctx, mbusId), cmsg)
+		if err == nil && resp.Ok == false {
+			err = errors.New(resp.Err)
+		}
+		return err
+	})
 
 	return msg.MsgId, err
 }
 
 // from synerex_api v0.4.0
+// For PRIVATE mbus, opt.Subscribers are the members and this client becomes the owner.
 func (clt *SXServiceClient) CreateMbus(ctx context.Context, opt *api.MbusOpt) (*api.Mbus, error) {
+	if opt.ClientId == 0 {
+		opt.ClientId = uint64(clt.ClientID) // owner of the mbus
+	}
 	mbus, err := clt.SXClient.Client.CreateMbus(ctx, opt)
+	if err != nil {
+		logger.Warnf("%v CreateMbus Error %v", clt, err)
+		return nil, err
+	}
 	mbus.ClientId = uint64(clt.ClientID) // set by myself for future use.
+	clt.mbusMutex.Lock()
+	clt.MbusIDs = append(clt.MbusIDs, IDType(mbus.MbusId))
+	clt.mbusMutex.Unlock()
 	return mbus, err
 }
 
+// AddMbusMembers allows members to join the private mbus (owner only)
+func (clt *SXServiceClient) AddMbusMembers(ctx context.Context, mbusId uint64, members []uint64) error {
+	mm := &api.MbusMembers{
+		ClientId: uint64(clt.ClientID),
+		MbusId:   mbusId,
+		Members:  members,
+	}
+	_, err := clt.SXClient.Client.AddMbusMembers(ctx, mm)
+	return err
+}
+
+// RemoveMbusMembers removes members from the private mbus (owner only)
+func (clt *SXServiceClient) RemoveMbusMembers(ctx context.Context, mbusId uint64, members []uint64) error {
+	mm := &api.MbusMembers{
+		ClientId: uint64(clt.ClientID),
+		MbusId:   mbusId,
+		Members:  members,
+	}
+	_, err := clt.SXClient.Client.RemoveMbusMembers(ctx, mm)
+	return err
+}
+
 // from synerex_api v0.4.0
 func (clt *SXServiceClient) GetMbusStatus(ctx context.Context, mb *api.Mbus) (*api.MbusState, error) {
 	mbs, err := clt.SXClient.Client.GetMbusState(ctx, mb)
@@ -812,37 +1119,38 @@ func (clt *SXServiceClient) CloseMbus(ctx context.Context, mbusId uint64) error
 		if pos >= 0 {
 			clt.removeMbusIndex(pos)
 		} else {
-			log.Printf("not found mbusID[%d]\n", mbusId)
+			logger.Warnf("not found mbusID[%d]", mbusId)
 		}
 		clt.mbusMutex.Unlock()
 	}
 	return err
 }
 
-// NotifyDemand sends Typed Demand to Server
+// NotifyDemand sends Typed Demand to Server (large Cdata is sent in chunks)
 func (clt *SXServiceClient) NotifyDemand(dmo *DemandOpts) (uint64, error) {
 	id := GenerateIntID()
 	ts := ptypes.TimestampNow()
-	dm := api.Demand{
-		Id:          id,
-		SenderId:    uint64(clt.ClientID),
-		ChannelType: clt.ChannelType,
-		DemandName:  dmo.Name,
-		Ts:          ts,
-		ArgJson:     dmo.JSON,
-		Cdata:       dmo.Cdata,
-	}
 	//	switch clt.ChannelType {
 	//	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
-	defer cancel()
-
-	_, err := clt.SXClient.Client.NotifyDemand(ctx, &dm)
+	err := forEachChunk(id, dmo.Cdata, func(mid uint64, cdata *api.Content) error {
+		dm := api.Demand{
+			Id:          mid,
+			SenderId:    uint64(clt.ClientID),
+			ChannelType: clt.ChannelType,
+			DemandName:  dmo.Name,
+			Ts:          ts,
+			ArgJson:     dmo.JSON,
+			Cdata:       cdata,
+		}
+		return clt.publish(context.Background(), func(ctx context.Context) (*api.Response, error) {
+			return clt.SXClient.Client.NotifyDemand(ctx, &dm)
+		})
+	})
 
 	//	resp, err := clt.Client.NotifyDemand(ctx, &dm)
 	if err != nil {
-		log.Printf("%v.NotifyDemand err %v", clt, err)
+		logger.Warnf("%v.NotifyDemand err %v", clt, err)
 		return 0, err
 	}
 	//	log.Println(resp)
@@ -850,27 +1158,29 @@ func (clt *SXServiceClient) NotifyDemand(dmo *DemandOpts) (uint64, error) {
 	return id, nil
 }
 
-// NotifySupply sends Typed Supply to Server
+// NotifySupply sends Typed Supply to Server (large Cdata is sent in chunks)
 func (clt *SXServiceClient) NotifySupply(smo *SupplyOpts) (uint64, error) {
 	id := GenerateIntID()
 	ts := ptypes.TimestampNow()
-	dm := api.Supply{
-		Id:          id,
-		SenderId:    uint64(clt.ClientID),
-		ChannelType: clt.ChannelType,
-		SupplyName:  smo.Name,
-		Ts:          ts,
-		ArgJson:     smo.JSON,
-		Cdata:       smo.Cdata,
-	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
-	defer cancel()
 	//	resp , err := clt.Client.NotifySupply(ctx, &dm)
 
-	_, err := clt.SXClient.Client.NotifySupply(ctx, &dm)
+	err := forEachChunk(id, smo.Cdata, func(mid uint64, cdata *api.Content) error {
+		dm := api.Supply{
+			Id:          mid,
+			SenderId:    uint64(clt.ClientID),
+			ChannelType: clt.ChannelType,
+			SupplyName:  smo.Name,
+			Ts:          ts,
+			ArgJson:     smo.JSON,
+			Cdata:       cdata,
+		}
+		return clt.publish(context.Background(), func(ctx context.Context) (*api.Response, error) {
+			return clt.SXClient.Client.NotifySupply(ctx, &dm)
+		})
+	})
 	if err != nil {
-		log.Printf("Error for sending:NotifySupply to  Synerex Server as %v ", err)
+		logger.Warnf("Error for sending:NotifySupply to  Synerex Server as %v ", err)
 		return 0, err
 	}
 	//	log.Println("RegiterSupply:", smo, resp)
@@ -887,19 +1197,26 @@ func (clt *SXServiceClient) Confirm(id IDType, pid IDType) error {
 		ChannelType: clt.ChannelType,
 		MbusId:      uint64(id),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), MSG_TIME_OUT*time.Second)
+	ctx, cancel := context.WithTimeout(receivedTraces.context(context.Background(), uint64(id)), MSG_TIME_OUT*time.Second)
 	defer cancel()
 	resp, err := clt.SXClient.Client.Confirm(ctx, tg)
 	if err != nil {
-		log.Printf("%v Confirm Failier %v %v", clt, err, resp)
+		logger.Warnf("%v Confirm Failier %v %v", clt, err, resp)
 		return err
 	}
+	receivedTraces.add(uint64(id), TraceParentFromContext(ctx)) // for mbus messages
+	clt.mbusMutex.Lock()
 	clt.MbusIDs = append(clt.MbusIDs, id)
+	clt.mbusMutex.Unlock()
 	//	log.Println("Confirm Success:", resp)
 
 	// nodestate may not work v0.5.0.
-	//	clt.NI.nodeState.selectDemand(uint64(id))
-	clt.NI.nodeState.selectSupply(uint64(pid))
+	// pid is proposed supply (SelectSupply) or proposed demand (SelectDemand)
+	if clt.NI.nodeState.proposedDemandIndex(uint64(pid)) >= 0 {
+		clt.NI.nodeState.selectDemand(uint64(pid))
+	} else {
+		clt.NI.nodeState.selectSupply(uint64(pid))
+	}
 
 	return nil
 }
@@ -910,7 +1227,7 @@ func reconnectClient(client *SXServiceClient, servAddr string, mu *sync.Mutex) {
 	mu.Lock()
 	if client.SXClient != nil {
 		client.SXClient = nil
-		log.Printf("Client reset \n")
+		logger.Infof("Client reset ")
 	}
 	mu.Unlock()
 	time.Sleep(RECONNECT_WAIT * time.Second) // wait 5 seconds to reconnect
@@ -918,14 +1235,14 @@ func reconnectClient(client *SXServiceClient, servAddr string, mu *sync.Mutex) {
 	if client.SXClient == nil && servAddr != "" {
 		newClt := GrpcConnectServer(servAddr)
 		if newClt != nil {
-			log.Printf("Reconnect server [%s]\n", servAddr)
+			logger.Infof("Reconnect server [%s]", servAddr)
 			client.SXClient = newClt
 			return
 		} else {
-			log.Printf("Can't re-connect server..")
+			logger.Warnf("Can't re-connect server..")
 		}
 	} else { // someone may connect!
-		log.Printf("Use reconnected client.. \n")
+		logger.Infof("Use reconnected client.. ")
 	}
 	mu.Unlock()
 }
@@ -944,9 +1261,9 @@ func SubscribeDemand(client *SXServiceClient, dmcb func(*SXServiceClient, *api.D
 	var servAddr string = ""
 	for *loopFlag { // make it continuously working..
 		err := client.SubscribeDemand(ctx, dmcb)
-		log.Printf("Error on subscribe. %v", err)
+		logger.Warnf("Error on subscribe. %v", err)
 		if client.SXClient == nil {
-			log.Printf("Already reconnect from other loop.")
+			logger.Infof("Already reconnect from other loop.")
 		} else {
 			servAddr = client.SXClient.ServerAddress
 		}
@@ -968,9 +1285,9 @@ func SubscribeSupply(client *SXServiceClient, spcb func(*SXServiceClient, *api.S
 	var servAddr string = ""
 	for *loopFlag { // make it continuously working..
 		client.SubscribeSupply(ctx, spcb)
-		log.Printf("Error on subscribe.")
+		logger.Warnf("Error on subscribe.")
 		if client.SXClient == nil {
-			log.Printf("Already reconnect from other loop.")
+			logger.Infof("Already reconnect from other loop.")
 		} else {
 			servAddr = client.SXClient.ServerAddress
 		}
@@ -988,12 +1305,12 @@ func generateDemandCallback(ndcb func(*SXServiceClient, *api.Demand), sscb func(
 			ndcb(clt, dm)
 		} else {
 			//
-			log.Printf("SelectSupply: %d: %v", dm.TargetId, clt.NI.nodeState.ProposedSupply)
+			logger.Debugf("SelectSupply: %d: %v", dm.TargetId, clt.NI.nodeState.ProposedSupply)
 			pos := clt.NI.nodeState.proposedSupplyIndex(dm.TargetId)
 			if pos >= 0 { // it is proposed by me.
 				sscb(clt, dm)
 			} else {
-				log.Printf("sxutil:Other Proposal? %v", dm.TargetId)
+				logger.Debugf("sxutil:Other Proposal? %v", dm.TargetId)
 			}
 		}
 	}
@@ -1020,7 +1337,7 @@ func demandHandlerCallback(dh DemandHandler) func(*SXServiceClient, *api.Demand)
 			}
 		} else { // select supply
 			//
-			log.Printf("SelectSupply: %d: %v", dm.TargetId, clt.NI.nodeState.ProposedSupply)
+			logger.Debugf("SelectSupply: %d: %v", dm.TargetId, clt.NI.nodeState.ProposedSupply)
 			pos := clt.NI.nodeState.proposedSupplyIndex(dm.TargetId)
 			if pos >= 0 { // it is proposed by me.
 				if dh.OnSelectSupply(clt, dm) { // if OK. send Confirm
@@ -1030,7 +1347,7 @@ func demandHandlerCallback(dh DemandHandler) func(*SXServiceClient, *api.Demand)
 					// may remove proposal.
 				}
 			} else {
-				log.Printf("sxutil:Other Proposal? %v", dm.TargetId)
+				logger.Debugf("sxutil:Other Proposal? %v", dm.TargetId)
 			}
 		}
 	}
@@ -1044,3 +1361,64 @@ func RegisterDemandHandler(client *SXServiceClient, dh DemandHandler) (*sync.Mut
 	go SubscribeDemand(client, dmcb, &mu, &loopFlag) // loop
 	return &mu, &loopFlag
 }
+
+// composit callback with selection checking for supply
+func generateSupplyCallback(nscb func(*SXServiceClient, *api.Supply), sdcb func(*SXServiceClient, *api.Supply)) func(*SXServiceClient, *api.Supply) {
+
+	return func(clt *SXServiceClient, sp *api.Supply) {
+		if sp.TargetId == 0 {
+			nscb(clt, sp)
+		} else {
+			//
+			logger.Debugf("SelectDemand: %d: %v", sp.TargetId, clt.NI.nodeState.ProposedDemand)
+			pos := clt.NI.nodeState.proposedDemandIndex(sp.TargetId)
+			if pos >= 0 { // it is proposed by me.
+				sdcb(clt, sp)
+			} else {
+				logger.Debugf("sxutil:Other Proposal? %v", sp.TargetId)
+			}
+		}
+	}
+}
+
+// Composit Subscriber for supply (nscb = notify supply callback, sdcb = selectdemand cb)
+func CombinedSubscribeSupply(client *SXServiceClient, nscb func(*SXServiceClient, *api.Supply), sdcb func(*SXServiceClient, *api.Supply)) (*sync.Mutex, *bool) {
+	var mu sync.Mutex
+	loopFlag := true
+	spcb := generateSupplyCallback(nscb, sdcb)
+	go SubscribeSupply(client, spcb, &mu, &loopFlag) // loop
+	return &mu, &loopFlag
+}
+
+// composit callback with SupplyHandler
+func supplyHandlerCallback(sh SupplyHandler) func(*SXServiceClient, *api.Supply) {
+	return func(clt *SXServiceClient, sp *api.Supply) {
+		if sp.TargetId == 0 { // notify supply
+			dmo := sh.OnNotifySupply(clt, sp)
+			if dmo != nil { // register propose Id.
+				dmo.Target = sp.Id // need to set!
+				clt.ProposeDemand(dmo)
+			}
+		} else { // select demand
+			logger.Debugf("SelectDemand: %d: %v", sp.TargetId, clt.NI.nodeState.ProposedDemand)
+			pos := clt.NI.nodeState.proposedDemandIndex(sp.TargetId)
+			if pos >= 0 { // it is proposed by me.
+				if sh.OnSelectDemand(clt, sp) { // if OK. send Confirm
+					err := clt.Confirm(IDType(sp.Id), IDType(sp.TargetId)) // send confirm to sender!
+					sh.OnConfirmResponse(clt, IDType(sp.Id), err)
+				}
+			} else {
+				logger.Debugf("sxutil:Other Proposal? %v", sp.TargetId)
+			}
+		}
+	}
+}
+
+// Register SupplyHandler
+func RegisterSupplyHandler(client *SXServiceClient, sh SupplyHandler) (*sync.Mutex, *bool) {
+	var mu sync.Mutex
+	loopFlag := true
+	spcb := supplyHandlerCallback(sh)
+	go SubscribeSupply(client, spcb, &mu, &loopFlag) // loop
+	return &mu, &loopFlag
+}
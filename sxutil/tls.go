@@ -0,0 +1,179 @@
+package sxutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLS support for gRPC connections (synerex server, nodeserv, gateways and cli)
+// Certificates can be specified by flags (TLSFlags) or environment variables.
+//  SX_TLS_CA          : CA certificate file for verifying the peer
+//  SX_TLS_CERT        : certificate file of this node (server certificate / client certificate for mTLS)
+//  SX_TLS_KEY         : private key file of the certificate
+//  SX_TLS_SERVER_NAME : server name for verifying server certificate (default: host of the address)
+//  SX_TLS_CLIENT      : "true" for connecting servers with TLS
+//  SX_TLS_CLIENT_AUTH : "true" for requiring client certificate on servers (mutual TLS)
+// Servers listen with TLS if certificate and key are given, and connections use TLS only with SX_TLS_CLIENT,
+// so a synerex server can serve TLS while connecting node server without TLS.
+
+// environment variables and flags of TLS options
+const (
+	envTLSCA         = "SX_TLS_CA"
+	envTLSCert       = "SX_TLS_CERT"
+	envTLSKey        = "SX_TLS_KEY"
+	envTLSServerName = "SX_TLS_SERVER_NAME"
+	envTLSClient     = "SX_TLS_CLIENT"
+	envTLSClientAuth = "SX_TLS_CLIENT_AUTH"
+)
+
+// TLSOption keeps certificate files for TLS
+type TLSOption struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	Client     bool // use TLS for connections
+	ClientAuth bool
+}
+
+var tlsOpt = NewTLSOptionFromEnv() // used for connecting nodeserv and synerex server
+
+// NewTLSOptionFromEnv creates TLSOption from environment variables
+func NewTLSOptionFromEnv() *TLSOption {
+	return &TLSOption{
+		CAFile:     os.Getenv(envTLSCA),
+		CertFile:   os.Getenv(envTLSCert),
+		KeyFile:    os.Getenv(envTLSKey),
+		ServerName: os.Getenv(envTLSServerName),
+		Client:     os.Getenv(envTLSClient) == "true",
+		ClientAuth: os.Getenv(envTLSClientAuth) == "true",
+	}
+}
+
+// TLSFlags defines TLS flags (default from environment variables). Call before flag.Parse.
+// Set the returned option with SetTLSOption after flag.Parse.
+func TLSFlags() *TLSOption {
+	opt := NewTLSOptionFromEnv()
+	flag.StringVar(&opt.CAFile, "tls_ca", opt.CAFile, "CA certificate file for TLS")
+	flag.StringVar(&opt.CertFile, "tls_cert", opt.CertFile, "Certificate file for TLS")
+	flag.StringVar(&opt.KeyFile, "tls_key", opt.KeyFile, "Private key file for TLS")
+	flag.StringVar(&opt.ServerName, "tls_server_name", opt.ServerName, "Server name for verifying TLS server certificate")
+	flag.BoolVar(&opt.Client, "tls_client", opt.Client, "Connect servers with TLS (client certificate for mutual TLS is -tls_cert)")
+	flag.BoolVar(&opt.ClientAuth, "tls_client_auth", opt.ClientAuth, "Require and verify client certificate (mutual TLS)")
+	return opt
+}
+
+// SetTLSOption sets TLS option for connections by sxutil
+func SetTLSOption(opt *TLSOption) {
+	if opt == nil {
+		opt = &TLSOption{}
+	}
+	tlsOpt = opt
+}
+
+// GetTLSOption returns current TLS option
+func GetTLSOption() *TLSOption {
+	return tlsOpt
+}
+
+// IsClientEnabled returns true if connections should use TLS
+func (opt *TLSOption) IsClientEnabled() bool {
+	return opt.Client
+}
+
+// IsServerEnabled returns true if servers should listen with TLS
+func (opt *TLSOption) IsServerEnabled() bool {
+	return opt.CertFile != "" && opt.KeyFile != ""
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate in CA file %s", caFile)
+	}
+	return pool, nil
+}
+
+// ClientTLSConfig creates tls.Config for connecting servers
+func (opt *TLSOption) ClientTLSConfig() (*tls.Config, error) {
+	conf := &tls.Config{
+		ServerName: opt.ServerName,
+	}
+	if opt.CAFile != "" { // otherwise system CA
+		pool, err := loadCertPool(opt.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootCAs = pool
+	}
+	if opt.CertFile != "" { // client certificate for mutual TLS
+		cert, err := tls.LoadX509KeyPair(opt.CertFile, opt.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+// ServerTLSConfig creates tls.Config for gRPC servers
+func (opt *TLSOption) ServerTLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(opt.CertFile, opt.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	if opt.ClientAuth {
+		if opt.CAFile == "" {
+			return nil, fmt.Errorf("CA file is required for client authentication")
+		}
+		pool, err := loadCertPool(opt.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+// DialOption returns grpc.DialOption for the TLS option (insecure if TLS is not specified)
+func (opt *TLSOption) DialOption() (grpc.DialOption, error) {
+	if !opt.IsClientEnabled() {
+		return grpc.WithInsecure(), nil
+	}
+	conf, err := opt.ClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(conf)), nil
+}
+
+// ServerOptions returns grpc.ServerOption for the TLS option (empty if TLS is not specified)
+func (opt *TLSOption) ServerOptions() ([]grpc.ServerOption, error) {
+	if !opt.IsServerEnabled() {
+		if opt.ClientAuth {
+			return nil, fmt.Errorf("certificate and key are required for client authentication")
+		}
+		return nil, nil
+	}
+	conf, err := opt.ServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("TLS enabled with certificate %s (client auth: %v)", opt.CertFile, opt.ClientAuth)
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(conf))}, nil
+}
@@ -0,0 +1,46 @@
+package sxutil
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTLSOptionFromEnv(t *testing.T) {
+	for _, env := range []string{envTLSCA, envTLSCert, envTLSKey, envTLSServerName, envTLSClient, envTLSClientAuth} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv(envTLSCA, "ca.pem")
+	os.Setenv(envTLSCert, "cert.pem")
+	os.Setenv(envTLSKey, "key.pem")
+	os.Setenv(envTLSServerName, "sx")
+	os.Setenv(envTLSClient, "")
+	os.Setenv(envTLSClientAuth, "true")
+	opt := NewTLSOptionFromEnv()
+	if opt.CAFile != "ca.pem" || opt.CertFile != "cert.pem" || opt.KeyFile != "key.pem" || opt.ServerName != "sx" || !opt.ClientAuth {
+		t.Errorf("option %+v", opt)
+	}
+	// serving certificate does not enable TLS for connections
+	if opt.IsClientEnabled() || !opt.IsServerEnabled() {
+		t.Errorf("client %v, server %v", opt.IsClientEnabled(), opt.IsServerEnabled())
+	}
+	os.Setenv(envTLSClient, "true")
+	if !NewTLSOptionFromEnv().IsClientEnabled() {
+		t.Error("client TLS is not enabled")
+	}
+}
+
+func TestTLSOptionDisabled(t *testing.T) {
+	opt := &TLSOption{CAFile: "ca.pem"}
+	if _, err := opt.DialOption(); err != nil { // insecure without loading files
+		t.Error(err)
+	}
+	if sopts, err := opt.ServerOptions(); err != nil || len(sopts) != 0 {
+		t.Errorf("server options %v, %v", sopts, err)
+	}
+	if _, err := (&TLSOption{ClientAuth: true}).ServerOptions(); err == nil {
+		t.Error("client auth without certificate is accepted")
+	}
+	if _, err := (&TLSOption{Client: true, CAFile: "no-such-ca.pem"}).DialOption(); err == nil {
+		t.Error("missing CA file is accepted")
+	}
+}
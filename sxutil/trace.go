@@ -0,0 +1,487 @@
+package sxutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/synerex/synerex_api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// OpenTelemetry style distributed tracing.
+// Trace context is propagated with W3C "traceparent" in gRPC metadata, and with trace_parent
+// field of messages delivered by the server. Spans are exported in OTLP/JSON to a file
+// (one ExportTraceServiceRequest per line) or to an OTLP/HTTP collector (e.g. http://localhost:4318).
+
+// TraceMetadataKey is the gRPC metadata key for trace context
+const TraceMetadataKey = "traceparent"
+
+// span kinds of OTLP
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+	SpanKindProducer = 4
+	SpanKindConsumer = 5
+)
+
+const (
+	traceBatchSize     = 128
+	traceFlushInterval = time.Second
+	traceQueueSize     = 4096
+)
+
+type traceID [16]byte
+type spanID [8]byte
+
+// spanContext is an identity of span (local or remote)
+type spanContext struct {
+	trace traceID
+	span  spanID
+}
+
+func (sc spanContext) traceParent() string {
+	return "00-" + hex.EncodeToString(sc.trace[:]) + "-" + hex.EncodeToString(sc.span[:]) + "-01"
+}
+
+// parseTraceParent parses W3C traceparent "00-<trace id>-<span id>-<flags>"
+func parseTraceParent(tp string) (spanContext, bool) {
+	var sc spanContext
+	items := strings.Split(tp, "-")
+	if len(items) != 4 || len(items[1]) != 32 || len(items[2]) != 16 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.trace[:], []byte(items[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.span[:], []byte(items[2])); err != nil {
+		return sc, false
+	}
+	return sc, sc.trace != traceID{}
+}
+
+type spanAttr struct {
+	key   string
+	value interface{}
+}
+
+// Span is a traced operation. Methods of nil Span do nothing (tracing is disabled).
+type Span struct {
+	sc     spanContext
+	parent spanID
+	name   string
+	kind   int
+	start  time.Time
+	end    time.Time
+	attrs  []spanAttr
+	err    string
+	mu     sync.Mutex
+}
+
+type tracer struct {
+	service string
+	queue   chan *Span
+	flushed chan chan struct{}
+	export  func(data []byte) error
+}
+
+var (
+	defaultTracer *tracer
+	tracerMu      sync.RWMutex
+	traceLog      = logger.Component("trace")
+)
+
+type traceCtxKey struct{}
+
+func getTracer() *tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+	return defaultTracer
+}
+
+// TracingEnabled returns true if StartTracing is called
+func TracingEnabled() bool {
+	return getTracer() != nil
+}
+
+// StartTracing starts exporting spans of service to export target.
+// export is a file path (or "file:<path>") or an OTLP/HTTP endpoint ("http://host:4318").
+func StartTracing(service, export string) error {
+	tr := &tracer{
+		service: service,
+		queue:   make(chan *Span, traceQueueSize),
+		flushed: make(chan chan struct{}),
+	}
+	if strings.HasPrefix(export, "http://") || strings.HasPrefix(export, "https://") {
+		url := strings.TrimSuffix(export, "/")
+		if !strings.HasSuffix(url, "/v1/traces") {
+			url += "/v1/traces"
+		}
+		tr.export = func(data []byte) error {
+			resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode/100 != 2 {
+				return fmt.Errorf("collector returns %s", resp.Status)
+			}
+			return nil
+		}
+	} else {
+		fp, err := os.OpenFile(strings.TrimPrefix(export, "file:"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		tr.export = func(data []byte) error {
+			_, err := fp.Write(append(data, '\n'))
+			return err
+		}
+	}
+	tracerMu.Lock()
+	defaultTracer = tr
+	tracerMu.Unlock()
+	go tr.run()
+	RegisterDeferFunction(FlushTracing)
+	traceLog.Infof("Start tracing of %s to %s", service, export)
+	return nil
+}
+
+// FlushTracing exports queued spans
+func FlushTracing() {
+	tr := getTracer()
+	if tr == nil {
+		return
+	}
+	done := make(chan struct{})
+	select {
+	case tr.flushed <- done:
+		<-done
+	case <-time.After(traceFlushInterval):
+	}
+}
+
+func (tr *tracer) run() {
+	ticker := time.NewTicker(traceFlushInterval)
+	defer ticker.Stop()
+	var spans []*Span
+	for {
+		var done chan struct{}
+		select {
+		case sp := <-tr.queue:
+			spans = append(spans, sp)
+			if len(spans) < traceBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case done = <-tr.flushed:
+			for len(tr.queue) > 0 {
+				spans = append(spans, <-tr.queue)
+			}
+		}
+		if len(spans) > 0 {
+			if err := tr.export(tr.encode(spans)); err != nil {
+				traceLog.Warnf("Can't export %d spans: %v", len(spans), err)
+			}
+			spans = spans[:0]
+		}
+		if done != nil {
+			close(done)
+		}
+	}
+}
+
+func newID(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		traceLog.Errorf("Can't generate trace id: %v", err)
+	}
+}
+
+// StartSpan starts a span with parent in ctx (returns nil Span if tracing is disabled)
+func StartSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	return StartSpanAt(ctx, name, kind, time.Now())
+}
+
+// StartSpanAt starts a span at the start time
+func StartSpanAt(ctx context.Context, name string, kind int, start time.Time) (context.Context, *Span) {
+	if !TracingEnabled() {
+		return ctx, nil
+	}
+	sp := &Span{name: name, kind: kind, start: start}
+	if parent, ok := ctx.Value(traceCtxKey{}).(spanContext); ok {
+		sp.sc.trace = parent.trace
+		sp.parent = parent.span
+	} else {
+		newID(sp.sc.trace[:])
+	}
+	newID(sp.sc.span[:])
+	return context.WithValue(ctx, traceCtxKey{}, sp.sc), sp
+}
+
+// ContextWithTraceParent returns context with remote parent from W3C traceparent
+func ContextWithTraceParent(ctx context.Context, tp string) context.Context {
+	if sc, ok := parseTraceParent(tp); ok {
+		return context.WithValue(ctx, traceCtxKey{}, sc)
+	}
+	return ctx
+}
+
+// TraceParentFromContext returns W3C traceparent of the current span in ctx
+func TraceParentFromContext(ctx context.Context) string {
+	if sc, ok := ctx.Value(traceCtxKey{}).(spanContext); ok {
+		return sc.traceParent()
+	}
+	return ""
+}
+
+// TraceParent returns W3C traceparent of the span
+func (sp *Span) TraceParent() string {
+	if sp == nil {
+		return ""
+	}
+	return sp.sc.traceParent()
+}
+
+// SetAttr sets attribute of the span (string, bool, integer or float value)
+func (sp *Span) SetAttr(key string, value interface{}) {
+	if sp == nil {
+		return
+	}
+	sp.mu.Lock()
+	sp.attrs = append(sp.attrs, spanAttr{key, value})
+	sp.mu.Unlock()
+}
+
+// SetError marks the span as error
+func (sp *Span) SetError(err error) {
+	if sp == nil || err == nil {
+		return
+	}
+	sp.mu.Lock()
+	sp.err = err.Error()
+	sp.mu.Unlock()
+}
+
+// End finishes the span and queues it for export
+func (sp *Span) End() {
+	if sp == nil {
+		return
+	}
+	tr := getTracer()
+	if tr == nil {
+		return
+	}
+	sp.end = time.Now()
+	select {
+	case tr.queue <- sp:
+	default: // drop span if exporter is slow
+	}
+}
+
+// OTLP/JSON encoding
+type otlpValue map[string]interface{}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []otlpAttr `json:"attributes,omitempty"`
+	Status            otlpStatus `json:"status"`
+}
+
+func toOtlpValue(v interface{}) otlpValue {
+	switch x := v.(type) {
+	case string:
+		return otlpValue{"stringValue": x}
+	case bool:
+		return otlpValue{"boolValue": x}
+	case int:
+		return otlpValue{"intValue": strconv.FormatInt(int64(x), 10)}
+	case int32:
+		return otlpValue{"intValue": strconv.FormatInt(int64(x), 10)}
+	case int64:
+		return otlpValue{"intValue": strconv.FormatInt(x, 10)}
+	case uint32:
+		return otlpValue{"intValue": strconv.FormatUint(uint64(x), 10)}
+	case uint64:
+		return otlpValue{"intValue": strconv.FormatUint(x, 10)}
+	case float64:
+		return otlpValue{"doubleValue": x}
+	default:
+		return otlpValue{"stringValue": fmt.Sprint(x)}
+	}
+}
+
+func (tr *tracer) encode(spans []*Span) []byte {
+	ospans := make([]otlpSpan, 0, len(spans))
+	for _, sp := range spans {
+		sp.mu.Lock()
+		ospan := otlpSpan{
+			TraceID:           hex.EncodeToString(sp.sc.trace[:]),
+			SpanID:            hex.EncodeToString(sp.sc.span[:]),
+			Name:              sp.name,
+			Kind:              sp.kind,
+			StartTimeUnixNano: strconv.FormatInt(sp.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(sp.end.UnixNano(), 10),
+		}
+		if sp.parent != (spanID{}) {
+			ospan.ParentSpanID = hex.EncodeToString(sp.parent[:])
+		}
+		for _, a := range sp.attrs {
+			ospan.Attributes = append(ospan.Attributes, otlpAttr{a.key, toOtlpValue(a.value)})
+		}
+		if sp.err != "" {
+			ospan.Status = otlpStatus{Code: 2, Message: sp.err} // STATUS_CODE_ERROR
+		}
+		sp.mu.Unlock()
+		ospans = append(ospans, ospan)
+	}
+	req := map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []otlpAttr{{"service.name", toOtlpValue(tr.service)}},
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "synerex"},
+				"spans": ospans,
+			}},
+		}},
+	}
+	data, _ := json.Marshal(req)
+	return data
+}
+
+// gRPC interceptors for client spans with traceparent metadata
+
+func traceUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !TracingEnabled() {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	ctx, sp := StartSpan(ctx, method, SpanKindClient)
+	ctx = metadata.AppendToOutgoingContext(ctx, TraceMetadataKey, sp.TraceParent())
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		sp.SetAttr("rpc.grpc.status_code", int(status.Code(err)))
+		sp.SetError(err)
+	}
+	sp.End()
+	return err
+}
+
+func traceStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if tp := TraceParentFromContext(ctx); tp != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, TraceMetadataKey, tp)
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// ContextWithGatewayMsg returns context with trace parent of the message in gm (for gateways)
+func ContextWithGatewayMsg(ctx context.Context, gm *api.GatewayMsg) context.Context {
+	var tp string
+	switch {
+	case gm.GetDemand() != nil:
+		tp = gm.GetDemand().GetTraceParent()
+	case gm.GetSupply() != nil:
+		tp = gm.GetSupply().GetTraceParent()
+	case gm.GetTarget() != nil:
+		tp = gm.GetTarget().GetTraceParent()
+	case gm.GetMbusMsg() != nil:
+		tp = gm.GetMbusMsg().GetTraceParent()
+	}
+	return ContextWithTraceParent(ctx, tp)
+}
+
+// TraceParentFromIncoming returns context with remote parent in incoming gRPC metadata
+func TraceParentFromIncoming(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if tps := md.Get(TraceMetadataKey); len(tps) > 0 {
+			return ContextWithTraceParent(ctx, tps[0])
+		}
+	}
+	return ctx
+}
+
+// received trace contexts for linking replies (propose/select/confirm/mbus) to the received message
+const traceLinkSize = 4096
+
+type traceLinks struct {
+	parents map[uint64]string
+	order   []uint64
+	mu      sync.Mutex
+}
+
+var receivedTraces = &traceLinks{parents: make(map[uint64]string)}
+
+// add keeps trace parent of the message id (old ones are removed)
+func (tl *traceLinks) add(id uint64, tp string) {
+	if tp == "" || !TracingEnabled() {
+		return
+	}
+	tl.mu.Lock()
+	if _, ok := tl.parents[id]; !ok {
+		tl.order = append(tl.order, id)
+		if len(tl.order) > traceLinkSize {
+			delete(tl.parents, tl.order[0])
+			tl.order = tl.order[1:]
+		}
+	}
+	tl.parents[id] = tp
+	tl.mu.Unlock()
+}
+
+// context returns context with trace parent of the message id (if ctx has no trace)
+func (tl *traceLinks) context(ctx context.Context, id uint64) context.Context {
+	if id == 0 || !TracingEnabled() || TraceParentFromContext(ctx) != "" {
+		return ctx
+	}
+	tl.mu.Lock()
+	tp := tl.parents[id]
+	tl.mu.Unlock()
+	return ContextWithTraceParent(ctx, tp)
+}
+
+// traceReceived starts consumer span for the received message and keeps it for replies
+func traceReceived(name string, id uint64, tp string) *Span {
+	if tp == "" || !TracingEnabled() {
+		return nil
+	}
+	_, sp := StartSpan(ContextWithTraceParent(context.Background(), tp), name, SpanKindConsumer)
+	sp.SetAttr("synerex.msg_id", id)
+	receivedTraces.add(id, sp.TraceParent())
+	return sp
+}
+
+// startTracingFromEnv starts tracing with SX_TRACE (export target) for providers
+func startTracingFromEnv(service string) {
+	if env := os.Getenv("SX_TRACE"); env != "" && !TracingEnabled() {
+		if err := StartTracing(service, env); err != nil {
+			traceLog.Errorf("Can't start tracing: %v", err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package sxutil
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/synerex/synerex_api"
+)
+
+const testTraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+func TestParseTraceParent(t *testing.T) {
+	sc, ok := parseTraceParent(testTraceParent)
+	if !ok || sc.traceParent() != testTraceParent {
+		t.Errorf("parse %q = %v %q", testTraceParent, ok, sc.traceParent())
+	}
+	for _, tp := range []string{"", "00-abc-def-01", "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01"} {
+		if _, ok := parseTraceParent(tp); ok {
+			t.Errorf("parse %q is accepted", tp)
+		}
+	}
+}
+
+func TestContextWithGatewayMsg(t *testing.T) {
+	msgs := map[string]*api.GatewayMsg{
+		"demand": {MsgOneof: &api.GatewayMsg_Demand{Demand: &api.Demand{TraceParent: testTraceParent}}},
+		"supply": {MsgOneof: &api.GatewayMsg_Supply{Supply: &api.Supply{TraceParent: testTraceParent}}},
+		"target": {MsgOneof: &api.GatewayMsg_Target{Target: &api.Target{TraceParent: testTraceParent}}},
+		"mbus":   {MsgOneof: &api.GatewayMsg_MbusMsg{MbusMsg: &api.MbusMsg{TraceParent: testTraceParent}}},
+	}
+	for kind, gm := range msgs {
+		ctx := ContextWithGatewayMsg(context.Background(), gm)
+		if tp := TraceParentFromContext(ctx); tp != testTraceParent {
+			t.Errorf("%s: trace parent %q", kind, tp)
+		}
+	}
+	untraced := &api.GatewayMsg{MsgOneof: &api.GatewayMsg_Supply{Supply: &api.Supply{}}}
+	if tp := TraceParentFromContext(ContextWithGatewayMsg(context.Background(), untraced)); tp != "" {
+		t.Errorf("message without trace parent: %q", tp)
+	}
+}
+
+func TestStartSpanDisabled(t *testing.T) {
+	if TracingEnabled() {
+		t.Skip("tracing is enabled by environment")
+	}
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent)
+	sctx, span := StartSpan(ctx, "gateway.forward", SpanKindInternal)
+	if span != nil || TraceParentFromContext(sctx) != testTraceParent {
+		t.Errorf("disabled span %v, trace parent %q", span, TraceParentFromContext(sctx))
+	}
+	// methods of nil span are no-op
+	span.SetAttr("synerex.msg_type", "SUPPLY")
+	span.SetError(context.Canceled)
+	span.End()
+}